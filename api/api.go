@@ -0,0 +1,13 @@
+// Package api defines the contract of the REST API shared by the server and
+// its clients: the request and response bodies, the problem details of
+// errors and the WebSocket messages. It embeds the OpenAPI document, which is
+// the source of truth: requests are validated against it and tests check
+// that it covers every route. The package must not import the server.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3.1 document, in YAML.
+//
+//go:embed openapi.yaml
+var Spec []byte
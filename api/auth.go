@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+const (
+	// CSRFCookie holds the CSRF token of the session, readable by scripts
+	// of the front-end so they can echo it in CSRFHeader.
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// RegisterBody defines the request body for the /register endpoint.
+type RegisterBody struct {
+	Name     string `json:"name" binding:"required,min=4" example:"John Doe"`          // User's full name
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"` // User's email
+	Password string `json:"password" binding:"required,min=8" example:"password123"`   // User's password
+}
+
+// LoginBody defines the request body for the /login endpoint.
+type LoginBody struct {
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"` // User's email
+	Password string `json:"password" binding:"required,min=8" example:"password123"`   // User's password
+}
+
+// TokenResponse defines the response of the /login and /refresh endpoints.
+// Clients which can't keep cookies send the token in the Authorization header
+// as a bearer token.
+type TokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2023-12-31T23:59:59Z"`
+}
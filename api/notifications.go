@@ -0,0 +1,6 @@
+package api
+
+// UpdateNotificationBody defines the request body for the /notifications/{notificationId} endpoint.
+type UpdateNotificationBody struct {
+	Read bool `json:"read" example:"true"` // Whether the notification was read
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// TypePrefix prefixes the code of an error to build its problem type URI.
+const TypePrefix = "urn:hyper-todo:problem:"
+
+// ResponseError defines a standard error response following RFC 7807.
+// Code is stable and meant to be matched by clients, Detail is for humans.
+type ResponseError struct {
+	Type     string       `json:"type" example:"urn:hyper-todo:problem:task_not_found"`
+	Title    string       `json:"title" example:"Not Found"`
+	Status   int          `json:"status" example:"404"`
+	Code     string       `json:"code" example:"task_not_found"`
+	Detail   string       `json:"detail" example:"task was not found"`
+	Instance string       `json:"instance,omitempty" example:"urn:hyper-todo:request:4f1c2a9e0b7d8c3a"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why a single field of the request was rejected.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Code    string `json:"code" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("[%d] %s: %s", e.Status, e.Code, e.Detail)
+}
+
+// WithInstance returns a copy of the error tied to the given instance.
+func (e *ResponseError) WithInstance(instance string) *ResponseError {
+	c := *e
+	c.Instance = instance
+	return &c
+}
+
+func NewResponseError(status int, code, detail string) *ResponseError {
+	return &ResponseError{
+		Type:   TypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
@@ -0,0 +1,13 @@
+package api
+
+// CreateTaskBody defines the request body for the /tasks endpoint.
+type CreateTaskBody struct {
+	Name        string `json:"name" example:"Eat"`                      // Name of the task
+	Description string `json:"description" example:"Eat the pizza"`     // Description of the task
+	Deadline    string `json:"deadline" example:"2023-12-31T23:59:59Z"` // Deadline for the task (RFC3339 format)
+}
+
+// RemindersBody defines the request and response body for the /tasks/{taskId}/reminders endpoint.
+type RemindersBody struct {
+	Offsets []string `json:"offsets" example:"24h,1h"` // How long before the deadline reminders are sent, in Go duration format
+}
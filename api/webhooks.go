@@ -0,0 +1,9 @@
+package api
+
+import "github.com/krau5/hyper-todo/domain"
+
+// CreateWebhookBody defines the request body for the /webhooks endpoint.
+type CreateWebhookBody struct {
+	URL        string             `json:"url" binding:"required" example:"https://example.com/hooks/tasks"` // Endpoint receiving the deliveries
+	EventTypes []domain.EventType `json:"eventTypes" example:"task.created,task.updated"`                   // Event types to deliver, every event if empty
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+// Client message types.
+const (
+	WSTypeSubscribe   = "subscribe"
+	WSTypeUnsubscribe = "unsubscribe"
+	WSTypeTaskCreate  = "task.create"
+	WSTypeTaskUpdate  = "task.update"
+	WSTypeTaskDelete  = "task.delete"
+	WSTypePong        = "pong"
+)
+
+// Server message types.
+const (
+	WSTypeResult = "result"
+	WSTypeError  = "error"
+	WSTypeEvent  = "event"
+	WSTypePing   = "ping"
+)
+
+// WSTopicTasks subscribes to changes of every task owned by the user.
+// Single tasks are subscribed to with the "task:<id>" topic.
+const WSTopicTasks = "tasks"
+
+// WSRequest defines a message sent by a WebSocket client.
+type WSRequest struct {
+	ID     string          `json:"id,omitempty" example:"42"`        // Correlation ID echoed back in the response
+	Type   string          `json:"type" example:"subscribe"`         // Message type
+	Topic  string          `json:"topic,omitempty" example:"task:1"` // Topic for subscribe and unsubscribe messages
+	TaskId int64           `json:"taskId,omitempty" example:"1"`     // Task ID for update and delete messages
+	Data   json.RawMessage `json:"data,omitempty"`                   // CreateTaskBody or domain.UpdateTaskData
+}
+
+// WSMessage defines a message sent to a WebSocket client.
+type WSMessage struct {
+	ID    string         `json:"id,omitempty" example:"42"` // Correlation ID of the request this message answers
+	Type  string         `json:"type" example:"result"`     // Message type
+	Event *domain.Event  `json:"event,omitempty"`           // Event payload for event messages
+	Data  any            `json:"data,omitempty"`            // Result payload for result messages
+	Error *ResponseError `json:"error,omitempty"`           // Error payload for error messages
+}
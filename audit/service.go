@@ -0,0 +1,67 @@
+// Package audit records the actions of the admin API and of the admin
+// command, so they can be reviewed later.
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Limits of the number of logs listed at once.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+//go:generate mockery --name AuditLogsRepository
+type AuditLogsRepository interface {
+	Create(context.Context, domain.AuditLog) (domain.AuditLog, error)
+	List(context.Context, domain.AuditLogFilter) ([]domain.AuditLog, error)
+}
+
+// Service records and lists audit logs.
+type Service struct {
+	auditLogsRepo AuditLogsRepository
+}
+
+var (
+	ErrInvalidAction = errors.New("action is missing or empty")
+	ErrInvalidLimit  = errors.New("limit must be between 1 and 500")
+)
+
+func NewService(auditLogsRepo AuditLogsRepository) *Service {
+	return &Service{auditLogsRepo: auditLogsRepo}
+}
+
+// Record stores a log of an action, timestamped now.
+func (s *Service) Record(ctx context.Context, log domain.AuditLog) error {
+	if len(log.Action) == 0 {
+		return ErrInvalidAction
+	}
+
+	log.CreatedAt = time.Now()
+	if _, err := s.auditLogsRepo.Create(ctx, log); err != nil {
+		logging.FromContext(ctx).Error("Failed to record audit log", zap.String("action", log.Action), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// List returns the logs matching the filter, newest first, up to
+// DefaultLimit of them unless the filter has a limit.
+func (s *Service) List(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxLimit {
+		return []domain.AuditLog{}, ErrInvalidLimit
+	}
+
+	return s.auditLogsRepo.List(ctx, filter)
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/audit/mocks"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecord(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("throws an error if action is invalid", func(t *testing.T) {
+		service := NewService(mocks.NewAuditLogsRepository(t))
+
+		err := service.Record(ctx, domain.AuditLog{ActorId: 1})
+		assert.EqualError(t, err, ErrInvalidAction.Error())
+	})
+
+	t.Run("stores the log timestamped now", func(t *testing.T) {
+		repo := mocks.NewAuditLogsRepository(t)
+		service := NewService(repo)
+
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(log domain.AuditLog) bool {
+			return log.Action == "users.disable" && log.ActorId == 1 && time.Since(log.CreatedAt) < time.Minute
+		})).Return(domain.AuditLog{ID: 1}, nil)
+
+		err := service.Record(ctx, domain.AuditLog{ActorId: 1, Action: "users.disable", Succeeded: true})
+		assert.Nil(t, err)
+	})
+
+	t.Run("returns the errors of the repository", func(t *testing.T) {
+		repo := mocks.NewAuditLogsRepository(t)
+		service := NewService(repo)
+
+		repo.On("Create", mock.Anything, mock.Anything).Return(domain.AuditLog{}, errors.New("db is down"))
+
+		err := service.Record(ctx, domain.AuditLog{Action: "users.disable"})
+		assert.EqualError(t, err, "db is down")
+	})
+}
+
+func TestList(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("lists up to the default limit", func(t *testing.T) {
+		repo := mocks.NewAuditLogsRepository(t)
+		service := NewService(repo)
+
+		logs := []domain.AuditLog{{ID: 2}, {ID: 1}}
+		repo.On("List", mock.Anything, domain.AuditLogFilter{ActorId: 1, Limit: DefaultLimit}).Return(logs, nil)
+
+		found, err := service.List(ctx, domain.AuditLogFilter{ActorId: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, logs, found)
+	})
+
+	t.Run("throws an error if limit is invalid", func(t *testing.T) {
+		service := NewService(mocks.NewAuditLogsRepository(t))
+
+		for _, limit := range []int{-1, MaxLimit + 1} {
+			_, err := service.List(ctx, domain.AuditLogFilter{Limit: limit})
+			assert.EqualError(t, err, ErrInvalidLimit.Error())
+		}
+	})
+}
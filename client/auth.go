@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/krau5/hyper-todo/api"
+)
+
+type (
+	RegisterBody = api.RegisterBody
+	LoginBody    = api.LoginBody
+	// Token is the token of a user and when it expires.
+	Token = api.TokenResponse
+)
+
+// Register creates an account.
+func (c *Client) Register(ctx context.Context, body RegisterBody) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/register", body: body, status: http.StatusCreated, anonymous: true})
+}
+
+// Login logs the user in, authenticating the next calls of the client.
+func (c *Client) Login(ctx context.Context, email, password string) (Token, error) {
+	var token Token
+	err := c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      "/login",
+		body:      LoginBody{Email: email, Password: password},
+		out:       &token,
+		status:    http.StatusOK,
+		anonymous: true,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	c.setToken(token)
+	return token, nil
+}
+
+// Refresh replaces the token of the client with a new one. Calls refresh it
+// by themselves when it is about to expire.
+func (c *Client) Refresh(ctx context.Context) (Token, error) {
+	var token Token
+	err := c.do(ctx, request{method: http.MethodPost, path: "/refresh", out: &token, status: http.StatusOK, anonymous: true})
+	if err != nil {
+		return Token{}, err
+	}
+
+	c.setToken(token)
+	return token, nil
+}
+
+// Token returns the current token of the client, which changes when it is
+// refreshed.
+func (c *Client) Token() Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token
+}
+
+func (c *Client) setToken(token Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = token
+}
+
+// refreshIfExpiring refreshes the token if it expires soon. Calls go on with
+// the current token when refreshing fails since it is still valid.
+func (c *Client) refreshIfExpiring(ctx context.Context) error {
+	if c.refreshBefore <= 0 || !c.expiring(c.Token()) {
+		return nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another call may have refreshed it meanwhile.
+	if !c.expiring(c.Token()) {
+		return nil
+	}
+
+	if _, err := c.Refresh(ctx); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// expiring reports whether the token expires within the refresh period.
+// Expired tokens can't be refreshed.
+func (c *Client) expiring(token Token) bool {
+	if token.ExpiresAt.IsZero() {
+		return false
+	}
+
+	left := time.Until(token.ExpiresAt)
+	return left > 0 && left <= c.refreshBefore
+}
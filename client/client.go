@@ -0,0 +1,339 @@
+// Package client is a Go client of the hyper-todo REST API.
+//
+// Requests and responses reuse the types of the api package, which the
+// server shares, so the client doesn't depend on the server. Failed calls
+// return an *Error decoded from the problem details of the response. Clients
+// authenticate with a bearer token by default, or with cookies like browsers
+// do, and refresh their token before it expires. Idempotent calls are retried
+// on network errors and on responses asking to retry later.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krau5/hyper-todo/api"
+)
+
+// APIPrefix is the path of the version of the API the client calls.
+const APIPrefix = "/api/v1"
+
+// AuthMode is how the client sends its token.
+type AuthMode int
+
+const (
+	// AuthBearer sends the token in the Authorization header.
+	AuthBearer AuthMode = iota
+	// AuthCookie keeps the cookies set at login and echoes the CSRF token
+	// of the session in state-changing requests.
+	AuthCookie
+)
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL       *url.URL
+	httpClient    *http.Client
+	authMode      AuthMode
+	maxRetries    int
+	retryWait     time.Duration
+	maxRetryWait  time.Duration
+	refreshBefore time.Duration
+
+	mu        sync.Mutex
+	token     Token
+	refreshMu sync.Mutex // Held while refreshing the token
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client doing the requests. With AuthCookie, a
+// cookie jar is set on it if it has none.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAuthMode sets how the client sends its token, AuthBearer by default.
+func WithAuthMode(mode AuthMode) Option {
+	return func(c *Client) { c.authMode = mode }
+}
+
+// WithToken sets the token of a previous login, such as one saved by a
+// command-line tool.
+func WithToken(token Token) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRetries sets how many times idempotent calls are retried, 2 by
+// default, and how long the client waits before the first retry. The wait
+// doubles on every retry unless the server says how long to wait.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithMaxRetryWait sets the longest the client waits before a retry, 10s by
+// default. Calls which the server asks to retry later than that fail.
+func WithMaxRetryWait(wait time.Duration) Option {
+	return func(c *Client) { c.maxRetryWait = wait }
+}
+
+// WithRefreshBefore sets how long before it expires the token is refreshed,
+// 5m by default. Zero disables refreshes.
+func WithRefreshBefore(d time.Duration) Option {
+	return func(c *Client) { c.refreshBefore = d }
+}
+
+// New returns a client of the API served at the base URL, such as
+// "https://todo.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + APIPrefix
+
+	c := &Client{
+		baseURL:       u,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		maxRetries:    2,
+		retryWait:     200 * time.Millisecond,
+		maxRetryWait:  10 * time.Second,
+		refreshBefore: 5 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.authMode == AuthCookie && c.httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		httpClient := *c.httpClient
+		httpClient.Jar = jar
+		c.httpClient = &httpClient
+	}
+
+	return c, nil
+}
+
+// Error is the problem details of a failed call. Code is stable and meant to
+// be matched, see ErrorCode.
+type Error = api.ResponseError
+
+// FieldError describes why a field of the request was rejected.
+type FieldError = api.FieldError
+
+// ErrorCode returns the code of the problem of a failed call, empty if the
+// call didn't fail with problem details.
+func ErrorCode(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+
+	return ""
+}
+
+// request describes a call to the API.
+type request struct {
+	method string
+	path   string // Relative to the API prefix, such as "/tasks"
+	query  url.Values
+	body   any
+	out    any // Decoded from the JSON response, or the raw one for strings
+	status int // Expected status
+
+	// anonymous calls don't refresh the token, such as those getting one.
+	anonymous bool
+}
+
+// do makes the call, refreshing the token first if it's about to expire.
+func (c *Client) do(ctx context.Context, r request) error {
+	if !r.anonymous {
+		if err := c.refreshIfExpiring(ctx); err != nil {
+			return err
+		}
+	}
+
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return err
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	resp, err := c.send(ctx, r.method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != r.status {
+		return decodeError(resp)
+	}
+	switch out := r.out.(type) {
+	case nil:
+		return nil
+	case *string:
+		data, err := io.ReadAll(resp.Body)
+		*out = string(data)
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(r.out); err != nil {
+		return fmt.Errorf("decoding response of %s %s: %w", r.method, r.path, err)
+	}
+
+	return nil
+}
+
+// send sends the request, retrying idempotent ones.
+func (c *Client) send(ctx context.Context, method, rawURL string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		c.authenticate(req)
+
+		resp, err := c.httpClient.Do(req)
+		retry := idempotent(method) && attempt < c.maxRetries
+		if !retry || (err == nil && !retryable(resp.StatusCode)) {
+			return resp, err
+		}
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait := c.retryWait << attempt
+		wait += rand.N(wait/2 + 1)
+		if resp != nil {
+			after, ok := retryAfter(resp.Header)
+			if ok && after > c.maxRetryWait {
+				return resp, nil
+			}
+			if ok {
+				wait = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		wait = min(wait, c.maxRetryWait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// authenticate adds the credentials of the client to the request.
+func (c *Client) authenticate(req *http.Request) {
+	if c.authMode == AuthCookie {
+		for _, cookie := range c.httpClient.Jar.Cookies(req.URL) {
+			if cookie.Name == api.CSRFCookie {
+				req.Header.Set(api.CSRFHeader, cookie.Value)
+			}
+		}
+		return
+	}
+
+	if token := c.Token(); len(token.Token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+	}
+}
+
+// decodeError returns the problem details of a failed call, or an error
+// describing the response if it has none, such as those of proxies.
+func decodeError(resp *http.Response) error {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == api.ProblemContentType {
+		var apiErr Error
+		if err := json.Unmarshal(data, &apiErr); err == nil {
+			return &apiErr
+		}
+	}
+
+	apiErr := &Error{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode), Detail: strings.TrimSpace(string(data))}
+	if len(apiErr.Detail) > 200 {
+		apiErr.Detail = apiErr.Detail[:200]
+	}
+
+	return apiErr
+}
+
+// idempotent reports whether repeating a request has the same effect as
+// making it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// retryable reports whether a request failing with the status may succeed
+// later.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// retryAfter parses the Retry-After header, in seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
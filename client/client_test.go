@@ -0,0 +1,372 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/lifecycle"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/krau5/hyper-todo/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const password = "Password_123"
+
+// apiURL is the URL of a server running the real router, shared by the
+// tests since the router registers metrics globally.
+var apiURL string
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.Storage.SQLitePath = ":memory:"
+	cfg.Auth.JwtSecretKey = "0123456789abcdef0123456789abcdef"
+	cfg.Auth.CookieDomain = "" // The test server listens on 127.0.0.1
+	cfg.Auth.CookieSecure = false
+	cfg.RateLimit.Enabled = false
+
+	db, err := repository.OpenSQLite(cfg.Storage.SQLitePath, &gorm.Config{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	app := lifecycle.New()
+	router, err := server.NewRouter(cfg, db, zap.NewNop(), app)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	srv := httptest.NewServer(router)
+	apiURL = srv.URL
+
+	code := m.Run()
+
+	srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	app.Shutdown(ctx)
+
+	os.Exit(code)
+}
+
+// newUser registers a user and returns a client logged in as them.
+func newUser(t *testing.T, name string, opts ...Option) *Client {
+	t.Helper()
+	ctx := context.Background()
+
+	c, err := New(apiURL, opts...)
+	require.NoError(t, err)
+
+	email := name + "@example.com"
+	require.NoError(t, c.Register(ctx, RegisterBody{Name: name, Email: email, Password: password}))
+	_, err = c.Login(ctx, email, password)
+	require.NoError(t, err)
+
+	return c
+}
+
+func deadline(d time.Duration) string {
+	return time.Now().Add(d).UTC().Format(time.RFC3339)
+}
+
+func TestClient_Tasks(t *testing.T) {
+	ctx := context.Background()
+	c := newUser(t, "tasks")
+
+	user, err := c.Me(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "tasks@example.com", user.Email)
+
+	blocker, err := c.CreateTask(ctx, CreateTaskBody{Name: "Buy", Description: "Buy the pizza", Deadline: deadline(time.Hour)})
+	require.NoError(t, err)
+	blocked, err := c.CreateTask(ctx, CreateTaskBody{Name: "Eat", Description: "Eat the pizza", Deadline: deadline(2 * time.Hour)})
+	require.NoError(t, err)
+
+	dependencies, err := c.AddBlocker(ctx, blocked.ID, blocker.ID)
+	require.NoError(t, err)
+	require.Len(t, dependencies.BlockedBy, 1)
+	assert.Equal(t, blocker.ID, dependencies.BlockedBy[0].ID)
+
+	yes := true
+	tasks, err := c.Tasks(ctx, TasksFilter{Blocked: &yes})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, blocked.ID, tasks[0].ID)
+
+	_, err = c.UpdateTask(ctx, blocked.ID, domain.UpdateTaskData{Completed: &yes})
+	assert.Equal(t, "task_blocked", ErrorCode(err))
+
+	name := "Eat the whole pizza"
+	updated, err := c.UpdateTask(ctx, blocked.ID, domain.UpdateTaskData{Name: &name, Completed: &yes, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, name, updated.Name)
+	assert.True(t, updated.Completed)
+
+	reminders, err := c.SetReminders(ctx, blocker.ID, RemindersBody{Offsets: []string{"30m"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"30m0s"}, reminders.Offsets)
+	reminders, err = c.Reminders(ctx, blocker.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"30m0s"}, reminders.Offsets)
+
+	_, err = c.RemoveBlocker(ctx, blocked.ID, blocker.ID)
+	require.NoError(t, err)
+	dependencies, err = c.Dependencies(ctx, blocked.ID)
+	require.NoError(t, err)
+	assert.Empty(t, dependencies.BlockedBy)
+
+	require.NoError(t, c.DeleteTask(ctx, blocked.ID))
+	tasks, err = c.Tasks(ctx, TasksFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, blocker.ID, tasks[0].ID)
+
+	usage, err := c.Usage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), usage.Tasks.Used)
+}
+
+func TestClient_Me(t *testing.T) {
+	ctx := context.Background()
+	c := newUser(t, "preferences")
+
+	timezone := "Europe/Berlin"
+	preferences, err := c.UpdatePreferences(ctx, domain.UpdatePreferencesData{Timezone: &timezone})
+	require.NoError(t, err)
+	assert.Equal(t, timezone, preferences.Timezone)
+	preferences, err = c.Preferences(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, timezone, preferences.Timezone)
+
+	digest, err := c.DigestPreview(ctx, DigestText)
+	require.NoError(t, err)
+	assert.NotEmpty(t, digest)
+
+	notifications, err := c.Notifications(ctx, true)
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+	require.NoError(t, c.ReadAllNotifications(ctx))
+
+	webhook, err := c.CreateWebhook(ctx, CreateWebhookBody{URL: "https://203.0.113.10/hooks/tasks"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, webhook.Secret)
+	active := false
+	webhook, err = c.UpdateWebhook(ctx, webhook.ID, domain.UpdateWebhookData{Active: &active})
+	require.NoError(t, err)
+	assert.False(t, webhook.Active)
+	webhooks, err := c.Webhooks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+	deliveries, err := c.Deliveries(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+	require.NoError(t, c.DeleteWebhook(ctx, webhook.ID))
+	_, err = c.Webhook(ctx, webhook.ID)
+	assert.Equal(t, "webhook_not_found", ErrorCode(err))
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := newUser(t, "errors")
+
+	_, err := c.UpdateTask(ctx, 1<<40, domain.UpdateTaskData{})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, "task_not_found", apiErr.Code)
+
+	_, err = c.CreateTask(ctx, CreateTaskBody{Name: "Eat"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "validation_failed", apiErr.Code)
+	assert.Equal(t, []FieldError{{Field: "deadline", Code: "format", Message: "must be a valid date-time"}}, apiErr.Errors)
+
+	anonymous, err := New(apiURL)
+	require.NoError(t, err)
+	_, err = anonymous.Me(ctx)
+	assert.Equal(t, "missing_token", ErrorCode(err))
+	_, err = anonymous.Login(ctx, "errors@example.com", "Wrong_password1")
+	assert.Equal(t, "invalid_credentials", ErrorCode(err))
+}
+
+func TestClient_CookieAuth(t *testing.T) {
+	ctx := context.Background()
+	c := newUser(t, "cookies", WithAuthMode(AuthCookie))
+
+	// Creating tasks requires the CSRF header with cookies.
+	task, err := c.CreateTask(ctx, CreateTaskBody{Name: "Eat", Description: "Eat the pizza", Deadline: deadline(time.Hour)})
+	require.NoError(t, err)
+	tasks, err := c.Tasks(ctx, TasksFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.ID, tasks[0].ID)
+}
+
+func TestClient_Events(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, mode := range []AuthMode{AuthBearer, AuthCookie} {
+		c := newUser(t, fmt.Sprintf("events%d", mode), WithAuthMode(mode))
+
+		stream, err := c.Events(ctx)
+		require.NoError(t, err)
+
+		// Events are only sent once subscribed, which the server acknowledges
+		// asynchronously.
+		var task domain.Task
+		require.Eventually(t, func() bool {
+			task, err = c.CreateTask(ctx, CreateTaskBody{Name: "Eat", Description: "Eat the pizza", Deadline: deadline(time.Hour)})
+			require.NoError(t, err)
+
+			select {
+			case event := <-stream.Events():
+				return event.Type == domain.EventTaskCreated && event.Task.ID == task.ID
+			case <-time.After(100 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second, time.Millisecond)
+
+		require.NoError(t, c.DeleteTask(ctx, task.ID))
+		event := <-stream.Events()
+		assert.Equal(t, domain.EventTaskDeleted, event.Type)
+		assert.Equal(t, task.ID, event.Task.ID)
+
+		require.NoError(t, stream.Close())
+		_, open := <-stream.Events()
+		assert.False(t, open)
+		assert.NoError(t, stream.Err())
+	}
+
+	anonymous, err := New(apiURL)
+	require.NoError(t, err)
+	_, err = anonymous.Events(ctx)
+	assert.Error(t, err)
+}
+
+func TestClient_RefreshesToken(t *testing.T) {
+	ctx := context.Background()
+	c := newUser(t, "refresh")
+
+	// A token expiring within the refresh period is refreshed by the next
+	// call, for the configured TTL.
+	token := c.Token()
+	token.ExpiresAt = time.Now().Add(time.Minute)
+	c.setToken(token)
+
+	_, err := c.Me(ctx)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(config.Default().Auth.TokenTTL), c.Token().ExpiresAt, 2*time.Second)
+
+	// Saved tokens can be reused.
+	saved, err := New(apiURL, WithToken(c.Token()))
+	require.NoError(t, err)
+	user, err := saved.Me(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh@example.com", user.Email)
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	statuses := make(chan int, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		status := <-statuses
+		switch status {
+		case http.StatusTooManyRequests:
+			w.Header().Set("Retry-After", "60")
+		case http.StatusBadGateway:
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(status)
+			fmt.Fprint(w, "<html>Bad Gateway</html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		statuses []int
+		calls    int32
+		status   int
+	}{
+		{
+			name:     "idempotent",
+			call:     func() error { _, err := c.Tasks(ctx, TasksFilter{}); return err },
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			calls:    3,
+		},
+		{
+			name:     "gives up",
+			call:     func() error { _, err := c.Tasks(ctx, TasksFilter{}); return err },
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusBadGateway},
+			calls:    3,
+			status:   http.StatusBadGateway,
+		},
+		{
+			name:     "not idempotent",
+			call:     func() error { _, err := c.CreateTask(ctx, CreateTaskBody{}); return err },
+			statuses: []int{http.StatusServiceUnavailable},
+			calls:    1,
+			status:   http.StatusServiceUnavailable,
+		},
+		{
+			name:     "retry too late",
+			call:     func() error { _, err := c.Tasks(ctx, TasksFilter{}); return err },
+			statuses: []int{http.StatusTooManyRequests},
+			calls:    1,
+			status:   http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			for _, status := range tt.statuses {
+				statuses <- status
+			}
+
+			err := tt.call()
+
+			assert.Equal(t, tt.calls, calls.Load())
+			if tt.status == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var apiErr *Error
+			require.True(t, errors.As(err, &apiErr), "error: %v", err)
+			assert.Equal(t, tt.status, apiErr.Status)
+		})
+	}
+}
+
+func TestNew_InvalidURL(t *testing.T) {
+	_, err := New("todo.example.com")
+	assert.Error(t, err)
+}
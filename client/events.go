@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"golang.org/x/net/websocket"
+)
+
+// EventStream receives the events of the tasks of the user over a WebSocket.
+type EventStream struct {
+	conn   *websocket.Conn
+	events chan domain.Event
+	done   chan struct{}
+	err    error // Why the stream ended, set before events is closed
+
+	closeOnce sync.Once
+}
+
+// Events opens a stream of the events of every task of the user. It ends
+// when the context is canceled, the stream is closed or the connection is
+// lost.
+func (c *Client) Events(ctx context.Context) (*EventStream, error) {
+	if err := c.refreshIfExpiring(ctx); err != nil {
+		return nil, err
+	}
+
+	location := *c.baseURL
+	location.Path += "/ws"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.authenticate(req)
+	if c.authMode == AuthCookie {
+		for _, cookie := range c.httpClient.Jar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+	}
+
+	// The server only accepts connections from its own origin.
+	origin := url.URL{Scheme: location.Scheme, Host: location.Host}
+	location.Scheme = strings.Replace(location.Scheme, "http", "ws", 1)
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Header = req.Header
+
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the event stream: %w", err)
+	}
+	if err := websocket.JSON.Send(conn, api.WSRequest{Type: api.WSTypeSubscribe, Topic: api.WSTopicTasks}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribing to task events: %w", err)
+	}
+
+	s := &EventStream{conn: conn, events: make(chan domain.Event, 16), done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	go s.receive()
+
+	return s, nil
+}
+
+// Events returns the channel of the events, closed when the stream ends.
+func (s *EventStream) Events() <-chan domain.Event {
+	return s.events
+}
+
+// Err returns why the stream ended once the channel of the events is closed,
+// nil if it was closed or its context canceled.
+func (s *EventStream) Err() error {
+	return s.err
+}
+
+// Close ends the stream.
+func (s *EventStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+	})
+
+	return err
+}
+
+// receive passes the events along and answers the pings of the server, which
+// disconnects silent clients.
+func (s *EventStream) receive() {
+	defer close(s.events)
+	defer s.Close()
+
+	for {
+		var msg api.WSMessage
+		if err := websocket.JSON.Receive(s.conn, &msg); err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.err = err
+			}
+			return
+		}
+
+		switch msg.Type {
+		case api.WSTypePing:
+			websocket.JSON.Send(s.conn, api.WSRequest{Type: api.WSTypePong})
+
+		case api.WSTypeEvent:
+			if msg.Event == nil {
+				continue
+			}
+			select {
+			case s.events <- *msg.Event:
+			case <-s.done:
+				return
+			}
+
+		case api.WSTypeError:
+			s.err = errors.New("event stream failed")
+			if msg.Error != nil {
+				s.err = msg.Error
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+)
+
+type UpdateNotificationBody = api.UpdateNotificationBody
+
+// Notifications lists the inbox of the user, newest first.
+func (c *Client) Notifications(ctx context.Context, unreadOnly bool) ([]domain.Notification, error) {
+	query := url.Values{}
+	if unreadOnly {
+		query.Set("unread", "true")
+	}
+
+	var notifications []domain.Notification
+	err := c.do(ctx, request{method: http.MethodGet, path: "/notifications", query: query, out: &notifications, status: http.StatusOK})
+	return notifications, err
+}
+
+// UpdateNotification marks a notification as read or unread.
+func (c *Client) UpdateNotification(ctx context.Context, notificationId int64, body UpdateNotificationBody) (domain.Notification, error) {
+	var notification domain.Notification
+	err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   "/notifications/" + strconv.FormatInt(notificationId, 10),
+		body:   body,
+		out:    &notification,
+		status: http.StatusOK,
+	})
+	return notification, err
+}
+
+// ReadAllNotifications marks every notification of the user as read.
+func (c *Client) ReadAllNotifications(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/notifications/read-all", status: http.StatusOK})
+}
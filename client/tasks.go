@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+)
+
+type (
+	CreateTaskBody = api.CreateTaskBody
+	RemindersBody  = api.RemindersBody
+)
+
+// TasksFilter filters the tasks of the user, every task by default.
+type TasksFilter struct {
+	Blocked *bool // Only tasks which are, or are not, blocked by other tasks
+}
+
+// Tasks lists the tasks of the user.
+func (c *Client) Tasks(ctx context.Context, filter TasksFilter) ([]domain.Task, error) {
+	query := url.Values{}
+	if filter.Blocked != nil {
+		query.Set("blocked", strconv.FormatBool(*filter.Blocked))
+	}
+
+	var tasks []domain.Task
+	err := c.do(ctx, request{method: http.MethodGet, path: "/tasks", query: query, out: &tasks, status: http.StatusOK})
+	return tasks, err
+}
+
+// CreateTask creates a task. The deadline is in the RFC 3339 format.
+func (c *Client) CreateTask(ctx context.Context, body CreateTaskBody) (domain.Task, error) {
+	var task domain.Task
+	err := c.do(ctx, request{method: http.MethodPost, path: "/tasks", body: body, out: &task, status: http.StatusCreated})
+	return task, err
+}
+
+// UpdateTask updates the set fields of a task.
+func (c *Client) UpdateTask(ctx context.Context, taskId int64, data domain.UpdateTaskData) (domain.Task, error) {
+	var task domain.Task
+	err := c.do(ctx, request{method: http.MethodPatch, path: taskPath(taskId), body: data, out: &task, status: http.StatusOK})
+	return task, err
+}
+
+// DeleteTask deletes a task.
+func (c *Client) DeleteTask(ctx context.Context, taskId int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: taskPath(taskId), status: http.StatusOK})
+}
+
+// Dependencies lists the tasks blocking a task and those it blocks.
+func (c *Client) Dependencies(ctx context.Context, taskId int64) (domain.TaskDependencies, error) {
+	var dependencies domain.TaskDependencies
+	err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskId) + "/dependencies", out: &dependencies, status: http.StatusOK})
+	return dependencies, err
+}
+
+// AddBlocker marks a task as blocked by another one.
+func (c *Client) AddBlocker(ctx context.Context, taskId, blockerId int64) (domain.TaskDependencies, error) {
+	var dependencies domain.TaskDependencies
+	err := c.do(ctx, request{method: http.MethodPut, path: blockerPath(taskId, blockerId), out: &dependencies, status: http.StatusOK})
+	return dependencies, err
+}
+
+// RemoveBlocker removes a blocker of a task.
+func (c *Client) RemoveBlocker(ctx context.Context, taskId, blockerId int64) (domain.TaskDependencies, error) {
+	var dependencies domain.TaskDependencies
+	err := c.do(ctx, request{method: http.MethodDelete, path: blockerPath(taskId, blockerId), out: &dependencies, status: http.StatusOK})
+	return dependencies, err
+}
+
+// Reminders returns how long before its deadline reminders of a task are
+// sent.
+func (c *Client) Reminders(ctx context.Context, taskId int64) (RemindersBody, error) {
+	var reminders RemindersBody
+	err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskId) + "/reminders", out: &reminders, status: http.StatusOK})
+	return reminders, err
+}
+
+// SetReminders replaces the reminders of a task.
+func (c *Client) SetReminders(ctx context.Context, taskId int64, body RemindersBody) (RemindersBody, error) {
+	var reminders RemindersBody
+	err := c.do(ctx, request{method: http.MethodPut, path: taskPath(taskId) + "/reminders", body: body, out: &reminders, status: http.StatusOK})
+	return reminders, err
+}
+
+func taskPath(taskId int64) string {
+	return "/tasks/" + strconv.FormatInt(taskId, 10)
+}
+
+func blockerPath(taskId, blockerId int64) string {
+	return fmt.Sprintf("%s/blockers/%d", taskPath(taskId), blockerId)
+}
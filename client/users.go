@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+// Digest formats of DigestPreview.
+const (
+	DigestHTML = "html"
+	DigestText = "text"
+)
+
+// Me returns the current user.
+func (c *Client) Me(ctx context.Context) (domain.User, error) {
+	var user domain.User
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me", out: &user, status: http.StatusOK})
+	return user, err
+}
+
+// Usage reports the consumption of the quotas of the user.
+func (c *Client) Usage(ctx context.Context) (domain.Usage, error) {
+	var usage domain.Usage
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me/usage", out: &usage, status: http.StatusOK})
+	return usage, err
+}
+
+// Preferences returns the notification preferences of the user.
+func (c *Client) Preferences(ctx context.Context) (domain.Preferences, error) {
+	var preferences domain.Preferences
+	err := c.do(ctx, request{method: http.MethodGet, path: "/me/preferences", out: &preferences, status: http.StatusOK})
+	return preferences, err
+}
+
+// UpdatePreferences updates the set preferences of the user.
+func (c *Client) UpdatePreferences(ctx context.Context, data domain.UpdatePreferencesData) (domain.Preferences, error) {
+	var preferences domain.Preferences
+	err := c.do(ctx, request{method: http.MethodPatch, path: "/me/preferences", body: data, out: &preferences, status: http.StatusOK})
+	return preferences, err
+}
+
+// DigestPreview renders the daily digest the user would receive now, in the
+// DigestHTML or DigestText format.
+func (c *Client) DigestPreview(ctx context.Context, format string) (string, error) {
+	var digest string
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/me/digest/preview",
+		query:  url.Values{"format": {format}},
+		out:    &digest,
+		status: http.StatusOK,
+	})
+	return digest, err
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+)
+
+type CreateWebhookBody = api.CreateWebhookBody
+
+// Webhooks lists the webhooks of the user, without their secrets.
+func (c *Client) Webhooks(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := c.do(ctx, request{method: http.MethodGet, path: "/webhooks", out: &webhooks, status: http.StatusOK})
+	return webhooks, err
+}
+
+// CreateWebhook creates a webhook. Its secret, signing the deliveries, is
+// only returned here.
+func (c *Client) CreateWebhook(ctx context.Context, body CreateWebhookBody) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := c.do(ctx, request{method: http.MethodPost, path: "/webhooks", body: body, out: &webhook, status: http.StatusCreated})
+	return webhook, err
+}
+
+// Webhook returns a webhook, without its secret.
+func (c *Client) Webhook(ctx context.Context, webhookId int64) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := c.do(ctx, request{method: http.MethodGet, path: webhookPath(webhookId), out: &webhook, status: http.StatusOK})
+	return webhook, err
+}
+
+// UpdateWebhook updates the set fields of a webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookId int64, data domain.UpdateWebhookData) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := c.do(ctx, request{method: http.MethodPatch, path: webhookPath(webhookId), body: data, out: &webhook, status: http.StatusOK})
+	return webhook, err
+}
+
+// DeleteWebhook deletes a webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookId int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: webhookPath(webhookId), status: http.StatusOK})
+}
+
+// Deliveries lists the deliveries of a webhook.
+func (c *Client) Deliveries(ctx context.Context, webhookId int64) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := c.do(ctx, request{method: http.MethodGet, path: webhookPath(webhookId) + "/deliveries", out: &deliveries, status: http.StatusOK})
+	return deliveries, err
+}
+
+// Redeliver queues a delivery of a webhook again.
+func (c *Client) Redeliver(ctx context.Context, webhookId, deliveryId int64) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("%s/deliveries/%d/redeliver", webhookPath(webhookId), deliveryId),
+		out:    &delivery,
+		status: http.StatusAccepted,
+	})
+	return delivery, err
+}
+
+func webhookPath(webhookId int64) string {
+	return "/webhooks/" + strconv.FormatInt(webhookId, 10)
+}
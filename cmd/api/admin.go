@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/krau5/hyper-todo/audit"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/krau5/hyper-todo/quota"
+	"github.com/krau5/hyper-todo/task"
+	"github.com/krau5/hyper-todo/user"
+	"gorm.io/gorm"
+)
+
+const adminUsage = `Usage: hyper-todo [flags] admin <command>
+
+Commands:
+  create-user [-password p] <name> <email>  create a user, with a generated password by default
+  reset-password [-password p] <email>      set a new password, generated by default
+  lock <email>                              refuse logins and the tokens of a user
+  unlock <email>                            let a locked user log in again
+  logout <email>                            refuse the tokens issued to a user until now
+  set-role <email> <role>                   make a user a user, support or admin
+  users                                     list users with their task counts
+  reassign <from-email> <to-email>          move every task of a user to another
+  purge [-older-than d]                     permanently remove data deleted at least d ago (default 0s)
+  seed [-email e] [-name n] [-password p]   create a demo user with sample tasks
+
+Commands changing data are recorded in the audit log.`
+
+// Requirements of the API on new users, checked before creating them.
+const (
+	minNameLength     = 4
+	minPasswordLength = 8
+)
+
+// errAdminUsage is returned for invalid arguments, along with the usage.
+var errAdminUsage = errors.New("invalid arguments")
+
+// admin runs the admin commands with the services of the configured
+// database, bypassing the API.
+type admin struct {
+	db          *gorm.DB
+	users       *user.Service
+	tasks       *task.Service
+	audit       *audit.Service
+	defaultPlan string
+	out         io.Writer
+	now         func() time.Time
+}
+
+func newAdmin(cfg config.Config, db *gorm.DB, out io.Writer) (*admin, error) {
+	usersRepo := repository.NewUserRepository(db)
+	tasksRepo := repository.NewTasksRepository(db)
+
+	plans, err := cfg.Quota.PlanLimits()
+	if err != nil {
+		return nil, fmt.Errorf("invalid quota plans: %w", err)
+	}
+	quotaService := quota.NewService(quota.Plans{Limits: plans, Default: cfg.Quota.DefaultPlan}, usersRepo, tasksRepo, repository.NewUsageRepository(db))
+
+	// Events have no subscribers outside of the server, which reloads the
+	// tasks of its clients on reconnection.
+	tasksService := task.NewService(tasksRepo, usersRepo, repository.NewDependenciesRepository(db), events.NewBus(), quotaService)
+
+	return &admin{
+		db:          db,
+		users:       user.NewService(usersRepo),
+		tasks:       tasksService,
+		audit:       audit.NewService(repository.NewAuditLogsRepository(db)),
+		defaultPlan: cfg.Quota.DefaultPlan,
+		out:         out,
+		now:         time.Now,
+	}, nil
+}
+
+// runAdmin runs an admin subcommand and returns the exit code.
+func runAdmin(cfg config.Config, db *gorm.DB, args []string) int {
+	a, err := newAdmin(cfg, db, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	err = a.run(context.Background(), args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprintln(os.Stdout, adminUsage)
+		return 0
+	case errors.Is(err, errAdminUsage):
+		if err != errAdminUsage {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func (a *admin) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errAdminUsage
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"create-user":    a.createUser,
+		"reset-password": a.resetPassword,
+		"lock":           a.lock,
+		"unlock":         a.unlock,
+		"logout":         a.logout,
+		"set-role":       a.setRole,
+		"users":          a.listUsers,
+		"reassign":       a.reassign,
+		"purge":          a.purge,
+		"seed":           a.seed,
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errAdminUsage, args[0])
+	}
+
+	return command(ctx, args[1:])
+}
+
+// parse parses the flags of a command, which must leave n arguments.
+func parse(fs *flag.FlagSet, args []string, n int) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errAdminUsage, err)
+	}
+
+	if fs.NArg() != n {
+		return fmt.Errorf("%w: wrong number of arguments to %s", errAdminUsage, fs.Name())
+	}
+
+	return nil
+}
+
+func (a *admin) createUser(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	password := fs.String("password", "", "password of the user, generated if empty")
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+
+	u, generated, err := a.create(ctx, fs.Arg(0), fs.Arg(1), *password)
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.create", u.ID, "", &err)
+
+	fmt.Fprintf(a.out, "Created user %d <%s>\n", u.ID, u.Email)
+	if generated {
+		fmt.Fprintf(a.out, "Password: %s\n", u.Password)
+	}
+
+	return nil
+}
+
+// create creates a user, generating their password if it's empty. The
+// returned user holds the password in clear.
+func (a *admin) create(ctx context.Context, name, email, password string) (_ domain.User, generated bool, err error) {
+	if len([]rune(name)) < minNameLength {
+		return domain.User{}, false, fmt.Errorf("name must be at least %d characters long", minNameLength)
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return domain.User{}, false, fmt.Errorf("invalid email %q", email)
+	}
+
+	password, generated, err = a.password(password)
+	if err != nil {
+		return domain.User{}, false, err
+	}
+
+	err = a.users.Create(ctx, name, email, password)
+	if errors.Is(err, domain.ErrDuplicate) {
+		return domain.User{}, false, fmt.Errorf("a user with email %s already exists", email)
+	}
+	if err != nil {
+		return domain.User{}, false, err
+	}
+
+	u, err := a.users.GetByEmail(ctx, email)
+	if err != nil {
+		return domain.User{}, false, err
+	}
+	u.Password = password
+
+	return u, generated, nil
+}
+
+func (a *admin) resetPassword(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	password := fs.String("password", "", "new password, generated if empty")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.reset_password", u.ID, "", &err)
+
+	newPassword, generated, err := a.password(*password)
+	if err != nil {
+		return err
+	}
+
+	if err := a.users.ResetPassword(ctx, u.ID, newPassword); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Reset the password of user %d <%s>\n", u.ID, u.Email)
+	if generated {
+		fmt.Fprintf(a.out, "Password: %s\n", newPassword)
+	}
+
+	return nil
+}
+
+// password checks the password, or generates one if it's empty.
+func (a *admin) password(password string) (_ string, generated bool, err error) {
+	if len(password) != 0 {
+		if len(password) < minPasswordLength {
+			return "", false, fmt.Errorf("password must be at least %d characters long", minPasswordLength)
+		}
+		return password, false, nil
+	}
+
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), true, nil
+}
+
+func (a *admin) lock(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("lock", flag.ContinueOnError)
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	// Recorded as the admin API disabling the account.
+	defer a.record(ctx, "users.disable", u.ID, "", &err)
+
+	if err := a.users.Lock(ctx, u.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Locked user %d <%s>\n", u.ID, u.Email)
+	return nil
+}
+
+func (a *admin) unlock(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("unlock", flag.ContinueOnError)
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.enable", u.ID, "", &err)
+
+	if err := a.users.Unlock(ctx, u.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Unlocked user %d <%s>\n", u.ID, u.Email)
+	return nil
+}
+
+func (a *admin) logout(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("logout", flag.ContinueOnError)
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.logout", u.ID, "", &err)
+
+	if err := a.users.RevokeSessions(ctx, u.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Logged out user %d <%s>\n", u.ID, u.Email)
+	return nil
+}
+
+func (a *admin) setRole(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("set-role", flag.ContinueOnError)
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+
+	role := domain.Role(fs.Arg(1))
+	if !role.Valid() {
+		return fmt.Errorf("%w: %v", errAdminUsage, user.ErrInvalidRole)
+	}
+
+	u, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.set_role", u.ID, "role="+string(role), &err)
+
+	if err := a.users.SetRole(ctx, u.ID, role); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "User %d <%s> is now %s\n", u.ID, u.Email, role)
+	return nil
+}
+
+func (a *admin) listUsers(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("users", flag.ContinueOnError)
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	users, err := a.users.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLE\tPLAN\tTASKS\tLOCKED AT")
+	for _, u := range users {
+		count, err := a.tasks.CountByUser(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+
+		plan := u.Plan
+		if len(plan) == 0 {
+			plan = a.defaultPlan
+		}
+		lockedAt := "-"
+		if u.Locked() {
+			lockedAt = u.LockedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\n", u.ID, u.Name, u.Email, u.Role, plan, count, lockedAt)
+	}
+
+	return w.Flush()
+}
+
+func (a *admin) reassign(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("reassign", flag.ContinueOnError)
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+
+	from, err := a.user(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := a.user(ctx, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "tasks.reassign", from.ID, fmt.Sprintf("to=%d", to.ID), &err)
+
+	count, err := a.tasks.Reassign(ctx, from.ID, to.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Moved %d tasks from %s to %s\n", count, from.Email, to.Email)
+	return nil
+}
+
+func (a *admin) purge(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	olderThan := fs.Duration("older-than", 0, "only purge data deleted at least this long ago")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return fmt.Errorf("%w: -older-than must not be negative", errAdminUsage)
+	}
+	defer a.record(ctx, "data.purge", 0, "older-than="+olderThan.String(), &err)
+
+	purged, err := repository.PurgeDeleted(ctx, a.db, a.now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+
+	if len(purged) == 0 {
+		fmt.Fprintln(a.out, "nothing to purge")
+	}
+	for _, p := range purged {
+		fmt.Fprintf(a.out, "purged %d rows from %s\n", p.Rows, p.Table)
+	}
+
+	return nil
+}
+
+// demoTasks are the tasks of the demo user, due relative to the seeding time.
+// The blocker is the index of the task blocking it, if any.
+var demoTasks = []struct {
+	name, description string
+	due               time.Duration
+	completed         bool
+	blocker           int
+}{
+	{"Buy groceries", "Milk, eggs, bread and coffee", 3 * time.Hour, false, -1},
+	{"Book a venue", "Find a room for the team offsite", 2 * 24 * time.Hour, false, -1},
+	{"Send invitations", "Invite the team once the venue is booked", 5 * 24 * time.Hour, false, 1},
+	{"Renew passport", "The current one expires next month", -24 * time.Hour, false, -1},
+	{"File expenses", "Receipts of the last trip", -3 * 24 * time.Hour, true, -1},
+	{"Plan the sprint", "Review the backlog with the team", 7 * 24 * time.Hour, false, -1},
+}
+
+func (a *admin) seed(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	name := fs.String("name", "Demo User", "name of the demo user")
+	email := fs.String("email", "demo@example.com", "email of the demo user")
+	password := fs.String("password", "", "password of the demo user, generated if empty")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	u, generated, err := a.create(ctx, *name, *email, *password)
+	if err != nil {
+		return err
+	}
+	defer a.record(ctx, "users.seed", u.ID, "", &err)
+
+	now := a.now().Truncate(time.Minute)
+	created := make([]domain.Task, len(demoTasks))
+	for i, demo := range demoTasks {
+		created[i], err = a.tasks.Create(ctx, demo.name, demo.description, now.Add(demo.due), u.ID)
+		if err != nil {
+			return err
+		}
+
+		if demo.completed {
+			completed := true
+			if _, err := a.tasks.UpdateById(ctx, created[i].ID, domain.UpdateTaskData{Completed: &completed}); err != nil {
+				return err
+			}
+		}
+	}
+	for i, demo := range demoTasks {
+		if demo.blocker < 0 {
+			continue
+		}
+		if err := a.tasks.AddBlocker(ctx, created[i].ID, created[demo.blocker].ID); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(a.out, "Created user %d <%s> with %d tasks\n", u.ID, u.Email, len(created))
+	if generated {
+		fmt.Fprintf(a.out, "Password: %s\n", u.Password)
+	}
+
+	return nil
+}
+
+// user finds a user by email.
+func (a *admin) user(ctx context.Context, email string) (domain.User, error) {
+	u, err := a.users.GetByEmail(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.User{}, fmt.Errorf("no user with email %s", email)
+	}
+
+	return u, err
+}
+
+// record adds the outcome of a command to the audit log, with no actor. A
+// failure to record fails the command, though its change is kept.
+func (a *admin) record(ctx context.Context, action string, targetUserId int64, details string, err *error) {
+	log := domain.AuditLog{
+		Action:       action,
+		TargetUserId: targetUserId,
+		Details:      details,
+		Succeeded:    *err == nil,
+	}
+	if *err != nil {
+		log.Error = (*err).Error()
+	}
+
+	if recordErr := a.audit.Record(ctx, log); recordErr != nil && *err == nil {
+		*err = fmt.Errorf("failed to record audit log: %w", recordErr)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestAdmin(t *testing.T) (*admin, *bytes.Buffer) {
+	t.Helper()
+
+	db, err := repository.OpenSQLite(":memory:", &gorm.Config{Logger: logger.Discard})
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	var out bytes.Buffer
+	a, err := newAdmin(config.Default(), db, &out)
+	require.Nil(t, err)
+
+	return a, &out
+}
+
+// runCommand runs an admin command and returns its output.
+func runCommand(t *testing.T, a *admin, out *bytes.Buffer, args ...string) string {
+	t.Helper()
+
+	out.Reset()
+	require.Nil(t, a.run(context.TODO(), args))
+
+	return out.String()
+}
+
+func TestAdmin_Users(t *testing.T) {
+	a, out := newTestAdmin(t)
+	ctx := context.TODO()
+
+	output := runCommand(t, a, out, "create-user", "-password", "Password_123", "Alice Smith", "alice@example.com")
+	assert.Equal(t, "Created user 1 <alice@example.com>\n", output)
+
+	output = runCommand(t, a, out, "create-user", "Bob Jones", "bob@example.com")
+	assert.Regexp(t, `^Created user 2 <bob@example.com>\nPassword: \S{16}\n$`, output)
+
+	err := a.run(ctx, []string{"create-user", "Alice Smith", "alice@example.com"})
+	assert.EqualError(t, err, "a user with email alice@example.com already exists")
+
+	output = runCommand(t, a, out, "reset-password", "-password", "Password_456", "alice@example.com")
+	assert.Equal(t, "Reset the password of user 1 <alice@example.com>\n", output)
+	alice, err := a.users.GetByEmail(ctx, "alice@example.com")
+	require.Nil(t, err)
+	assert.True(t, utils.VerifyPassword("Password_456", alice.Password))
+
+	output = runCommand(t, a, out, "lock", "bob@example.com")
+	assert.Equal(t, "Locked user 2 <bob@example.com>\n", output)
+	bob, err := a.users.GetByEmail(ctx, "bob@example.com")
+	require.Nil(t, err)
+	assert.True(t, bob.Locked())
+
+	output = runCommand(t, a, out, "set-role", "alice@example.com", "admin")
+	assert.Equal(t, "User 1 <alice@example.com> is now admin\n", output)
+
+	output = runCommand(t, a, out, "logout", "alice@example.com")
+	assert.Equal(t, "Logged out user 1 <alice@example.com>\n", output)
+	alice, err = a.users.GetByEmail(ctx, "alice@example.com")
+	require.Nil(t, err)
+	assert.Equal(t, domain.RoleAdmin, alice.Role)
+	assert.NotNil(t, alice.SessionsRevokedAt)
+
+	_, err = a.tasks.Create(ctx, "task", "description", time.Now(), alice.ID)
+	require.Nil(t, err)
+
+	output = runCommand(t, a, out, "users")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^ID\s+NAME\s+EMAIL\s+ROLE\s+PLAN\s+TASKS\s+LOCKED AT$`, lines[0])
+	assert.Regexp(t, `^1\s+Alice Smith\s+alice@example.com\s+admin\s+free\s+1\s+-$`, lines[1])
+	assert.Regexp(t, `^2\s+Bob Jones\s+bob@example.com\s+user\s+free\s+0\s+\d{4}-\d\d-\d\dT`, lines[2])
+
+	runCommand(t, a, out, "unlock", "bob@example.com")
+	bob, err = a.users.GetByEmail(ctx, "bob@example.com")
+	require.Nil(t, err)
+	assert.False(t, bob.Locked())
+
+	output = runCommand(t, a, out, "reassign", "alice@example.com", "bob@example.com")
+	assert.Equal(t, "Moved 1 tasks from alice@example.com to bob@example.com\n", output)
+	tasks, err := a.tasks.GetByUser(ctx, bob.ID)
+	require.Nil(t, err)
+	assert.Len(t, tasks, 1)
+
+	err = a.run(ctx, []string{"lock", "missing@example.com"})
+	assert.EqualError(t, err, "no user with email missing@example.com")
+
+	logs, err := a.audit.List(ctx, domain.AuditLogFilter{})
+	require.Nil(t, err)
+	var actions []string
+	for _, log := range logs {
+		assert.Zero(t, log.ActorId)
+		assert.True(t, log.Succeeded)
+		actions = append(actions, log.Action)
+	}
+	assert.Equal(t, []string{"tasks.reassign", "users.enable", "users.logout", "users.set_role", "users.disable", "users.reset_password", "users.create", "users.create"}, actions)
+	assert.Equal(t, "role=admin", logs[3].Details)
+	assert.Equal(t, "to=2", logs[0].Details)
+}
+
+func TestAdmin_SeedGeneratesPassword(t *testing.T) {
+	a, out := newTestAdmin(t)
+
+	output := runCommand(t, a, out, "seed")
+	assert.Regexp(t, `^Created user 1 <demo@example.com> with 6 tasks\nPassword: \S{16}\n$`, output)
+}
+
+func TestAdmin_SeedAndPurge(t *testing.T) {
+	a, out := newTestAdmin(t)
+	ctx := context.TODO()
+
+	output := runCommand(t, a, out, "seed", "-password", "Password_123")
+	assert.Equal(t, "Created user 1 <demo@example.com> with 6 tasks\n", output)
+
+	tasks, err := a.tasks.GetByUser(ctx, 1)
+	require.Nil(t, err)
+	require.Len(t, tasks, len(demoTasks))
+	var completed, blocked int
+	for _, task := range tasks {
+		if task.Completed {
+			completed++
+		}
+		if task.Blocked {
+			blocked++
+		}
+	}
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 1, blocked)
+
+	output = runCommand(t, a, out, "purge")
+	assert.Equal(t, "nothing to purge\n", output)
+
+	require.Nil(t, a.tasks.DeleteById(ctx, tasks[0].ID))
+
+	output = runCommand(t, a, out, "purge", "-older-than", "1h")
+	assert.Equal(t, "nothing to purge\n", output)
+
+	a.now = func() time.Time { return time.Now().Add(time.Minute) }
+	output = runCommand(t, a, out, "purge")
+	assert.Equal(t, "purged 1 rows from task_models\n", output)
+}
+
+func TestAdmin_Usage(t *testing.T) {
+	a, _ := newTestAdmin(t)
+	ctx := context.TODO()
+
+	for _, args := range [][]string{
+		nil,
+		{"unknown"},
+		{"lock"},
+		{"set-role", "alice@example.com", "owner"},
+		{"create-user", "-unknown", "name", "email@example.com"},
+		{"purge", "-older-than", "-1h"},
+	} {
+		assert.ErrorIs(t, a.run(ctx, args), errAdminUsage, "%q", args)
+	}
+
+	assert.ErrorIs(t, a.run(ctx, []string{"users", "-h"}), flag.ErrHelp)
+
+	err := a.run(ctx, []string{"create-user", "-password", "short", "Alice Smith", "alice@example.com"})
+	assert.EqualError(t, err, "password must be at least 8 characters long")
+
+	err = a.run(ctx, []string{"create-user", "Al", "alice@example.com"})
+	assert.EqualError(t, err, "name must be at least 4 characters long")
+
+	err = a.run(ctx, []string{"create-user", "Alice Smith", "not an email"})
+	assert.EqualError(t, err, `invalid email "not an email"`)
+}
@@ -1,60 +1,201 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
-	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
 	"github.com/krau5/hyper-todo/config"
-	_ "github.com/krau5/hyper-todo/docs"
+	"github.com/krau5/hyper-todo/internal/lifecycle"
+	"github.com/krau5/hyper-todo/internal/logging"
 	"github.com/krau5/hyper-todo/internal/repository"
-	"github.com/krau5/hyper-todo/internal/rest"
-	"github.com/krau5/hyper-todo/internal/rest/middleware"
-	"github.com/krau5/hyper-todo/task"
-	"github.com/krau5/hyper-todo/user"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	swaggerfiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/krau5/hyper-todo/internal/server"
+	"github.com/krau5/hyper-todo/internal/tracing"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// @title Hyper Todo API
-// @securityDefinitions.apikey ApiKeyAuth
-// @in header
-// @name Authorization
 func main() {
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "Usage: hyper-todo [flags] [migrate|admin <command>]\n\nFlags:\n%s", config.Usage())
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	logger := initLogger(gin.Mode())
 	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+
+	logger.Debug("Loaded configuration", zap.Any("config", cfg.Values()))
+
+	db := initDB(cfg, logger)
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if cfg.Storage.Driver != config.DriverPostgres {
+			fmt.Fprintf(os.Stderr, "migrations only apply to the %s storage driver\n", config.DriverPostgres)
+			os.Exit(2)
+		}
+		os.Exit(runMigrate(db, args[1:]))
+	}
+	if len(args) > 0 && args[0] == "admin" {
+		if db == nil {
+			fmt.Fprintf(os.Stderr, "admin commands need a database, not the %s storage driver\n", config.DriverMemory)
+			os.Exit(2)
+		}
+		if cfg.Storage.Driver == config.DriverPostgres {
+			if err := prepareSchema(db, cfg.Postgres, logger); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(runAdmin(cfg, db, args[1:]))
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		os.Exit(2)
+	}
+
+	if cfg.Storage.Driver == config.DriverPostgres {
+		if err := prepareSchema(db, cfg.Postgres, logger); err != nil {
+			logger.Fatal("database schema is not ready", zap.Error(err))
+		}
+	}
+
+	app := lifecycle.New()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	app.OnShutdown("tracing", shutdownTracing)
+
+	if db != nil {
+		app.OnShutdown("database", func(context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		})
+	}
+
+	router, err := server.NewRouter(cfg, db, logger, app)
+	if err != nil {
+		logger.Fatal("failed to set up the router", zap.Error(err))
+	}
 
-	db := initDB(logger)
+	httpServer := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.HTTP.Port),
+		Handler:           router,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		// Requests outliving the drain, such as WebSocket connections, are
+		// canceled along with the background workers.
+		BaseContext: func(net.Listener) context.Context { return app.Context() },
+	}
 
-	r := gin.Default()
+	if err := serve(httpServer, cfg.HTTP, app, logger); err != nil {
+		logger.Error("Server stopped with an error", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
+	}
+}
 
-	r.Use(ginzap.Ginzap(logger, time.RFC3339, true))
-	r.Use(ginzap.RecoveryWithZap(logger, true))
-	registerHandlers(r, db)
+// serve runs the server until it fails or SIGINT/SIGTERM is received, then
+// drains in-flight requests and shuts the lifecycle down within the
+// configured shutdown timeout.
+func serve(server *http.Server, cfg config.HTTPConfig, app *lifecycle.Lifecycle, logger *zap.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	logger.Info("Server started", zap.Int("port", cfg.Port))
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		errs = append(errs, err)
+	case <-ctx.Done():
+		logger.Info("Shutting down", zap.Duration("timeout", cfg.ShutdownTimeout))
+
+		// Fail readiness first, so load balancers stop routing new traffic
+		// before the listener is closed.
+		app.Drain()
+		time.Sleep(cfg.ShutdownDelay)
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
-	logger.Info("Server started", zap.String("port", config.Envs.Port))
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("draining connections: %w", err))
+	}
 
-	if err := r.Run(":" + config.Envs.Port); err != nil {
-		logger.Fatal("Server failed to start", zap.Error(err))
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
 	}
+
+	return errors.Join(errs...)
 }
 
-func initDB(logger *zap.Logger) *gorm.DB {
-	dsn := config.GetDsn()
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// initDB opens the database of the storage driver. It returns nil for the
+// memory driver, which needs none.
+func initDB(cfg config.Config, logger *zap.Logger) *gorm.DB {
+	gormConfig := &gorm.Config{Logger: logging.NewGormLogger(cfg.Log.SlowQueryThreshold)}
+
+	switch cfg.Storage.Driver {
+	case config.DriverMemory:
+		return nil
+
+	case config.DriverSQLite:
+		db, err := repository.OpenSQLite(cfg.Storage.SQLitePath, gormConfig)
+		if err != nil {
+			logger.Fatal("failed to open the SQLite database", zap.Error(err))
+		}
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			logger.Fatal("failed to instrument the database", zap.Error(err))
+		}
+
+		return db
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.Postgres.DSN()), gormConfig)
 	if err != nil {
 		logger.Fatal("failed to connect to db", zap.Error(err))
 	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		logger.Fatal("failed to instrument the database", zap.Error(err))
+	}
 
-	err = db.AutoMigrate(&repository.UserModel{}, &repository.TaskModel{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		logger.Fatal("failed to run migrations", zap.Error(err))
+		logger.Fatal("failed to access the connection pool", zap.Error(err))
 	}
+	sqlDB.SetMaxOpenConns(cfg.Postgres.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Postgres.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Postgres.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Postgres.ConnMaxIdleTime)
 
 	return db
 }
@@ -65,24 +206,3 @@ func initLogger(mode string) *zap.Logger {
 	}
 	return zap.Must(zap.NewDevelopment())
 }
-
-func registerHandlers(r *gin.Engine, db *gorm.DB) {
-	usersRepo := repository.NewUserRepository(db)
-	usersService := user.NewService(usersRepo)
-
-	tasksRepo := repository.NewTasksRepository(db)
-	tasksService := task.NewService(tasksRepo, usersRepo)
-
-	r.Use(middleware.PrometheusMiddleware())
-	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
-
-	rest.NewPingHandler(r)
-	rest.NewAuthHandler(r, usersService)
-	rest.NewTasksHandler(r, tasksService)
-	rest.NewUsersHandler(r, usersService)
-
-	r.GET("/swagger", func(c *gin.Context) {
-		c.Redirect(http.StatusPermanentRedirect, "/swagger/index.html")
-	})
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler, ginSwagger.DefaultModelsExpandDepth(-1)))
-}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/internal/migrations"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `Usage: hyper-todo [flags] migrate <command>
+
+Commands:
+  up         apply all pending migrations
+  down [n]   revert the last n applied migrations (default 1)
+  status     list migrations and when they were applied`
+
+// prepareSchema applies pending migrations if MIGRATE_ON_START is set and
+// otherwise only verifies the database is migrated to the expected version.
+func prepareSchema(db *gorm.DB, cfg config.PostgresConfig, logger *zap.Logger) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if !cfg.MigrateOnStart {
+		return migrator.Check(ctx)
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		logger.Info("applied migration", zap.Int64("version", m.Version), zap.String("name", m.Name))
+	}
+
+	return err
+}
+
+// runMigrate runs a migrate subcommand and returns the exit code.
+func runMigrate(db *gorm.DB, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
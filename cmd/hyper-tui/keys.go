@@ -0,0 +1,104 @@
+package main
+
+import "unicode/utf8"
+
+// keyCode identifies the keys without a printable character.
+type keyCode int
+
+const (
+	keyRune keyCode = iota // A printable character
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyTab
+	keyBacktab
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyCtrlC
+)
+
+// key is a key pressed by the user.
+type key struct {
+	code keyCode
+	r    rune // Character of keyRune keys
+}
+
+func runeKey(r rune) key {
+	return key{code: keyRune, r: r}
+}
+
+// escapeKeys are the keys sent as escape sequences by terminals, after the
+// escape character.
+var escapeKeys = map[string]keyCode{
+	"[A": keyUp, "OA": keyUp,
+	"[B": keyDown, "OB": keyDown,
+	"[C": keyRight, "OC": keyRight,
+	"[D": keyLeft, "OD": keyLeft,
+	"[Z": keyBacktab,
+}
+
+// decodeKeys decodes the keys of the input read from a terminal in raw mode.
+// Unknown escape sequences are dropped.
+func decodeKeys(input []byte) []key {
+	var keys []key
+
+	for len(input) > 0 {
+		switch b := input[0]; b {
+		case '\r', '\n':
+			keys = append(keys, key{code: keyEnter})
+		case '\t':
+			keys = append(keys, key{code: keyTab})
+		case 0x7f, 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+
+		case 0x1b:
+			n := escapeLength(input)
+			if n == 1 {
+				keys = append(keys, key{code: keyEsc})
+			} else if code, ok := escapeKeys[string(input[1:n])]; ok {
+				keys = append(keys, key{code: code})
+			}
+			input = input[n:]
+			continue
+
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError && r >= ' ' {
+				keys = append(keys, runeKey(r))
+			}
+			input = input[size:]
+			continue
+		}
+
+		input = input[1:]
+	}
+
+	return keys
+}
+
+// escapeLength returns the length of the escape sequence the input starts
+// with, 1 for a lone escape key.
+func escapeLength(input []byte) int {
+	if len(input) < 2 {
+		return 1
+	}
+
+	switch input[1] {
+	case 'O':
+		return min(3, len(input))
+	case '[':
+		// Control sequences end with a byte in the 0x40-0x7e range.
+		for i := 2; i < len(input); i++ {
+			if input[i] >= 0x40 && input[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(input)
+	}
+
+	return 1
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []key
+	}{
+		{"runes", "aé ", []key{runeKey('a'), runeKey('é'), runeKey(' ')}},
+		{"arrows", "\x1b[A\x1b[B\x1bOC\x1b[D", []key{{code: keyUp}, {code: keyDown}, {code: keyRight}, {code: keyLeft}}},
+		{"controls", "\r\t\x7f\x03\x1b[Z", []key{{code: keyEnter}, {code: keyTab}, {code: keyBackspace}, {code: keyCtrlC}, {code: keyBacktab}}},
+		{"escape", "\x1b", []key{{code: keyEsc}}},
+		{"escape then rune", "\x1bq", []key{{code: keyEsc}, runeKey('q')}},
+		{"unknown sequence", "\x1b[1;5Ax", []key{runeKey('x')}},
+		{"other controls", "\x01\x02", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, decodeKeys([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Command hyper-tui is a keyboard-driven terminal UI for the tasks of a
+// hyper-todo account, logged in to with the hyper command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+	"github.com/krau5/hyper-todo/internal/credentials"
+)
+
+// refreshBefore is how long before it expires the saved token is refreshed,
+// keeping the session alive while the UI is open.
+const refreshBefore = 30 * time.Minute
+
+func main() {
+	fs := flag.NewFlagSet("hyper-tui", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: hyper-tui [flags]\n\nRun hyper login first.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	server := fs.String("server", os.Getenv("HYPER_SERVER"), "`URL` of the API, the one logged in to by default (HYPER_SERVER)")
+	pollInterval := fs.Duration("poll", 30*time.Second, "how often tasks are reloaded while the event stream is unavailable")
+	events := fs.Bool("events", true, "receive changes from the event stream")
+	fs.Parse(os.Args[1:])
+
+	if err := run(*server, *pollInterval, *events); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(server string, pollInterval time.Duration, events bool) error {
+	configDir, err := credentials.DefaultDir()
+	if err != nil {
+		return err
+	}
+	creds, err := credentials.Load(configDir)
+	if err != nil {
+		return err
+	}
+	if len(creds.Token.Token) == 0 {
+		return credentials.ErrNotLoggedIn
+	}
+	if len(server) != 0 && strings.TrimSuffix(server, "/") != strings.TrimSuffix(creds.Server, "/") {
+		return fmt.Errorf("logged in to %s, run hyper login to use %s", creds.Server, server)
+	}
+
+	api, err := client.New(creds.Server, client.WithToken(creds.Token), client.WithRefreshBefore(refreshBefore))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if token := api.Token(); token.Token != creds.Token.Token {
+			creds.Token = token
+			if err := credentials.Save(configDir, creds); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to save the refreshed token: %v\n", err)
+			}
+		}
+	}()
+
+	term, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer term.restore()
+	io.WriteString(term.out, enterAltScreen)
+	defer io.WriteString(term.out, exitAltScreen)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	m := newModel(api, time.Now(), pollInterval)
+	if width, height, err := term.size(); err == nil {
+		m.width, m.height = width, height
+	}
+
+	p := newProgram(term.out)
+	go p.readKeys(ctx, term.in)
+	go p.tick(ctx, time.Second)
+	if events {
+		go p.streamEvents(ctx, api)
+	}
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+	go func() {
+		for range resized {
+			if width, height, err := term.size(); err == nil {
+				p.send(ctx, resizeMsg{width: width, height: height})
+			}
+		}
+	}()
+
+	p.run(ctx, m)
+	return nil
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/deadline"
+)
+
+// taskAPI is the part of the API the model calls, implemented by
+// *client.Client.
+type taskAPI interface {
+	Tasks(ctx context.Context, filter client.TasksFilter) ([]domain.Task, error)
+	CreateTask(ctx context.Context, body client.CreateTaskBody) (domain.Task, error)
+	UpdateTask(ctx context.Context, taskId int64, data domain.UpdateTaskData) (domain.Task, error)
+	DeleteTask(ctx context.Context, taskId int64) error
+}
+
+// Messages handled by the model. Calls to the API are made by commands,
+// which run in the background and report their result in a message.
+type (
+	msg any
+
+	keyMsg    key
+	resizeMsg struct{ width, height int }
+	tickMsg   time.Time
+
+	tasksMsg struct {
+		tasks []domain.Task
+		at    time.Time
+		err   error
+	}
+	savedMsg struct {
+		task domain.Task
+		err  error
+	}
+	deletedMsg struct {
+		taskId int64
+		err    error
+	}
+
+	eventMsg  domain.Event
+	streamMsg struct {
+		live bool // Whether the event stream is connected
+		err  error
+	}
+)
+
+// cmd calls the API in the background.
+type cmd func(context.Context) msg
+
+type viewMode int
+
+const (
+	viewList viewMode = iota
+	viewCalendar
+)
+
+// filter selects the tasks of the list.
+type filter int
+
+const (
+	filterOpen filter = iota
+	filterAll
+	filterDone
+	filterOverdue
+	filterBlocked
+	filterCount
+)
+
+var filterNames = [filterCount]string{"open", "all", "done", "overdue", "blocked"}
+
+func (f filter) String() string {
+	return filterNames[f]
+}
+
+func (f filter) matches(t domain.Task, now time.Time) bool {
+	switch f {
+	case filterOpen:
+		return !t.Completed
+	case filterDone:
+		return t.Completed
+	case filterOverdue:
+		return !t.Completed && t.Deadline.Before(now)
+	case filterBlocked:
+		return !t.Completed && t.Blocked
+	}
+
+	return true
+}
+
+// Fields of the form editing a task.
+const (
+	fieldName = iota
+	fieldDeadline
+	fieldDescription
+	fieldCount
+)
+
+var fieldLabels = [fieldCount]string{"Name", "Deadline", "Description"}
+
+// form edits a new or existing task.
+type form struct {
+	taskId  int64 // Zero for a new task
+	field   int
+	values  [fieldCount][]rune
+	initial [fieldCount]string // Values of the task being edited
+}
+
+// model is the state of the UI. It is only changed by update, and rendered by
+// view.
+type model struct {
+	api          taskAPI
+	pollInterval time.Duration // How often tasks are reloaded without events
+
+	now           time.Time
+	width, height int
+
+	tasks    []domain.Task // Every task, by deadline
+	loaded   bool
+	loading  bool
+	loadedAt time.Time
+	live     bool // Whether changes are received from the event stream
+
+	mode     viewMode
+	filter   filter
+	cursor   int       // Index of the selected task among the filtered ones
+	day      time.Time // Selected day of the calendar, at midnight
+	form     *form
+	deleting int64 // Task waiting for the deletion to be confirmed
+
+	status    string
+	statusErr bool
+	quit      bool
+}
+
+func newModel(api taskAPI, now time.Time, pollInterval time.Duration) model {
+	return model{
+		api:          api,
+		pollInterval: pollInterval,
+		now:          now,
+		width:        80,
+		height:       24,
+		day:          startOfDay(now),
+	}
+}
+
+// init returns the command loading the tasks at startup.
+func (m model) init() (model, cmd) {
+	return m.reload()
+}
+
+// update returns the model changed by the message, and the command to run
+// next, if any.
+func (m model) update(message msg) (model, cmd) {
+	switch message := message.(type) {
+	case keyMsg:
+		return m.handleKey(key(message))
+
+	case resizeMsg:
+		m.width, m.height = message.width, message.height
+
+	case tickMsg:
+		m.now = time.Time(message)
+		if !m.live && !m.loading && m.now.Sub(m.loadedAt) >= m.pollInterval {
+			return m.reload()
+		}
+
+	case tasksMsg:
+		m.loading = false
+		m.loadedAt = message.at
+		if message.err != nil {
+			m.setError(message.err)
+			break
+		}
+		selected, _ := m.selected()
+		m.tasks = message.tasks
+		m.loaded = true
+		sortByDeadline(m.tasks)
+		m.selectTask(selected.ID)
+
+	case savedMsg:
+		if message.err != nil {
+			m.setError(message.err)
+			break
+		}
+		m.apply(message.task)
+		m.setStatus("Saved %q", message.task.Name)
+		// Completing a task may unblock others.
+		return m.reload()
+
+	case deletedMsg:
+		if message.err != nil {
+			m.setError(message.err)
+			break
+		}
+		m.remove(message.taskId)
+		m.setStatus("Deleted task %d", message.taskId)
+		return m.reload()
+
+	case eventMsg:
+		return m.handleEvent(domain.Event(message))
+
+	case streamMsg:
+		m.live = message.live
+		if message.live {
+			// Changes made while disconnected were missed.
+			return m.reload()
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) handleEvent(event domain.Event) (model, cmd) {
+	switch event.Type {
+	case domain.EventTaskCreated, domain.EventTaskUpdated:
+		m.apply(event.Task)
+	case domain.EventTaskDeleted:
+		m.remove(event.Task.ID)
+	case domain.EventTaskReminder:
+		m.setStatus("Reminder: %q is due %s", event.Task.Name, deadline.Relative(event.Task.Deadline, m.now))
+		return m, nil
+	}
+
+	// Whether other tasks are blocked is only known by reloading them.
+	if event.Type != domain.EventTaskCreated {
+		return m.reload()
+	}
+
+	return m, nil
+}
+
+func (m model) handleKey(k key) (model, cmd) {
+	if k.code == keyCtrlC {
+		m.quit = true
+		return m, nil
+	}
+	if m.form != nil {
+		return m.handleFormKey(k)
+	}
+	if m.deleting != 0 {
+		taskId := m.deleting
+		m.deleting = 0
+		if k.code == keyRune && k.r == 'y' {
+			return m, m.deleteTask(taskId)
+		}
+		m.setStatus("")
+		return m, nil
+	}
+
+	switch {
+	case k.code == keyRune && k.r == 'q':
+		m.quit = true
+		return m, nil
+	case k.code == keyRune && k.r == 'r':
+		return m.reload()
+	case k.code == keyRune && k.r == 'c':
+		if m.mode == viewList {
+			m.mode = viewCalendar
+			if task, ok := m.selected(); ok {
+				m.day = startOfDay(task.Deadline.In(m.now.Location()))
+			}
+		} else {
+			m.mode = viewList
+		}
+		return m, nil
+	case k.code == keyRune && k.r == 'a':
+		f := &form{}
+		if m.mode == viewCalendar {
+			f.values[fieldDeadline] = []rune(m.day.Format(time.DateOnly))
+		} else {
+			f.values[fieldDeadline] = []rune("tomorrow")
+		}
+		m.form = f
+		return m, nil
+	}
+
+	if m.mode == viewCalendar {
+		return m.handleCalendarKey(k)
+	}
+
+	return m.handleListKey(k)
+}
+
+func (m model) handleListKey(k key) (model, cmd) {
+	task, selected := m.selected()
+
+	switch {
+	case k.code == keyDown || k.r == 'j':
+		m.cursor++
+		m.clampCursor()
+	case k.code == keyUp || k.r == 'k':
+		m.cursor--
+		m.clampCursor()
+	case k.r == 'g':
+		m.cursor = 0
+	case k.r == 'G':
+		m.cursor = len(m.visible()) - 1
+		m.clampCursor()
+	case k.r == 'f':
+		m.filter = (m.filter + 1) % filterCount
+		m.cursor = 0
+		m.setStatus("Showing %s tasks", m.filter)
+
+	case !selected:
+	case k.r == ' ' || k.r == 'x' || k.r == 'X':
+		completed := !task.Completed
+		return m, m.updateTask(task.ID, domain.UpdateTaskData{Completed: &completed, Force: k.r == 'X'})
+	case k.code == keyEnter || k.r == 'e':
+		f := &form{taskId: task.ID}
+		f.initial[fieldName] = task.Name
+		f.initial[fieldDeadline] = task.Deadline.In(m.now.Location()).Format("2006-01-02 15:04")
+		f.initial[fieldDescription] = task.Description
+		for field, value := range f.initial {
+			f.values[field] = []rune(value)
+		}
+		m.form = f
+	case k.r == 'd':
+		m.deleting = task.ID
+		m.setStatus("Delete %q? Press y to confirm", task.Name)
+	}
+
+	return m, nil
+}
+
+func (m model) handleCalendarKey(k key) (model, cmd) {
+	switch {
+	case k.code == keyEsc:
+		m.mode = viewList
+	case k.code == keyRight || k.r == 'l':
+		m.day = m.day.AddDate(0, 0, 1)
+	case k.code == keyLeft || k.r == 'h':
+		m.day = m.day.AddDate(0, 0, -1)
+	case k.code == keyDown || k.r == 'j':
+		m.day = m.day.AddDate(0, 0, 7)
+	case k.code == keyUp || k.r == 'k':
+		m.day = m.day.AddDate(0, 0, -7)
+	case k.r == '>':
+		m.day = m.day.AddDate(0, 1, 0)
+	case k.r == '<':
+		m.day = m.day.AddDate(0, -1, 0)
+	case k.r == 't':
+		m.day = startOfDay(m.now)
+	}
+
+	return m, nil
+}
+
+func (m model) handleFormKey(k key) (model, cmd) {
+	// The form is shared with the previous model, so it is copied before
+	// being changed.
+	f := *m.form
+	f.values[f.field] = slices.Clone(f.values[f.field])
+	m.form = &f
+
+	switch k.code {
+	case keyEsc:
+		m.form = nil
+		m.setStatus("")
+	case keyTab, keyDown:
+		f.field = (f.field + 1) % fieldCount
+	case keyBacktab, keyUp:
+		f.field = (f.field + fieldCount - 1) % fieldCount
+	case keyBackspace:
+		if n := len(f.values[f.field]); n > 0 {
+			f.values[f.field] = f.values[f.field][:n-1]
+		}
+	case keyRune:
+		f.values[f.field] = append(f.values[f.field], k.r)
+	case keyEnter:
+		return m.submit()
+	}
+
+	return m, nil
+}
+
+// submit creates or updates the task of the form. Only the fields changed
+// are updated, so deadlines keep their seconds unless edited.
+func (m model) submit() (model, cmd) {
+	f := m.form
+	var values [fieldCount]string
+	for field := range values {
+		values[field] = strings.TrimSpace(string(f.values[field]))
+	}
+	if len(values[fieldName]) == 0 {
+		m.setError(errors.New("the name is required"))
+		return m, nil
+	}
+	if len(values[fieldDescription]) == 0 {
+		// The API requires a description, which short tasks don't need.
+		values[fieldDescription] = values[fieldName]
+	}
+
+	var data domain.UpdateTaskData
+	if f.taskId == 0 || values[fieldDeadline] != f.initial[fieldDeadline] {
+		due, err := deadline.Parse(values[fieldDeadline], m.now)
+		if err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		data.Deadline = &due
+	}
+	if values[fieldName] != f.initial[fieldName] {
+		data.Name = &values[fieldName]
+	}
+	if values[fieldDescription] != f.initial[fieldDescription] {
+		data.Description = &values[fieldDescription]
+	}
+
+	m.form = nil
+	if f.taskId == 0 {
+		m.setStatus("Saving %q", values[fieldName])
+		body := client.CreateTaskBody{Name: values[fieldName], Description: values[fieldDescription], Deadline: data.Deadline.Format(time.RFC3339)}
+		return m, func(ctx context.Context) msg {
+			task, err := m.api.CreateTask(ctx, body)
+			return savedMsg{task: task, err: err}
+		}
+	}
+	if data == (domain.UpdateTaskData{}) {
+		m.setStatus("")
+		return m, nil
+	}
+
+	m.setStatus("Saving %q", values[fieldName])
+	return m, m.updateTask(f.taskId, data)
+}
+
+func (m model) reload() (model, cmd) {
+	m.loading = true
+	return m, func(ctx context.Context) msg {
+		tasks, err := m.api.Tasks(ctx, client.TasksFilter{})
+		return tasksMsg{tasks: tasks, at: time.Now(), err: err}
+	}
+}
+
+func (m model) updateTask(taskId int64, data domain.UpdateTaskData) cmd {
+	return func(ctx context.Context) msg {
+		task, err := m.api.UpdateTask(ctx, taskId, data)
+		return savedMsg{task: task, err: err}
+	}
+}
+
+func (m model) deleteTask(taskId int64) cmd {
+	return func(ctx context.Context) msg {
+		return deletedMsg{taskId: taskId, err: m.api.DeleteTask(ctx, taskId)}
+	}
+}
+
+// visible returns the tasks of the list, those matching the filter.
+func (m model) visible() []domain.Task {
+	var tasks []domain.Task
+	for _, t := range m.tasks {
+		if m.filter.matches(t, m.now) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks
+}
+
+// dueOn returns the tasks due on the day.
+func (m model) dueOn(day time.Time) []domain.Task {
+	var tasks []domain.Task
+	for _, t := range m.tasks {
+		if startOfDay(t.Deadline.In(day.Location())).Equal(day) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks
+}
+
+func (m model) selected() (domain.Task, bool) {
+	tasks := m.visible()
+	if m.cursor < 0 || m.cursor >= len(tasks) {
+		return domain.Task{}, false
+	}
+
+	return tasks[m.cursor], true
+}
+
+// selectTask moves the cursor to the task, which keeps its place when tasks
+// change, or within the list if it is no longer listed.
+func (m *model) selectTask(taskId int64) {
+	if i := slices.IndexFunc(m.visible(), func(t domain.Task) bool { return t.ID == taskId }); i >= 0 {
+		m.cursor = i
+		return
+	}
+	m.clampCursor()
+}
+
+func (m *model) clampCursor() {
+	m.cursor = max(0, min(m.cursor, len(m.visible())-1))
+}
+
+// apply adds or replaces a task. The slice is copied since it is shared with
+// the previous model.
+func (m *model) apply(task domain.Task) {
+	selected, _ := m.selected()
+	m.tasks = slices.Clone(m.tasks)
+	if i := slices.IndexFunc(m.tasks, func(t domain.Task) bool { return t.ID == task.ID }); i >= 0 {
+		m.tasks[i] = task
+	} else {
+		m.tasks = append(m.tasks, task)
+	}
+	sortByDeadline(m.tasks)
+	m.selectTask(selected.ID)
+}
+
+func (m *model) remove(taskId int64) {
+	m.tasks = slices.DeleteFunc(slices.Clone(m.tasks), func(t domain.Task) bool { return t.ID == taskId })
+	m.clampCursor()
+}
+
+func (m *model) setStatus(format string, args ...any) {
+	m.status = fmt.Sprintf(format, args...)
+	m.statusErr = false
+}
+
+func (m *model) setError(err error) {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		m.status = apiErr.Detail
+	} else {
+		m.status = err.Error()
+	}
+	m.statusErr = true
+}
+
+// sortByDeadline sorts tasks by deadline, the earliest first.
+func sortByDeadline(tasks []domain.Task) {
+	slices.SortStableFunc(tasks, func(a, b domain.Task) int {
+		if c := a.Deadline.Compare(b.Deadline); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI records the calls of the model and answers them from its tasks.
+type fakeAPI struct {
+	tasks   []domain.Task
+	err     error
+	created []client.CreateTaskBody
+	updated map[int64]domain.UpdateTaskData
+	deleted []int64
+}
+
+func (a *fakeAPI) Tasks(context.Context, client.TasksFilter) ([]domain.Task, error) {
+	return append([]domain.Task(nil), a.tasks...), a.err
+}
+
+func (a *fakeAPI) CreateTask(_ context.Context, body client.CreateTaskBody) (domain.Task, error) {
+	a.created = append(a.created, body)
+	deadline, _ := time.Parse(time.RFC3339, body.Deadline)
+	return domain.Task{ID: 100, Name: body.Name, Description: body.Description, Deadline: deadline}, a.err
+}
+
+func (a *fakeAPI) UpdateTask(_ context.Context, taskId int64, data domain.UpdateTaskData) (domain.Task, error) {
+	if a.updated == nil {
+		a.updated = map[int64]domain.UpdateTaskData{}
+	}
+	a.updated[taskId] = data
+
+	for _, t := range a.tasks {
+		if t.ID == taskId {
+			if data.Completed != nil {
+				t.Completed = *data.Completed
+			}
+			if data.Name != nil {
+				t.Name = *data.Name
+			}
+			return t, a.err
+		}
+	}
+
+	return domain.Task{}, errors.New("task not found")
+}
+
+func (a *fakeAPI) DeleteTask(_ context.Context, taskId int64) error {
+	a.deleted = append(a.deleted, taskId)
+	return a.err
+}
+
+// now is a Wednesday afternoon.
+var now = time.Date(2025, time.January, 15, 14, 30, 0, 0, time.UTC)
+
+func testTasks() []domain.Task {
+	return []domain.Task{
+		{ID: 3, Name: "Pay rent", Description: "Pay rent", Deadline: now.Add(48 * time.Hour)},
+		{ID: 1, Name: "Buy pizza", Description: "Buy pizza", Deadline: now.Add(-time.Hour)},
+		{ID: 2, Name: "Eat pizza", Description: "Eat it", Deadline: now.Add(2 * time.Hour), Blocked: true},
+		{ID: 4, Name: "Call mom", Description: "Call mom", Deadline: now.Add(-24 * time.Hour), Completed: true},
+	}
+}
+
+// loadedModel returns a model with the tasks of the API loaded.
+func loadedModel(t *testing.T, api *fakeAPI) model {
+	t.Helper()
+
+	m, c := newModel(api, now, 30*time.Second).init()
+	require.NotNil(t, c)
+	assert.True(t, m.loading)
+
+	return updateWith(m, c)
+}
+
+// updateWith runs the command and updates the model with its message.
+func updateWith(m model, c cmd) model {
+	m, _ = m.update(c(context.Background()))
+	return m
+}
+
+// press updates the model with the keys, returning the last command.
+func press(m model, keys ...key) (model, cmd) {
+	var c cmd
+	for _, k := range keys {
+		m, c = m.update(keyMsg(k))
+	}
+
+	return m, c
+}
+
+func typeText(m model, text string) model {
+	for _, r := range text {
+		m, _ = m.update(keyMsg(runeKey(r)))
+	}
+
+	return m
+}
+
+func taskIDs(tasks []domain.Task) []int64 {
+	ids := []int64{}
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	return ids
+}
+
+func TestModel_Load(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	assert.True(t, m.loaded)
+	assert.False(t, m.loading)
+	assert.Equal(t, []int64{4, 1, 2, 3}, taskIDs(m.tasks))
+	assert.Equal(t, []int64{1, 2, 3}, taskIDs(m.visible()))
+
+	task, ok := m.selected()
+	require.True(t, ok)
+	assert.Equal(t, int64(1), task.ID)
+}
+
+func TestModel_LoadError(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{err: &client.Error{Status: 503, Detail: "service unavailable"}})
+
+	assert.False(t, m.loaded)
+	assert.True(t, m.statusErr)
+	assert.Equal(t, "service unavailable", m.status)
+}
+
+func TestModel_Navigation(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	tests := []struct {
+		key    key
+		cursor int
+	}{
+		{key{code: keyDown}, 1},
+		{runeKey('j'), 2},
+		{runeKey('j'), 2},
+		{key{code: keyUp}, 1},
+		{runeKey('g'), 0},
+		{runeKey('k'), 0},
+		{runeKey('G'), 2},
+	}
+
+	for _, tt := range tests {
+		m, _ = press(m, tt.key)
+		assert.Equal(t, tt.cursor, m.cursor, "after %+v", tt.key)
+	}
+}
+
+func TestModel_Filter(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	tests := []struct {
+		filter filter
+		ids    []int64
+	}{
+		{filterAll, []int64{4, 1, 2, 3}},
+		{filterDone, []int64{4}},
+		{filterOverdue, []int64{1}},
+		{filterBlocked, []int64{2}},
+		{filterOpen, []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		m, _ = press(m, runeKey('f'))
+		assert.Equal(t, tt.filter, m.filter)
+		assert.Equal(t, tt.ids, taskIDs(m.visible()), tt.filter.String())
+	}
+}
+
+func TestModel_ToggleCompletion(t *testing.T) {
+	api := &fakeAPI{tasks: testTasks()}
+	m := loadedModel(t, api)
+
+	m, c := press(m, key{code: keyDown}, runeKey('X'))
+	require.NotNil(t, c)
+	m, c = m.update(c(context.Background()))
+
+	require.Contains(t, api.updated, int64(2))
+	assert.True(t, *api.updated[2].Completed)
+	assert.True(t, api.updated[2].Force)
+	assert.Equal(t, `Saved "Eat pizza"`, m.status)
+	// The completed task is no longer listed, the next one is selected.
+	assert.Equal(t, []int64{1, 3}, taskIDs(m.visible()))
+	task, _ := m.selected()
+	assert.Equal(t, int64(3), task.ID)
+
+	// Tasks are reloaded since others may be unblocked.
+	require.NotNil(t, c)
+	assert.True(t, m.loading)
+}
+
+func TestModel_ToggleCompletionError(t *testing.T) {
+	api := &fakeAPI{tasks: testTasks()}
+	m := loadedModel(t, api)
+
+	api.err = &client.Error{Status: 409, Code: "task_blocked", Detail: "task is blocked by uncompleted tasks"}
+	m, c := press(m, key{code: keyDown}, runeKey(' '))
+	m, c = m.update(c(context.Background()))
+
+	assert.Nil(t, c)
+	assert.True(t, m.statusErr)
+	assert.Equal(t, "task is blocked by uncompleted tasks", m.status)
+	assert.False(t, api.updated[2].Force)
+}
+
+func TestModel_AddTask(t *testing.T) {
+	api := &fakeAPI{tasks: testTasks()}
+	m := loadedModel(t, api)
+
+	m, _ = press(m, runeKey('a'))
+	require.NotNil(t, m.form)
+	assert.Equal(t, "tomorrow", string(m.form.values[fieldDeadline]))
+
+	// Keys are typed in the form rather than handled as commands.
+	m = typeText(m, "Wash dishes")
+	m, _ = press(m, key{code: keyTab}, key{code: keyBackspace}, key{code: keyBackspace})
+	m = typeText(m, "ow 9am")
+	assert.Equal(t, "tomorrow 9am", string(m.form.values[fieldDeadline]))
+
+	m, c := press(m, key{code: keyEnter})
+	assert.Nil(t, m.form)
+	require.NotNil(t, c)
+	m = updateWith(m, c)
+
+	require.Len(t, api.created, 1)
+	assert.Equal(t, client.CreateTaskBody{Name: "Wash dishes", Description: "Wash dishes", Deadline: "2025-01-16T09:00:00Z"}, api.created[0])
+	assert.Contains(t, taskIDs(m.tasks), int64(100))
+}
+
+func TestModel_AddTaskInvalid(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	m, c := press(m, runeKey('a'), key{code: keyEnter})
+	assert.Nil(t, c)
+	assert.NotNil(t, m.form)
+	assert.Equal(t, "the name is required", m.status)
+
+	m = typeText(m, "Nap")
+	m, _ = press(m, key{code: keyTab})
+	m = typeText(m, " someday")
+	m, c = press(m, key{code: keyEnter})
+	assert.Nil(t, c)
+	assert.NotNil(t, m.form)
+	assert.True(t, m.statusErr)
+
+	m, _ = press(m, key{code: keyEsc})
+	assert.Nil(t, m.form)
+}
+
+func TestModel_EditTask(t *testing.T) {
+	api := &fakeAPI{tasks: testTasks()}
+	m := loadedModel(t, api)
+
+	m, _ = press(m, runeKey('e'))
+	require.NotNil(t, m.form)
+	assert.Equal(t, int64(1), m.form.taskId)
+	assert.Equal(t, "Buy pizza", string(m.form.values[fieldName]))
+	assert.Equal(t, "2025-01-15 13:30", string(m.form.values[fieldDeadline]))
+
+	before := m
+	m = typeText(m, "s")
+	// Editing doesn't change the previous model.
+	assert.Equal(t, "Buy pizza", string(before.form.values[fieldName]))
+
+	m, c := press(m, key{code: keyEnter})
+	updateWith(m, c)
+	require.Contains(t, api.updated, int64(1))
+	assert.Equal(t, "Buy pizzas", *api.updated[1].Name)
+	assert.Nil(t, api.updated[1].Deadline, "unchanged fields are not updated")
+	assert.Nil(t, api.updated[1].Description)
+}
+
+func TestModel_DeleteTask(t *testing.T) {
+	api := &fakeAPI{tasks: testTasks()}
+	m := loadedModel(t, api)
+
+	m, c := press(m, runeKey('d'), runeKey('n'))
+	assert.Nil(t, c)
+	assert.Empty(t, api.deleted)
+
+	m, c = press(m, runeKey('d'), runeKey('y'))
+	require.NotNil(t, c)
+	m = updateWith(m, c)
+	assert.Equal(t, []int64{1}, api.deleted)
+	assert.Equal(t, []int64{2, 3}, taskIDs(m.visible()))
+	assert.Equal(t, "Deleted task 1", m.status)
+}
+
+func TestModel_Events(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	m, c := m.update(streamMsg{live: true})
+	assert.True(t, m.live)
+	require.NotNil(t, c, "tasks are reloaded once connected")
+	m = updateWith(m, c)
+
+	created := domain.Task{ID: 5, Name: "Nap", Deadline: now.Add(time.Hour)}
+	m, c = m.update(eventMsg(domain.NewTaskEvent(domain.EventTaskCreated, created)))
+	assert.Nil(t, c)
+	assert.Equal(t, []int64{1, 5, 2, 3}, taskIDs(m.visible()))
+	task, _ := m.selected()
+	assert.Equal(t, int64(1), task.ID, "the selection is kept")
+
+	created.Completed = true
+	m, c = m.update(eventMsg(domain.NewTaskEvent(domain.EventTaskUpdated, created)))
+	assert.NotNil(t, c)
+	assert.Equal(t, []int64{1, 2, 3}, taskIDs(m.visible()))
+
+	m, _ = m.update(eventMsg(domain.NewTaskEvent(domain.EventTaskDeleted, domain.Task{ID: 1})))
+	assert.Equal(t, []int64{2, 3}, taskIDs(m.visible()))
+
+	m, c = m.update(eventMsg(domain.NewTaskEvent(domain.EventTaskReminder, testTasks()[0])))
+	assert.Nil(t, c)
+	assert.Equal(t, `Reminder: "Pay rent" is due in 2d`, m.status)
+}
+
+func TestModel_Polling(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	loadedAt := m.loadedAt
+
+	m, c := m.update(tickMsg(loadedAt.Add(10 * time.Second)))
+	assert.Nil(t, c)
+
+	m, c = m.update(tickMsg(loadedAt.Add(30 * time.Second)))
+	require.NotNil(t, c, "tasks are polled without events")
+	assert.True(t, m.loading)
+	m, c = m.update(tickMsg(loadedAt.Add(31 * time.Second)))
+	assert.Nil(t, c, "a single load is pending")
+
+	m, _ = m.update(tasksMsg{tasks: testTasks(), at: loadedAt.Add(31 * time.Second)})
+	m, _ = m.update(streamMsg{live: true})
+	m, _ = m.update(tasksMsg{tasks: testTasks(), at: loadedAt.Add(32 * time.Second)})
+	_, c = m.update(tickMsg(loadedAt.Add(time.Hour)))
+	assert.Nil(t, c, "tasks are not polled with events")
+
+	m, c = m.update(streamMsg{err: errors.New("connection lost")})
+	assert.False(t, m.live)
+	assert.Nil(t, c)
+	_, c = m.update(tickMsg(loadedAt.Add(time.Hour)))
+	assert.NotNil(t, c, "polling resumes without events")
+}
+
+func TestModel_Calendar(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	// The calendar opens on the day of the selected task.
+	m, _ = press(m, key{code: keyDown}, runeKey('c'))
+	assert.Equal(t, viewCalendar, m.mode)
+	assert.Equal(t, time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC), m.day)
+	assert.Equal(t, []int64{1, 2}, taskIDs(m.dueOn(m.day)))
+
+	tests := []struct {
+		key key
+		day time.Time
+	}{
+		{key{code: keyRight}, time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{runeKey('j'), time.Date(2025, time.January, 23, 0, 0, 0, 0, time.UTC)},
+		{runeKey('>'), time.Date(2025, time.February, 23, 0, 0, 0, 0, time.UTC)},
+		{runeKey('t'), time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{runeKey('h'), time.Date(2025, time.January, 14, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		m, _ = press(m, tt.key)
+		assert.Equal(t, tt.day, m.day, "after %+v", tt.key)
+	}
+
+	// New tasks are due on the selected day.
+	m, _ = press(m, runeKey('a'))
+	assert.Equal(t, "2025-01-14", string(m.form.values[fieldDeadline]))
+	m, _ = press(m, key{code: keyEsc}, key{code: keyEsc})
+	assert.Equal(t, viewList, m.mode)
+}
+
+func TestModel_Quit(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+
+	// q is typed in forms, ctrl-c always quits.
+	m, _ = press(m, runeKey('a'), runeKey('q'))
+	assert.False(t, m.quit)
+	m, _ = press(m, key{code: keyCtrlC})
+	assert.True(t, m.quit)
+
+	m = loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = press(m, runeKey('q'))
+	assert.True(t, m.quit)
+}
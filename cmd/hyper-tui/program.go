@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+)
+
+// Escape sequences controlling the screen.
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+// program runs the model, feeding it the messages of the terminal, the event
+// stream and the commands it returns.
+type program struct {
+	out  io.Writer
+	msgs chan msg
+}
+
+func newProgram(out io.Writer) *program {
+	return &program{out: out, msgs: make(chan msg, 64)}
+}
+
+// send passes a message to the model, unless the program has ended.
+func (p *program) send(ctx context.Context, message msg) {
+	select {
+	case p.msgs <- message:
+	case <-ctx.Done():
+	}
+}
+
+// run updates and renders the model until the user quits or the context is
+// canceled, and returns its last state.
+func (p *program) run(ctx context.Context, m model) model {
+	m, c := m.init()
+	for {
+		p.render(m)
+		if m.quit {
+			return m
+		}
+		if c != nil {
+			go func(c cmd) { p.send(ctx, c(ctx)) }(c)
+		}
+
+		select {
+		case <-ctx.Done():
+			return m
+		case message := <-p.msgs:
+			m, c = m.update(message)
+		}
+	}
+}
+
+// render redraws the screen, clearing what the previous frame left.
+func (p *program) render(m model) {
+	frame := strings.ReplaceAll(m.view(), "\n", "\x1b[K\n")
+	io.WriteString(p.out, "\x1b[H"+frame+"\x1b[K\x1b[J")
+}
+
+// readKeys sends the keys read from the input.
+func (p *program) readKeys(ctx context.Context, in io.Reader) {
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		for _, k := range decodeKeys(buf[:n]) {
+			p.send(ctx, keyMsg(k))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// tick sends the time every interval, updating the deadlines relative to it
+// and polling tasks while there are no events.
+func (p *program) tick(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.send(ctx, tickMsg(now))
+		}
+	}
+}
+
+// eventsSource opens event streams, implemented by *client.Client.
+type eventsSource interface {
+	Events(ctx context.Context) (*client.EventStream, error)
+}
+
+// streamEvents sends the events of the tasks, reconnecting to the stream
+// after a growing delay when it ends. The model polls tasks meanwhile.
+func (p *program) streamEvents(ctx context.Context, source eventsSource) {
+	const minWait, maxWait = 2 * time.Second, time.Minute
+	wait := minWait
+
+	for {
+		stream, err := source.Events(ctx)
+		if err == nil {
+			p.send(ctx, streamMsg{live: true})
+			wait = minWait
+			for event := range stream.Events() {
+				p.send(ctx, eventMsg(event))
+			}
+			err = stream.Err()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		p.send(ctx, streamMsg{err: err})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(2*wait, maxWait)
+	}
+}
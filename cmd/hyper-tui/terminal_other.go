@@ -0,0 +1,27 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+type terminal struct {
+	in  *os.File
+	out *os.File
+}
+
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("the terminal UI is not supported on this platform")
+}
+
+func (t *terminal) restore() error {
+	return nil
+}
+
+func (t *terminal) size() (int, int, error) {
+	return 0, 0, errors.ErrUnsupported
+}
+
+func notifyResize(chan<- os.Signal) {}
@@ -0,0 +1,65 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal is the terminal the UI is displayed in, in raw mode so keys are
+// read as they are pressed.
+type terminal struct {
+	in       *os.File
+	out      *os.File
+	original unix.Termios
+}
+
+// openTerminal switches the terminal of stdin to raw mode.
+func openTerminal() (*terminal, error) {
+	t := &terminal{in: os.Stdin, out: os.Stdout}
+	fd := int(t.in.Fd())
+
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, errors.New("stdin is not a terminal")
+	}
+	t.original = *termios
+
+	// Input is read byte by byte, without echo nor signals. Output is still
+	// processed so newlines return the cursor.
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// restore switches the terminal back to its original mode.
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, &t.original)
+}
+
+// size returns the number of columns and rows of the terminal.
+func (t *terminal) size() (int, int, error) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// notifyResize relays the signals sent when the terminal is resized.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, unix.SIGWINCH)
+}
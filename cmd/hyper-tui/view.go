@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/deadline"
+)
+
+// ANSI escape sequences styling the text.
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleDim     = "\x1b[2m"
+)
+
+const (
+	listHelp     = "↑/↓ move  space done  X force done  a add  e edit  d delete  f filter  c calendar  r reload  q quit"
+	calendarHelp = "←/→/↑/↓ day  </> month  t today  a add  c list  q quit"
+	formHelp     = "tab next field  enter save  esc cancel"
+)
+
+// view renders the model as lines of the screen, fitting its size.
+func (m model) view() string {
+	var body []string
+	help := listHelp
+	switch {
+	case m.form != nil:
+		body, help = m.viewForm(), formHelp
+	case m.mode == viewCalendar:
+		body, help = m.viewCalendar(), calendarHelp
+	default:
+		body = m.viewList(m.height - 4)
+	}
+
+	lines := append([]string{m.viewHeader(), ""}, body...)
+	for len(lines) < m.height-2 {
+		lines = append(lines, "")
+	}
+	lines = lines[:max(m.height-2, 0)]
+
+	status := m.status
+	if m.statusErr {
+		status = styleRed + "error: " + status + styleReset
+	}
+	lines = append(lines, status, styleDim+help+styleReset)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, m.width)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m model) viewHeader() string {
+	var parts []string
+	if m.mode == viewCalendar {
+		parts = append(parts, "calendar", m.day.Format("January 2006"))
+	} else {
+		parts = append(parts, fmt.Sprintf("%d %s tasks", len(m.visible()), m.filter))
+	}
+
+	switch {
+	case m.live:
+		parts = append(parts, "live")
+	case m.loaded:
+		parts = append(parts, "updated "+m.loadedAt.In(m.now.Location()).Format("15:04:05"))
+	default:
+		parts = append(parts, "loading")
+	}
+
+	return styleBold + "hyper-todo" + styleReset + " · " + strings.Join(parts, " · ")
+}
+
+// viewList renders the tasks matching the filter, scrolled to show the
+// selected one within the rows.
+func (m model) viewList(rows int) []string {
+	tasks := m.visible()
+	if len(tasks) == 0 {
+		if !m.loaded {
+			return nil
+		}
+		return []string{fmt.Sprintf("No %s tasks, press a to add one", m.filter)}
+	}
+
+	idWidth := 0
+	for _, t := range tasks {
+		idWidth = max(idWidth, len(fmt.Sprint(t.ID)))
+	}
+
+	offset := 0
+	if rows > 0 && m.cursor >= rows {
+		offset = m.cursor - rows + 1
+	}
+
+	var lines []string
+	for i := offset; i < len(tasks) && (rows <= 0 || i < offset+rows); i++ {
+		t := tasks[i]
+		line := fmt.Sprintf("%s %*d  %-18s %s", marker(t, m.now), idWidth, t.ID, deadline.Format(t.Deadline, m.now), t.Name)
+		switch {
+		case i == m.cursor:
+			line = styleReverse + pad(line, m.width) + styleReset
+		case !t.Completed && t.Deadline.Before(m.now):
+			line = styleRed + line + styleReset
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// viewCalendar renders the month of the selected day, marking days with
+// tasks due, followed by the tasks due on the selected day.
+func (m model) viewCalendar() []string {
+	lines := []string{" Mo  Tu  We  Th  Fr  Sa  Su"}
+
+	first := time.Date(m.day.Year(), m.day.Month(), 1, 0, 0, 0, 0, m.day.Location())
+	// Weeks start on Monday.
+	blanks := (int(first.Weekday()) + 6) % 7
+	week := strings.Repeat("    ", blanks)
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%3d", day.Day())
+		if len(m.dueOn(day)) > 0 {
+			cell += "*"
+		} else {
+			cell += " "
+		}
+		switch {
+		case day.Equal(m.day):
+			cell = styleReverse + cell + styleReset
+		case day.Equal(startOfDay(m.now)):
+			cell = styleBold + cell + styleReset
+		}
+		week += cell
+
+		if day.Weekday() == time.Sunday {
+			lines = append(lines, week)
+			week = ""
+		}
+	}
+	if len(week) > 0 {
+		lines = append(lines, week)
+	}
+
+	lines = append(lines, "", "Due "+m.day.Format("Mon Jan 2")+":")
+	tasks := m.dueOn(m.day)
+	if len(tasks) == 0 {
+		lines = append(lines, "  nothing")
+	}
+	for _, t := range tasks {
+		lines = append(lines, fmt.Sprintf("  %s %s %s", marker(t, m.now), t.Deadline.In(m.now.Location()).Format("15:04"), t.Name))
+	}
+
+	return lines
+}
+
+func (m model) viewForm() []string {
+	title := "New task"
+	if m.form.taskId != 0 {
+		title = fmt.Sprintf("Edit task %d", m.form.taskId)
+	}
+
+	lines := []string{styleBold + title + styleReset, ""}
+	for field, label := range fieldLabels {
+		value := string(m.form.values[field])
+		if field == m.form.field {
+			value = styleReverse + value + " " + styleReset
+		}
+		lines = append(lines, fmt.Sprintf("%12s: %s", label, value))
+	}
+
+	due, err := deadline.Parse(string(m.form.values[fieldDeadline]), m.now)
+	if err == nil {
+		lines = append(lines, "", fmt.Sprintf("%12s  %s (%s)", "", due.Format("Mon 2006-01-02 15:04"), deadline.Relative(due, m.now)))
+	}
+
+	return lines
+}
+
+// marker shows the state of a task in a checkbox.
+func marker(t domain.Task, now time.Time) string {
+	switch {
+	case t.Completed:
+		return "[x]"
+	case t.Blocked:
+		return "[-]"
+	case t.Deadline.Before(now):
+		return "[!]"
+	default:
+		return "[ ]"
+	}
+}
+
+// truncate cuts a line to the width, not counting escape sequences.
+func truncate(line string, width int) string {
+	if width <= 0 || visibleWidth(line) <= width {
+		return line
+	}
+
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b {
+			n := escapeLength([]byte(line[i:]))
+			b.WriteString(line[i : i+n])
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if visible < width {
+			b.WriteRune(r)
+			visible++
+		}
+		i += size
+	}
+
+	return b.String()
+}
+
+// pad pads a line with spaces to the width.
+func pad(line string, width int) string {
+	if n := width - visibleWidth(line); n > 0 {
+		return line + strings.Repeat(" ", n)
+	}
+
+	return line
+}
+
+// visibleWidth returns the number of characters of a line displayed on the
+// screen.
+func visibleWidth(line string) int {
+	width := 0
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b {
+			i += escapeLength([]byte(line[i:]))
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(line[i:])
+		width++
+		i += size
+	}
+
+	return width
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var escapes = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+// screen returns the lines of the view without styles and trailing spaces.
+func screen(m model) []string {
+	lines := strings.Split(escapes.ReplaceAllString(m.view(), ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return lines
+}
+
+func TestView_List(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = m.update(resizeMsg{width: 60, height: 10})
+
+	lines := screen(m)
+	require.Len(t, lines, 10)
+	assert.Equal(t, []string{
+		"hyper-todo · 3 open tasks · updated " + m.loadedAt.In(time.UTC).Format("15:04:05"),
+		"",
+		"[!] 1  today 13:30        Buy pizza",
+		"[-] 2  today 16:30        Eat pizza",
+		"[ ] 3  Fri Jan 17 14:30   Pay rent",
+		"",
+		"",
+		"",
+		"",
+		"↑/↓ move  space done  X force done  a add  e edit  d delete",
+	}, lines)
+
+	// The selected line is highlighted.
+	assert.Contains(t, m.view(), styleReverse+"[!] 1")
+
+	m, _ = m.update(streamMsg{live: true})
+	m, _ = press(m, runeKey('d'))
+	lines = screen(m)
+	assert.Equal(t, "hyper-todo · 3 open tasks · live", lines[0])
+	assert.Equal(t, `Delete "Buy pizza"? Press y to confirm`, lines[8])
+}
+
+func TestView_ListScrolls(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = m.update(resizeMsg{width: 80, height: 6})
+
+	m, _ = press(m, runeKey('G'))
+	lines := screen(m)
+	require.Len(t, lines, 6)
+	// Two rows fit, the selected task is the last one.
+	assert.Contains(t, lines[2], "Eat pizza")
+	assert.Contains(t, lines[3], "Pay rent")
+}
+
+func TestView_Empty(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{})
+	assert.Equal(t, "No open tasks, press a to add one", screen(m)[2])
+
+	m, _ = newModel(&fakeAPI{}, now, time.Minute).init()
+	assert.Equal(t, "hyper-todo · 0 open tasks · loading", screen(m)[0])
+}
+
+func TestView_Error(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = press(m, runeKey('a'), key{code: keyEnter})
+
+	view := m.view()
+	assert.Contains(t, view, styleRed+"error: the name is required"+styleReset)
+}
+
+func TestView_Calendar(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = m.update(resizeMsg{width: 40, height: 20})
+	m, _ = press(m, runeKey('c'))
+
+	lines := screen(m)
+	assert.Equal(t, []string{
+		"hyper-todo · calendar · January 2025 · u",
+		"",
+		" Mo  Tu  We  Th  Fr  Sa  Su",
+		"          1   2   3   4   5",
+		"  6   7   8   9  10  11  12",
+		" 13  14* 15* 16  17* 18  19",
+		" 20  21  22  23  24  25  26",
+		" 27  28  29  30  31",
+		"",
+		"Due Wed Jan 15:",
+		"  [!] 13:30 Buy pizza",
+		"  [-] 16:30 Eat pizza",
+	}, lines[:12])
+	assert.Equal(t, "←/→/↑/↓ day  </> month  t today  a add", lines[19])
+	assert.Contains(t, m.view(), styleReverse+" 15*"+styleReset)
+
+	m, _ = press(m, runeKey('l'))
+	lines = screen(m)
+	assert.Equal(t, []string{"Due Thu Jan 16:", "  nothing"}, lines[9:11])
+}
+
+func TestView_Form(t *testing.T) {
+	m := loadedModel(t, &fakeAPI{tasks: testTasks()})
+	m, _ = press(m, runeKey('e'), key{code: keyTab})
+
+	lines := screen(m)
+	assert.Equal(t, []string{
+		"Edit task 1",
+		"",
+		"        Name: Buy pizza",
+		"    Deadline: 2025-01-15 13:30",
+		" Description: Buy pizza",
+		"",
+		"              Wed 2025-01-15 13:30 (1h ago)",
+	}, lines[2:9])
+	assert.Contains(t, m.view(), styleReverse+"2025-01-15 13:30 "+styleReset)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	assert.Equal(t, styleBold+"ab"+styleReset, truncate(styleBold+"abcd"+styleReset, 2))
+	assert.Equal(t, "éà", truncate("éàü", 2))
+	assert.Equal(t, "short", truncate("short", 10))
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/credentials"
+	"github.com/krau5/hyper-todo/internal/deadline"
+	"golang.org/x/term"
+)
+
+// flags returns the flag set of a command, printing its usage on errors.
+func (c *cli) flags(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: hyper %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses the arguments of a command, which takes between min and max
+// positional arguments, max being -1 for any number.
+func parse(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() < min || (max >= 0 && fs.NArg() > max) {
+		fs.Usage()
+		return errUsage
+	}
+
+	return nil
+}
+
+func (c *cli) login(ctx context.Context, args []string) error {
+	fs := c.flags("login", "[-register] [-name name] <email>")
+	register := fs.Bool("register", false, "create the account first")
+	name := fs.String("name", "", "`name` of the account to create, the local part of the email by default")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	email := fs.Arg(0)
+
+	password, err := c.readPassword()
+	if err != nil {
+		return err
+	}
+
+	api, err := client.New(c.serverURL())
+	if err != nil {
+		return err
+	}
+
+	if *register {
+		if len(*name) == 0 {
+			*name, _, _ = strings.Cut(email, "@")
+		}
+		if err := api.Register(ctx, client.RegisterBody{Name: *name, Email: email, Password: password}); err != nil {
+			return err
+		}
+	}
+
+	token, err := api.Login(ctx, email, password)
+	if err != nil {
+		return err
+	}
+
+	c.creds = credentials.Credentials{Server: c.serverURL(), Email: email, Token: token}
+	if err := credentials.Save(c.configDir, c.creds); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+
+	fmt.Fprintf(c.stderr, "Logged in to %s as %s\n", c.creds.Server, email)
+	return nil
+}
+
+// readPassword reads the password from HYPER_PASSWORD or the first line of
+// stdin, prompting for it without echoing it on terminals.
+func (c *cli) readPassword() (string, error) {
+	if password := c.getenv("HYPER_PASSWORD"); len(password) != 0 {
+		return password, nil
+	}
+
+	var (
+		password string
+		err      error
+	)
+	if f, ok := c.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(c.stderr, "Password: ")
+		var raw []byte
+		raw, err = term.ReadPassword(int(f.Fd()))
+		// The newline typed by the user isn't echoed either.
+		fmt.Fprintln(c.stderr)
+		password = string(raw)
+	} else {
+		var line string
+		line, err = bufio.NewReader(c.stdin).ReadString('\n')
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	if len(password) == 0 {
+		if err != nil {
+			return "", fmt.Errorf("reading password: %w", err)
+		}
+		return "", errors.New("empty password")
+	}
+
+	return password, nil
+}
+
+func (c *cli) add(ctx context.Context, args []string) error {
+	fs := c.flags("add", "[-d deadline] [-desc text] <name>")
+	rawDeadline := fs.String("d", "tomorrow", "`deadline` of the task")
+	description := fs.String("desc", "", "`description` of the task, the name by default")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+	name := strings.Join(fs.Args(), " ")
+	if len(*description) == 0 {
+		// The API requires a description, which short tasks don't need.
+		*description = name
+	}
+
+	due, err := deadline.Parse(*rawDeadline, c.now())
+	if err != nil {
+		return err
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	task, err := api.CreateTask(ctx, client.CreateTaskBody{
+		Name:        name,
+		Description: *description,
+		Deadline:    due.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	return printTask(c.stdout, c.format, task, c.now())
+}
+
+func (c *cli) ls(ctx context.Context, args []string) error {
+	fs := c.flags("ls", "[-all] [-done] [-blocked] [-overdue] [-due deadline]")
+	all := fs.Bool("all", false, "include completed tasks")
+	done := fs.Bool("done", false, "only list completed tasks")
+	blocked := fs.Bool("blocked", false, "only list tasks blocked by other tasks")
+	overdue := fs.Bool("overdue", false, "only list open tasks past their deadline")
+	rawDue := fs.String("due", "", "only list tasks due before the `deadline`")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	now := c.now()
+	var due time.Time
+	if len(*rawDue) != 0 {
+		var err error
+		if due, err = deadline.Parse(*rawDue, now); err != nil {
+			return err
+		}
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	filter := client.TasksFilter{}
+	if *blocked {
+		filter.Blocked = blocked
+	}
+	tasks, err := api.Tasks(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	tasks = slices.DeleteFunc(tasks, func(t domain.Task) bool {
+		switch {
+		case *done && !t.Completed:
+			return true
+		case !*done && !*all && t.Completed:
+			return true
+		case *overdue && (t.Completed || !t.Deadline.Before(now)):
+			return true
+		case !due.IsZero() && !t.Deadline.Before(due):
+			return true
+		}
+		return false
+	})
+	sortByDeadline(tasks)
+
+	return printTasks(c.stdout, c.format, tasks, now)
+}
+
+func (c *cli) show(ctx context.Context, args []string) error {
+	fs := c.flags("show", "<id>")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs)
+	if err != nil {
+		return err
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	// The API has no route for a single task, it is found among all of them.
+	tasks, err := api.Tasks(ctx, client.TasksFilter{})
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(tasks, func(t domain.Task) bool { return t.ID == ids[0] })
+	if i < 0 {
+		return fmt.Errorf("task %d not found", ids[0])
+	}
+
+	dependencies, err := api.Dependencies(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	reminders, err := api.Reminders(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+
+	details := taskDetails{Task: tasks[i], BlockedBy: []int64{}, Blocks: []int64{}, Reminders: reminders.Offsets}
+	for _, t := range dependencies.BlockedBy {
+		details.BlockedBy = append(details.BlockedBy, t.ID)
+	}
+	for _, t := range dependencies.Blocks {
+		details.Blocks = append(details.Blocks, t.ID)
+	}
+
+	return printTaskDetails(c.stdout, c.format, details, c.now())
+}
+
+func (c *cli) edit(ctx context.Context, args []string) error {
+	fs := c.flags("edit", "[-name name] [-desc text] [-d deadline] [-reopen] <id>")
+	name := fs.String("name", "", "new `name` of the task")
+	description := fs.String("desc", "", "new `description` of the task")
+	rawDeadline := fs.String("d", "", "new `deadline` of the task")
+	reopen := fs.Bool("reopen", false, "mark the task as not completed")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs)
+	if err != nil {
+		return err
+	}
+
+	var data domain.UpdateTaskData
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			data.Name = name
+		case "desc":
+			data.Description = description
+		case "reopen":
+			if *reopen {
+				completed := false
+				data.Completed = &completed
+			}
+		case "d":
+			var due time.Time
+			if due, err = deadline.Parse(*rawDeadline, c.now()); err == nil {
+				data.Deadline = &due
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if data == (domain.UpdateTaskData{}) {
+		fs.Usage()
+		return errUsage
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	task, err := api.UpdateTask(ctx, ids[0], data)
+	if err != nil {
+		return err
+	}
+
+	return printTask(c.stdout, c.format, task, c.now())
+}
+
+func (c *cli) done(ctx context.Context, args []string) error {
+	fs := c.flags("done", "[-force] <id>...")
+	force := fs.Bool("force", false, "complete tasks even if they are blocked")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs)
+	if err != nil {
+		return err
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	completed := true
+	var tasks []domain.Task
+	for _, id := range ids {
+		task, err := api.UpdateTask(ctx, id, domain.UpdateTaskData{Completed: &completed, Force: *force})
+		if err != nil {
+			printTasks(c.stdout, c.format, tasks, c.now())
+			return fmt.Errorf("task %d: %w", id, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return printTasks(c.stdout, c.format, tasks, c.now())
+}
+
+func (c *cli) rm(ctx context.Context, args []string) error {
+	fs := c.flags("rm", "<id>...")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs)
+	if err != nil {
+		return err
+	}
+
+	api, save, err := c.client()
+	if err != nil {
+		return err
+	}
+	defer save()
+
+	for _, id := range ids {
+		if err := api.DeleteTask(ctx, id); err != nil {
+			return fmt.Errorf("task %d: %w", id, err)
+		}
+		if c.format == formatTable {
+			fmt.Fprintf(c.stdout, "Deleted task %d\n", id)
+		}
+	}
+
+	return nil
+}
+
+// parseIDs parses the positional arguments as task IDs.
+func parseIDs(fs *flag.FlagSet) ([]int64, error) {
+	ids := make([]int64, fs.NArg())
+	for i, arg := range fs.Args() {
+		id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid task ID %q", arg)
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// sortByDeadline sorts tasks by deadline, the earliest first.
+func sortByDeadline(tasks []domain.Task) {
+	slices.SortStableFunc(tasks, func(a, b domain.Task) int {
+		if c := a.Deadline.Compare(b.Deadline); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+}
@@ -0,0 +1,202 @@
+// Command hyper manages the tasks of a hyper-todo account from the terminal.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/client"
+	"github.com/krau5/hyper-todo/internal/credentials"
+	"github.com/krau5/hyper-todo/internal/deadline"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// refreshBefore is how long before it expires the saved token is refreshed.
+const refreshBefore = 30 * time.Minute
+
+const usage = `Usage: hyper [flags] <command> [arguments]
+
+Commands:
+  login [-register] [-name name] <email>   log in, reading the password from stdin or HYPER_PASSWORD
+  add [-d deadline] [-desc text] <name>    add a task
+  ls [-all] [-done] [-blocked] [-overdue] [-due deadline]
+                                           list tasks, open ones by default, by deadline
+  show <id>                                show a task with its dependencies and reminders
+  edit [-name name] [-desc text] [-d deadline] [-reopen] <id>
+                                           edit a task
+  done [-force] <id>...                    complete tasks, even blocked ones with -force
+  rm <id>...                               delete tasks
+
+Run "hyper <command> -h" for the flags of a command.
+
+Flags:
+`
+
+// cli runs commands, writing their output to stdout and errors to stderr.
+type cli struct {
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	configDir string // Where credentials are saved
+	getenv    func(string) string
+	now       func() time.Time
+
+	server string // Overrides the saved server
+	format string
+	creds  credentials.Credentials
+}
+
+func main() {
+	configDir, err := credentials.DefaultDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	c := &cli{
+		stdin:     os.Stdin,
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+		configDir: configDir,
+		getenv:    os.Getenv,
+		now:       time.Now,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := c.run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// run runs the command of the arguments and returns the exit code.
+func (c *cli) run(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("hyper", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprint(c.stderr, usage)
+		fs.PrintDefaults()
+		fmt.Fprintf(c.stderr, "\n%s\n", deadline.Help)
+	}
+	fs.StringVar(&c.server, "server", c.getenv("HYPER_SERVER"), "`URL` of the API, "+defaultServer+" or the one logged in to by default (HYPER_SERVER)")
+	fs.StringVar(&c.format, "o", formatTable, "output `format`: table, json or plain")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if !validFormat(c.format) || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"login": c.login,
+		"add":   c.add,
+		"ls":    c.ls,
+		"show":  c.show,
+		"edit":  c.edit,
+		"done":  c.done,
+		"rm":    c.rm,
+	}
+	command, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(c.stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	var err error
+	if c.creds, err = credentials.Load(c.configDir); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+
+	err = command(ctx, fs.Args()[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	}
+
+	c.printError(err)
+	return 1
+}
+
+// errUsage is returned for invalid arguments of a command, once its usage
+// has been printed.
+var errUsage = errors.New("invalid usage")
+
+// printError prints why a command failed, with the fields rejected by the API.
+func (c *cli) printError(err error) {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		fmt.Fprintf(c.stderr, "error: %v\n", err)
+		return
+	}
+
+	switch apiErr.Code {
+	case "missing_token", "invalid_token", "session_revoked":
+		fmt.Fprintln(c.stderr, "error: session expired, run hyper login again")
+		return
+	}
+
+	fmt.Fprintf(c.stderr, "error: %s\n", apiErr.Detail)
+	for _, fieldErr := range apiErr.Errors {
+		fmt.Fprintf(c.stderr, "  %s: %s\n", fieldErr.Field, fieldErr.Message)
+	}
+}
+
+// serverURL returns the URL of the API, from the flag, the saved credentials
+// or the default one.
+func (c *cli) serverURL() string {
+	switch {
+	case len(c.server) != 0:
+		return c.server
+	case len(c.creds.Server) != 0:
+		return c.creds.Server
+	}
+
+	return defaultServer
+}
+
+// client returns a client authenticated with the saved token, saving it
+// again when the client refreshes it.
+func (c *cli) client() (*client.Client, func(), error) {
+	if len(c.creds.Token.Token) == 0 {
+		return nil, nil, credentials.ErrNotLoggedIn
+	}
+	if len(c.server) != 0 && strings.TrimSuffix(c.server, "/") != strings.TrimSuffix(c.creds.Server, "/") {
+		return nil, nil, fmt.Errorf("logged in to %s, run hyper login to use %s", c.creds.Server, c.server)
+	}
+
+	// Commands are run now and then, so tokens are refreshed well before they
+	// expire to keep the session alive between them.
+	api, err := client.New(c.serverURL(), client.WithToken(c.creds.Token), client.WithRefreshBefore(refreshBefore))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	save := func() {
+		if token := api.Token(); token.Token != c.creds.Token.Token {
+			c.creds.Token = token
+			if err := credentials.Save(c.configDir, c.creds); err != nil {
+				fmt.Fprintf(c.stderr, "warning: failed to save the refreshed token: %v\n", err)
+			}
+		}
+	}
+
+	return api, save, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/internal/credentials"
+	"github.com/krau5/hyper-todo/internal/lifecycle"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/krau5/hyper-todo/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// apiURL is the URL of a server running the real router, shared by the
+// tests since the router registers metrics globally.
+var apiURL string
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.Storage.SQLitePath = ":memory:"
+	cfg.Auth.JwtSecretKey = "0123456789abcdef0123456789abcdef"
+	cfg.RateLimit.Enabled = false
+
+	db, err := repository.OpenSQLite(cfg.Storage.SQLitePath, &gorm.Config{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	app := lifecycle.New()
+	router, err := server.NewRouter(cfg, db, zap.NewNop(), app)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	srv := httptest.NewServer(router)
+	apiURL = srv.URL
+
+	code := m.Run()
+
+	srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	app.Shutdown(ctx)
+
+	os.Exit(code)
+}
+
+// testCLI runs commands with the credentials saved in a temporary directory.
+type testCLI struct {
+	t         *testing.T
+	configDir string
+	now       time.Time
+}
+
+func newTestCLI(t *testing.T) *testCLI {
+	return &testCLI{t: t, configDir: t.TempDir(), now: time.Now()}
+}
+
+// run runs a command reading stdin, returning its exit code and output.
+func (tc *testCLI) run(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	c := &cli{
+		stdin:     strings.NewReader(stdin),
+		stdout:    &stdout,
+		stderr:    &stderr,
+		configDir: tc.configDir,
+		getenv:    func(string) string { return "" },
+		now:       func() time.Time { return tc.now },
+	}
+
+	code := c.run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+// ok runs a command which must succeed and returns its output.
+func (tc *testCLI) ok(args ...string) string {
+	tc.t.Helper()
+	code, stdout, stderr := tc.run("", args...)
+	require.Equal(tc.t, 0, code, "stderr: %s", stderr)
+	return stdout
+}
+
+func TestCLI(t *testing.T) {
+	tc := newTestCLI(t)
+
+	code, _, stderr := tc.run("", "ls")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "error: "+credentials.ErrNotLoggedIn.Error()+"\n", stderr)
+
+	code, _, stderr = tc.run("Password_123\n", "-server", apiURL, "login", "-register", "-name", "Jane", "jane@example.com")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "Logged in to "+apiURL+" as jane@example.com")
+
+	creds, err := credentials.Load(tc.configDir)
+	require.NoError(t, err)
+	assert.Equal(t, apiURL, creds.Server)
+	assert.NotEmpty(t, creds.Token.Token)
+	info, err := os.Stat(credentials.Path(tc.configDir))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	out := tc.ok("-o", "plain", "add", "-d", "tomorrow 17:00", "-desc", "Buy the pizza", "Buy", "pizza")
+	fields := strings.Split(strings.TrimSpace(out), "\t")
+	require.Len(t, fields, 4)
+	buy := fields[0]
+	assert.Equal(t, "open", fields[1])
+	tomorrow := tc.now.AddDate(0, 0, 1)
+	expected := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 17, 0, 0, 0, time.Local)
+	deadline, err := time.Parse(time.RFC3339, fields[2])
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(deadline), "expected %s, got %s", expected, deadline)
+	assert.Equal(t, "Buy pizza", fields[3])
+
+	out = tc.ok("-o", "plain", "add", "-d", "in 1h", "Eat")
+	eat := strings.Split(out, "\t")[0]
+
+	out = tc.ok("ls")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^ID\s+STATUS\s+DEADLINE\s+NAME$`, lines[0])
+	assert.Regexp(t, `^`+eat+`\s+open\s+today .*Eat$`, lines[1])
+	assert.Regexp(t, `^`+buy+`\s+open\s+.*Buy pizza$`, lines[2])
+
+	out = tc.ok("-o", "plain", "ls", "-due", "in 2h")
+	assert.Equal(t, []string{eat}, ids(out))
+
+	tc.ok("-o", "plain", "done", eat)
+	assert.Equal(t, []string{buy}, ids(tc.ok("-o", "plain", "ls")))
+	assert.Equal(t, []string{eat}, ids(tc.ok("-o", "plain", "ls", "-done")))
+	assert.Equal(t, []string{eat, buy}, ids(tc.ok("-o", "plain", "ls", "-all")))
+
+	out = tc.ok("-o", "json", "edit", "-name", "Buy a pizza", "-reopen", buy)
+	var task struct {
+		Name      string `json:"name"`
+		Completed bool   `json:"completed"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &task))
+	assert.Equal(t, "Buy a pizza", task.Name)
+
+	out = tc.ok("show", buy)
+	assert.Contains(t, out, "Name:      Buy a pizza")
+	assert.Contains(t, out, "Status:    open")
+	assert.Contains(t, out, "\nBuy the pizza\n")
+
+	tc.now = tc.now.Add(30 * time.Hour)
+	assert.Equal(t, []string{buy}, ids(tc.ok("-o", "plain", "ls", "-overdue")))
+
+	code, _, stderr = tc.run("", "-o", "plain", "edit", buy)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage: hyper edit")
+
+	code, _, stderr = tc.run("", "add", "-d", "someday", "Nap")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "error: invalid deadline \"someday\"\n", stderr)
+
+	code, _, stderr = tc.run("", "add", "-d", "2000-01-01", "")
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.HasPrefix(stderr, "error: "), stderr)
+
+	assert.Equal(t, "Deleted task "+buy+"\nDeleted task "+eat+"\n", tc.ok("rm", buy, eat))
+	assert.Equal(t, "No tasks\n", tc.ok("ls", "-all"))
+
+	code, _, stderr = tc.run("", "show", buy)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "error: task "+buy+" not found\n", stderr)
+}
+
+func TestCLI_RefreshesToken(t *testing.T) {
+	tc := newTestCLI(t)
+	code, _, stderr := tc.run("Password_123\n", "-server", apiURL, "login", "-register", "refresh@example.com")
+	require.Equal(t, 0, code, stderr)
+
+	creds, err := credentials.Load(tc.configDir)
+	require.NoError(t, err)
+
+	// Within 30 minutes of its expiry, the token is replaced by a new one.
+	creds.Token.ExpiresAt = time.Now().Add(10 * time.Minute)
+	require.NoError(t, credentials.Save(tc.configDir, creds))
+	time.Sleep(time.Second) // Tokens issued in the same second are identical
+	tc.ok("ls")
+
+	refreshed, err := credentials.Load(tc.configDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, creds.Token.Token, refreshed.Token.Token)
+	assert.True(t, refreshed.Token.ExpiresAt.After(creds.Token.ExpiresAt))
+}
+
+func TestCLI_Usage(t *testing.T) {
+	tc := newTestCLI(t)
+
+	code, _, stderr := tc.run("", "frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+
+	code, _, stderr = tc.run("", "-o", "yaml", "ls")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage: hyper")
+
+	code, _, _ = tc.run("", "done", "-h")
+	assert.Equal(t, 0, code)
+
+	code, _, stderr = tc.run("", "rm", "first")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "error: invalid task ID \"first\"\n", stderr)
+}
+
+// ids returns the IDs of tasks printed in the plain format.
+func ids(out string) []string {
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if id, _, ok := strings.Cut(line, "\t"); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/deadline"
+)
+
+// Output formats of the commands.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatPlain = "plain"
+)
+
+func validFormat(format string) bool {
+	switch format {
+	case formatTable, formatJSON, formatPlain:
+		return true
+	}
+
+	return false
+}
+
+// taskStatus summarizes the state of a task.
+func taskStatus(t domain.Task, now time.Time) string {
+	switch {
+	case t.Completed:
+		return "done"
+	case t.Blocked:
+		return "blocked"
+	case t.Deadline.Before(now):
+		return "overdue"
+	default:
+		return "open"
+	}
+}
+
+// printTasks prints tasks in the format: a table with a header, JSON, or one
+// tab-separated line per task meant for scripts.
+func printTasks(w io.Writer, format string, tasks []domain.Task, now time.Time) error {
+	switch format {
+	case formatJSON:
+		return printJSON(w, tasks)
+
+	case formatPlain:
+		for _, t := range tasks {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.ID, taskStatus(t, now), t.Deadline.Format(time.RFC3339), t.Name)
+		}
+		return nil
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintln(w, "No tasks")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tDEADLINE\tNAME")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", t.ID, taskStatus(t, now), deadline.Format(t.Deadline, now), t.Name)
+	}
+
+	return tw.Flush()
+}
+
+// printTask prints a single task, as an object rather than a list in JSON.
+func printTask(w io.Writer, format string, task domain.Task, now time.Time) error {
+	if format == formatJSON {
+		return printJSON(w, task)
+	}
+
+	return printTasks(w, format, []domain.Task{task}, now)
+}
+
+// taskDetails is a task with its dependencies and reminders, as shown by the
+// show command.
+type taskDetails struct {
+	domain.Task
+	BlockedBy []int64  `json:"blockedBy"`
+	Blocks    []int64  `json:"blocks"`
+	Reminders []string `json:"reminders"`
+}
+
+// printTaskDetails prints a single task with its dependencies and reminders.
+func printTaskDetails(w io.Writer, format string, details taskDetails, now time.Time) error {
+	switch format {
+	case formatJSON:
+		return printJSON(w, details)
+	case formatPlain:
+		return printTask(w, format, details.Task, now)
+	}
+
+	t := details.Task
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", t.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", t.Name)
+	fmt.Fprintf(tw, "Status:\t%s\n", taskStatus(t, now))
+	fmt.Fprintf(tw, "Deadline:\t%s (%s)\n", t.Deadline.Local().Format("Mon 2006-01-02 15:04"), deadline.Relative(t.Deadline, now))
+	if t.CompletedAt != nil {
+		fmt.Fprintf(tw, "Completed:\t%s\n", t.CompletedAt.Local().Format("Mon 2006-01-02 15:04"))
+	}
+	if len(details.BlockedBy) > 0 {
+		fmt.Fprintf(tw, "Blocked by:\t%s\n", joinIDs(details.BlockedBy))
+	}
+	if len(details.Blocks) > 0 {
+		fmt.Fprintf(tw, "Blocks:\t%s\n", joinIDs(details.Blocks))
+	}
+	if len(details.Reminders) > 0 {
+		fmt.Fprintf(tw, "Reminders:\t%s before\n", strings.Join(details.Reminders, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(t.Description) > 0 {
+		fmt.Fprintf(w, "\n%s\n", t.Description)
+	}
+
+	return nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+
+	return strings.Join(parts, ", ")
+}
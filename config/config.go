@@ -1,54 +1,521 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/ratelimit"
 )
 
+// Config holds the settings of the API server and the CLI commands. Values
+// are merged from defaults, a YAML or TOML file, environment variables and
+// command-line flags, in increasing order of precedence. See Load.
 type Config struct {
-	Port             string
-	CookieDomain     string
-	JwtSecretKey     string
-	PostgresUser     string
-	PostgresPassword string
-	PostgresDB       string
-	PostgresHost     string
+	HTTP      HTTPConfig      `config:"http"`
+	CORS      CORSConfig      `config:"cors"`
+	API       APIConfig       `config:"api"`
+	Log       LogConfig       `config:"log"`
+	Tracing   TracingConfig   `config:"tracing"`
+	Auth      AuthConfig      `config:"auth"`
+	RateLimit RateLimitConfig `config:"rate_limit"`
+	Quota     QuotaConfig     `config:"quota"`
+	Storage   StorageConfig   `config:"storage"`
+	Postgres  PostgresConfig  `config:"postgres"`
+	SMTP      SMTPConfig      `config:"smtp"`
 }
 
-func loadConfig() *Config {
-	godotenv.Load()
+type HTTPConfig struct {
+	Port              int           `config:"port" env:"PORT" help:"port the HTTP server listens on"`
+	ReadTimeout       time.Duration `config:"read_timeout" env:"HTTP_READ_TIMEOUT" help:"maximum duration for reading an entire request"`
+	ReadHeaderTimeout time.Duration `config:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" help:"maximum duration for reading request headers"`
+	WriteTimeout      time.Duration `config:"write_timeout" env:"HTTP_WRITE_TIMEOUT" help:"maximum duration before timing out writes of a response"`
+	IdleTimeout       time.Duration `config:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" help:"maximum time to wait for the next request on keep-alive connections"`
+	ShutdownTimeout   time.Duration `config:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" help:"time given to in-flight requests and workers to finish on shutdown"`
+	ShutdownDelay     time.Duration `config:"shutdown_delay" env:"SHUTDOWN_DELAY" help:"time readiness fails before the server stops accepting connections"`
+	HealthTimeout     time.Duration `config:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" help:"time allowed for a single readiness check"`
+	TrustedProxies    string        `config:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" help:"comma-separated IPs or CIDRs of proxies whose X-Forwarded-For header is trusted, none if empty"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   string        `config:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" help:"comma-separated origins allowed to call the API and open WebSockets from browsers, e.g. \"https://app.example.com\", CORS is disabled if empty"`
+	AllowCredentials bool          `config:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" help:"let allowed origins send cookies with their requests"`
+	MaxAge           time.Duration `config:"max_age" env:"CORS_MAX_AGE" help:"how long browsers may cache preflight responses"`
+}
 
-	return &Config{
-		Port:             getEnv("PORT", "8080"),
-		CookieDomain:     getEnv("COOKIE_DOMAIN", "localhost"),
-		JwtSecretKey:     getEnv("JWT_SECRET_KEY", "secret"),
-		PostgresUser:     getEnv("POSTGRES_USER", "user"),
-		PostgresPassword: getEnv("POSTGRES_PASSWORD", "password"),
-		PostgresDB:       getEnv("POSTGRES_DB", "hypertodo"),
-		PostgresHost:     getEnv("POSTGRES_HOST", "localhost"),
+// Origins returns the allowed origins.
+func (c CORSConfig) Origins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.AllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); len(origin) != 0 {
+			origins = append(origins, origin)
+		}
 	}
+
+	return origins
 }
 
-var Envs = loadConfig()
+// DateLayout is the layout of dates in the configuration.
+const DateLayout = "2006-01-02"
 
-func getEnv(key, fallback string) string {
-	if val, ok := os.LookupEnv(key); ok {
-		return val
+type APIConfig struct {
+	LegacyRoutes     bool   `config:"legacy_routes" env:"API_LEGACY_ROUTES" help:"also serve version 1 of the API at the root, as deprecated aliases of /api/v1"`
+	LegacySunset     string `config:"legacy_sunset" env:"API_LEGACY_SUNSET" help:"date, as YYYY-MM-DD, the legacy routes will be removed, announced in their Sunset header, none if empty"`
+	ValidateRequests bool   `config:"validate_requests" env:"API_VALIDATE_REQUESTS" help:"refuse requests not matching the OpenAPI document before they reach handlers"`
+}
+
+// Sunset parses the date of removal of the legacy routes, zero if unset.
+func (c APIConfig) Sunset() (time.Time, error) {
+	if len(c.LegacySunset) == 0 {
+		return time.Time{}, nil
 	}
 
-	return fallback
+	return time.Parse(DateLayout, c.LegacySunset)
+}
+
+type LogConfig struct {
+	SlowQueryThreshold time.Duration `config:"slow_query_threshold" env:"LOG_SLOW_QUERY_THRESHOLD" help:"duration above which database queries are logged as slow, 0 to disable"`
+}
+
+// Trace exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+type TracingConfig struct {
+	Exporter     string  `config:"exporter" env:"TRACING_EXPORTER" help:"trace exporter: none, stdout or otlp"`
+	OTLPEndpoint string  `config:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT" help:"URL the otlp exporter sends traces to over HTTP"`
+	ServiceName  string  `config:"service_name" env:"TRACING_SERVICE_NAME" help:"service name reported with the traces"`
+	SampleRatio  float64 `config:"sample_ratio" env:"TRACING_SAMPLE_RATIO" help:"fraction of new traces to sample, between 0 and 1"`
+}
+
+// Cookie SameSite modes.
+const (
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+	SameSiteNone   = "none" // Requires auth.cookie_secure
+)
+
+type AuthConfig struct {
+	JwtSecretKey   Secret        `config:"jwt_secret_key" env:"JWT_SECRET_KEY" help:"key signing authentication tokens, at least 32 characters"`
+	TokenTTL       time.Duration `config:"token_ttl" env:"AUTH_TOKEN_TTL" help:"how long authentication tokens and their cookies are valid"`
+	CookieDomain   string        `config:"cookie_domain" env:"COOKIE_DOMAIN" help:"domain of the authentication cookie"`
+	CookieSecure   bool          `config:"cookie_secure" env:"COOKIE_SECURE" help:"only send cookies over HTTPS, disable for plain HTTP development"`
+	CookieSameSite string        `config:"cookie_samesite" env:"COOKIE_SAMESITE" help:"SameSite attribute of cookies: lax, strict or none"`
+	CSRFProtection bool          `config:"csrf_protection" env:"CSRF_PROTECTION" help:"require the X-CSRF-Token header on state-changing requests authenticated with the cookie"`
 }
 
-func GetDsn() string {
-	dsn := fmt.Sprintf(
-		"postgresql://%s:%s@%s:5432/%s",
-		Envs.PostgresUser,
-		Envs.PostgresPassword,
-		Envs.PostgresHost,
-		Envs.PostgresDB,
+// Rate limit stores.
+const (
+	RateLimitStoreMemory   = "memory"   // Limits hold per replica
+	RateLimitStoreDatabase = "database" // Limits are shared through the storage database
+)
+
+type RateLimitConfig struct {
+	Enabled       bool   `config:"enabled" env:"RATE_LIMIT_ENABLED" help:"limit how often clients may call the API"`
+	Store         string `config:"store" env:"RATE_LIMIT_STORE" help:"where limits are kept: memory, or database to share them across replicas"`
+	Anonymous     string `config:"anonymous" env:"RATE_LIMIT_ANONYMOUS" help:"limit of requests without a valid token per client IP, as <requests>/<period> or 0 for none"`
+	Authenticated string `config:"authenticated" env:"RATE_LIMIT_AUTHENTICATED" help:"limit of authenticated requests per user, as <requests>/<period> or 0 for none"`
+	Routes        string `config:"routes" env:"RATE_LIMIT_ROUTES" help:"comma-separated limits of routes, without their /api/v1 prefix, overriding the defaults, e.g. \"POST /login=10/1m,GET /tasks=0\""`
+}
+
+// Policies parses the limits.
+func (c RateLimitConfig) Policies() (ratelimit.Policies, error) {
+	var (
+		policies ratelimit.Policies
+		errs     []error
+		err      error
 	)
 
-	return dsn
+	if policies.Anonymous, err = ratelimit.ParseRate(c.Anonymous); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limit.anonymous: %w", err))
+	}
+	if policies.Authenticated, err = ratelimit.ParseRate(c.Authenticated); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limit.authenticated: %w", err))
+	}
+	if policies.Routes, err = ratelimit.ParseRoutes(c.Routes); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limit.routes: %w", err))
+	}
+
+	return policies, errors.Join(errs...)
+}
+
+type QuotaConfig struct {
+	Plans       string `config:"plans" env:"QUOTA_PLANS" help:"semicolon-separated plans with their limits of tasks and daily API calls, 0 for none, e.g. \"free=tasks:100,api_calls:1000;pro=tasks:0,api_calls:0\""`
+	DefaultPlan string `config:"default_plan" env:"QUOTA_DEFAULT_PLAN" help:"plan of users without one"`
+}
+
+// PlanLimits parses the plans. Limits missing from a plan are 0, which is no
+// limit.
+func (c QuotaConfig) PlanLimits() (map[string]domain.Limits, error) {
+	plans := map[string]domain.Limits{}
+
+	for _, entry := range strings.Split(c.Plans, ";") {
+		if len(strings.TrimSpace(entry)) == 0 {
+			continue
+		}
+
+		name, rawLimits, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || len(name) == 0 {
+			return nil, fmt.Errorf("plan must be <name>=<resource>:<limit>,..., got %q", strings.TrimSpace(entry))
+		}
+
+		var limits domain.Limits
+		for _, rawLimit := range strings.Split(rawLimits, ",") {
+			resource, value, _ := strings.Cut(rawLimit, ":")
+			limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil || limit < 0 {
+				return nil, fmt.Errorf("%s: limit must be a number, 0 for none, got %q", name, strings.TrimSpace(rawLimit))
+			}
+
+			switch strings.TrimSpace(resource) {
+			case domain.QuotaTasks:
+				limits.Tasks = limit
+			case domain.QuotaAPICalls:
+				limits.APICallsPerDay = limit
+			default:
+				return nil, fmt.Errorf("%s: unknown resource %q, must be %s or %s", name, strings.TrimSpace(resource), domain.QuotaTasks, domain.QuotaAPICalls)
+			}
+		}
+		plans[name] = limits
+	}
+
+	return plans, nil
+}
+
+// Storage drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory" // Users and tasks only, other features are disabled
+)
+
+type StorageConfig struct {
+	Driver     string `config:"driver" env:"STORAGE_DRIVER" help:"storage backend: postgres, sqlite or memory"`
+	SQLitePath string `config:"sqlite_path" env:"SQLITE_PATH" help:"path of the SQLite database, :memory: for one discarded on exit"`
+}
+
+type PostgresConfig struct {
+	Host            string        `config:"host" env:"POSTGRES_HOST" help:"Postgres host"`
+	Port            int           `config:"port" env:"POSTGRES_PORT" help:"Postgres port"`
+	User            string        `config:"user" env:"POSTGRES_USER" help:"Postgres user"`
+	Password        Secret        `config:"password" env:"POSTGRES_PASSWORD" help:"Postgres password"`
+	DB              string        `config:"db" env:"POSTGRES_DB" help:"Postgres database name"`
+	SSLMode         string        `config:"sslmode" env:"POSTGRES_SSLMODE" help:"Postgres sslmode: disable, allow, prefer, require, verify-ca or verify-full"`
+	ConnectTimeout  time.Duration `config:"connect_timeout" env:"POSTGRES_CONNECT_TIMEOUT" help:"timeout of establishing a connection"`
+	MaxOpenConns    int           `config:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS" help:"maximum number of open connections, 0 for unlimited"`
+	MaxIdleConns    int           `config:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS" help:"maximum number of idle connections"`
+	ConnMaxLifetime time.Duration `config:"conn_max_lifetime" env:"POSTGRES_CONN_MAX_LIFETIME" help:"maximum time a connection is reused, 0 for no limit"`
+	ConnMaxIdleTime time.Duration `config:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME" help:"maximum time a connection stays idle, 0 for no limit"`
+	MigrateOnStart  bool          `config:"migrate_on_start" env:"MIGRATE_ON_START" help:"apply pending migrations at startup instead of only checking the schema version"`
+}
+
+type SMTPConfig struct {
+	Host     string `config:"host" env:"SMTP_HOST" help:"SMTP server host, email is disabled if empty"`
+	Port     int    `config:"port" env:"SMTP_PORT" help:"SMTP server port"`
+	Username string `config:"username" env:"SMTP_USERNAME" help:"SMTP username"`
+	Password Secret `config:"password" env:"SMTP_PASSWORD" help:"SMTP password"`
+	From     string `config:"from" env:"SMTP_FROM" help:"sender address of outgoing email"`
+}
+
+// Default returns the configuration used for values not set by any source.
+// There is deliberately no default JWT secret.
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+			HealthTimeout:     2 * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       true,
+			Store:         RateLimitStoreMemory,
+			Anonymous:     "60/1m",
+			Authenticated: "600/1m",
+			Routes:        "POST /login=10/1m,POST /register=5/1h",
+		},
+		Quota: QuotaConfig{
+			Plans:       "free=tasks:1000,api_calls:20000;unlimited=tasks:0,api_calls:0",
+			DefaultPlan: "free",
+		},
+		Log: LogConfig{
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Tracing: TracingConfig{
+			Exporter:     ExporterNone,
+			OTLPEndpoint: "http://localhost:4318/v1/traces",
+			ServiceName:  "hyper-todo",
+			SampleRatio:  1,
+		},
+		API: APIConfig{
+			LegacyRoutes:     true,
+			LegacySunset:     "2027-04-30",
+			ValidateRequests: true,
+		},
+		CORS: CORSConfig{
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		},
+		Auth: AuthConfig{
+			TokenTTL:       time.Hour,
+			CookieDomain:   "localhost",
+			CookieSecure:   true,
+			CookieSameSite: SameSiteLax,
+			CSRFProtection: true,
+		},
+		Storage: StorageConfig{
+			Driver:     DriverPostgres,
+			SQLitePath: "hyper-todo.db",
+		},
+		Postgres: PostgresConfig{
+			Host:           "localhost",
+			Port:           5432,
+			User:           "user",
+			DB:             "hypertodo",
+			SSLMode:        "disable",
+			ConnectTimeout: 5 * time.Second,
+			MaxOpenConns:   20,
+			MaxIdleConns:   5,
+			MigrateOnStart: true,
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+			From: "hyper-todo@localhost",
+		},
+	}
+}
+
+// MinSecretLength is the minimal length of the JWT secret key.
+const MinSecretLength = 32
+
+var drivers = []string{DriverPostgres, DriverSQLite, DriverMemory}
+
+var rateLimitStores = []string{RateLimitStoreMemory, RateLimitStoreDatabase}
+
+var sameSiteModes = []string{SameSiteLax, SameSiteStrict, SameSiteNone}
+
+var exporters = []string{ExporterNone, ExporterStdout, ExporterOTLP}
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate returns every invalid setting, joined into one error.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	validatePort := func(key string, port int) {
+		if port < 1 || port > 65535 {
+			invalid(key, "must be between 1 and 65535, got %d", port)
+		}
+	}
+
+	validatePositive := func(key string, d time.Duration) {
+		if d <= 0 {
+			invalid(key, "must be positive, got %s", d)
+		}
+	}
+
+	validateNotNegative := func(key string, d time.Duration) {
+		if d < 0 {
+			invalid(key, "must not be negative, got %s", d)
+		}
+	}
+
+	validatePort("http.port", c.HTTP.Port)
+	validatePositive("http.read_timeout", c.HTTP.ReadTimeout)
+	validatePositive("http.read_header_timeout", c.HTTP.ReadHeaderTimeout)
+	validatePositive("http.write_timeout", c.HTTP.WriteTimeout)
+	validatePositive("http.idle_timeout", c.HTTP.IdleTimeout)
+	validatePositive("http.shutdown_timeout", c.HTTP.ShutdownTimeout)
+	validateNotNegative("http.shutdown_delay", c.HTTP.ShutdownDelay)
+	validatePositive("http.health_check_timeout", c.HTTP.HealthTimeout)
+	validateNotNegative("log.slow_query_threshold", c.Log.SlowQueryThreshold)
+
+	if !contains(exporters, c.Tracing.Exporter) {
+		invalid("tracing.exporter", "must be one of %v, got %q", exporters, c.Tracing.Exporter)
+	}
+	if c.Tracing.Exporter == ExporterOTLP {
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("tracing.otlp_endpoint", "must be an http or https URL, got %q", c.Tracing.OTLPEndpoint)
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	if len(c.Auth.JwtSecretKey) == 0 {
+		invalid("auth.jwt_secret_key", "is required")
+	} else if len(c.Auth.JwtSecretKey) < MinSecretLength {
+		invalid("auth.jwt_secret_key", "must be at least %d characters long", MinSecretLength)
+	}
+
+	validatePositive("auth.token_ttl", c.Auth.TokenTTL)
+	if !contains(sameSiteModes, c.Auth.CookieSameSite) {
+		invalid("auth.cookie_samesite", "must be one of %v, got %q", sameSiteModes, c.Auth.CookieSameSite)
+	}
+	if c.Auth.CookieSameSite == SameSiteNone && !c.Auth.CookieSecure {
+		invalid("auth.cookie_samesite", "can only be none when auth.cookie_secure is enabled")
+	}
+
+	for _, origin := range c.CORS.Origins() {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				invalid("cors.allowed_origins", "must list origins rather than * when cors.allow_credentials is enabled")
+			}
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(u.Path) != 0 || origin != u.Scheme+"://"+u.Host {
+			invalid("cors.allowed_origins", "must be * or origins such as https://app.example.com, got %q", origin)
+		}
+	}
+	validateNotNegative("cors.max_age", c.CORS.MaxAge)
+
+	if _, err := c.API.Sunset(); err != nil {
+		invalid("api.legacy_sunset", "must be a date as YYYY-MM-DD, got %q", c.API.LegacySunset)
+	}
+
+	for _, proxy := range strings.Split(c.HTTP.TrustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if len(proxy) == 0 {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			invalid("http.trusted_proxies", "must be IPs or CIDRs, got %q", proxy)
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		if !contains(rateLimitStores, c.RateLimit.Store) {
+			invalid("rate_limit.store", "must be one of %v, got %q", rateLimitStores, c.RateLimit.Store)
+		}
+		if c.RateLimit.Store == RateLimitStoreDatabase && c.Storage.Driver == DriverMemory {
+			invalid("rate_limit.store", "must be memory when storage.driver is memory")
+		}
+		if _, err := c.RateLimit.Policies(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if plans, err := c.Quota.PlanLimits(); err != nil {
+		invalid("quota.plans", "%s", err)
+	} else if _, ok := plans[c.Quota.DefaultPlan]; !ok {
+		invalid("quota.default_plan", "must be one of the plans, got %q", c.Quota.DefaultPlan)
+	}
+
+	if !contains(drivers, c.Storage.Driver) {
+		invalid("storage.driver", "must be one of %v, got %q", drivers, c.Storage.Driver)
+	}
+	if c.Storage.Driver == DriverSQLite && len(c.Storage.SQLitePath) == 0 {
+		invalid("storage.sqlite_path", "is required when storage.driver is sqlite")
+	}
+
+	// Postgres settings are ignored by the other drivers.
+	if c.Storage.Driver == DriverPostgres {
+		if len(c.Postgres.Host) == 0 {
+			invalid("postgres.host", "is required")
+		}
+		validatePort("postgres.port", c.Postgres.Port)
+		if len(c.Postgres.User) == 0 {
+			invalid("postgres.user", "is required")
+		}
+		if len(c.Postgres.DB) == 0 {
+			invalid("postgres.db", "is required")
+		}
+		if !contains(sslModes, c.Postgres.SSLMode) {
+			invalid("postgres.sslmode", "must be one of %v, got %q", sslModes, c.Postgres.SSLMode)
+		}
+		validatePositive("postgres.connect_timeout", c.Postgres.ConnectTimeout)
+		if c.Postgres.MaxOpenConns < 0 {
+			invalid("postgres.max_open_conns", "must not be negative, got %d", c.Postgres.MaxOpenConns)
+		}
+		if c.Postgres.MaxIdleConns < 0 {
+			invalid("postgres.max_idle_conns", "must not be negative, got %d", c.Postgres.MaxIdleConns)
+		}
+		if c.Postgres.MaxOpenConns > 0 && c.Postgres.MaxIdleConns > c.Postgres.MaxOpenConns {
+			invalid("postgres.max_idle_conns", "must not exceed postgres.max_open_conns (%d), got %d", c.Postgres.MaxOpenConns, c.Postgres.MaxIdleConns)
+		}
+		validateNotNegative("postgres.conn_max_lifetime", c.Postgres.ConnMaxLifetime)
+		validateNotNegative("postgres.conn_max_idle_time", c.Postgres.ConnMaxIdleTime)
+	}
+
+	if len(c.SMTP.Host) != 0 {
+		validatePort("smtp.port", c.SMTP.Port)
+		if len(c.SMTP.From) == 0 {
+			invalid("smtp.from", "is required when smtp.host is set")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DSN returns the connection URL of the Postgres database.
+func (c PostgresConfig) DSN() string {
+	query := url.Values{}
+	query.Set("sslmode", c.SSLMode)
+	query.Set("connect_timeout", strconv.Itoa(int(c.ConnectTimeout.Seconds())))
+
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(c.User, c.Password.Value()),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.DB,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// Secret is a string which is redacted when printed, logged or serialized.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+// Value returns the secret itself.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable pointing to a config file, used
+// unless the --config flag is set.
+const FileEnv = "CONFIG_FILE"
+
+var (
+	ErrUnknownKey    = errors.New("unknown setting")
+	ErrUnknownFormat = errors.New("config file must have a .yaml, .yml or .toml extension")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a single setting of the Config struct.
+type field struct {
+	key   string // Dotted path used in config files, e.g. postgres.port
+	env   string
+	help  string
+	value reflect.Value
+}
+
+// flagName returns the command-line flag of the setting, e.g. --postgres-port.
+func (f field) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(f.key)
+}
+
+func (f field) set(raw string) error {
+	v := f.value
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q, expected a value like 30s or 5m", f.key, raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", f.key, raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", f.key, raw)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q, expected true or false", f.key, raw)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", f.key, v.Type())
+	}
+
+	return nil
+}
+
+// fields lists the settings of the struct pointed to by ptr.
+func fields(ptr any) []field {
+	var result []field
+
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			key := prefix + sf.Tag.Get("config")
+
+			if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+				walk(v.Field(i), key+".")
+				continue
+			}
+
+			result = append(result, field{
+				key:   key,
+				env:   sf.Tag.Get("env"),
+				help:  sf.Tag.Get("help"),
+				value: v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(ptr).Elem(), "")
+
+	return result
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+//
+//   - the defaults returned by Default,
+//   - a YAML or TOML file set with --config or CONFIG_FILE,
+//   - environment variables, including the ones in a .env file,
+//   - command-line flags, e.g. --postgres-port=5433.
+//
+// The configuration is validated before it is returned. Load returns the
+// arguments left after the flags, such as subcommands.
+func Load(args []string) (Config, []string, error) {
+	cfg := Default()
+	settings := fields(&cfg)
+
+	fs := flag.NewFlagSet("hyper-todo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFile := fs.String("config", os.Getenv(FileEnv), "path to a YAML or TOML config file")
+
+	type flagValue struct {
+		field field
+		raw   string
+	}
+	var flagValues []flagValue
+	for _, f := range settings {
+		f := f
+		fs.Func(f.flagName(), f.help, func(raw string) error {
+			flagValues = append(flagValues, flagValue{field: f, raw: raw})
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if *configFile != "" {
+		values, err := readFile(*configFile)
+		if err != nil {
+			return Config{}, nil, err
+		}
+
+		byKey := make(map[string]field, len(settings))
+		for _, f := range settings {
+			byKey[f.key] = f
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			f, ok := byKey[key]
+			if !ok {
+				return Config{}, nil, fmt.Errorf("%s: %w %q", *configFile, ErrUnknownKey, key)
+			}
+
+			if err := f.set(values[key]); err != nil {
+				return Config{}, nil, fmt.Errorf("%s: %w", *configFile, err)
+			}
+		}
+	}
+
+	// Variables already set in the environment take precedence over .env.
+	godotenv.Load()
+
+	for _, f := range settings {
+		if raw, ok := os.LookupEnv(f.env); ok {
+			if err := f.set(raw); err != nil {
+				return Config{}, nil, fmt.Errorf("%s: %w", f.env, err)
+			}
+		}
+	}
+
+	for _, v := range flagValues {
+		if err := v.field.set(v.raw); err != nil {
+			return Config{}, nil, fmt.Errorf("--%s: %w", v.field.flagName(), err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// Usage describes the command-line flags along with their environment variables.
+func Usage() string {
+	var b strings.Builder
+
+	b.WriteString("  --config string\n    \tpath to a YAML or TOML config file (env " + FileEnv + ")\n")
+	for _, f := range fields(&Config{}) {
+		fmt.Fprintf(&b, "  --%s %s\n    \t%s (env %s)\n", f.flagName(), typeName(f.value), f.help, f.env)
+	}
+
+	return b.String()
+}
+
+// Values returns every setting by its dotted key, formatted for logging with
+// secrets redacted.
+func (c Config) Values() map[string]string {
+	values := make(map[string]string)
+	for _, f := range fields(&c) {
+		values[f.key] = fmt.Sprint(f.value.Interface())
+	}
+
+	return values
+}
+
+func typeName(v reflect.Value) string {
+	if v.Type() == durationType {
+		return "duration"
+	}
+
+	return v.Kind().String()
+}
+
+// readFile parses a config file into settings keyed by dotted paths.
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &tree)
+	case ".toml":
+		err = toml.Unmarshal(content, &tree)
+	default:
+		return nil, fmt.Errorf("%s: %w", path, ErrUnknownFormat)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten(tree, "", values)
+
+	return values, nil
+}
+
+func flatten(tree map[string]any, prefix string, values map[string]string) {
+	for key, value := range tree {
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(v, prefix+key+".", values)
+		case nil:
+		default:
+			values[prefix+key] = fmt.Sprint(v)
+		}
+	}
+}
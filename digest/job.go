@@ -0,0 +1,155 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/mail"
+)
+
+//go:generate mockery --name SubscribersRepository
+type SubscribersRepository interface {
+	GetDigestSubscribers(context.Context) ([]domain.Preferences, error)
+	ClaimDigest(ctx context.Context, userId int64, date string) (bool, error)
+	ReleaseDigest(ctx context.Context, userId int64, date, previous string) error
+}
+
+// JobConfig defines polling settings of the Job.
+type JobConfig struct {
+	PollInterval time.Duration // How often subscribers are checked for a due digest
+}
+
+// DefaultJobConfig returns the settings used by the API server.
+func DefaultJobConfig() JobConfig {
+	return JobConfig{PollInterval: time.Minute}
+}
+
+// Job sends the daily digest to every opted-in user once their chosen local
+// time has passed. Each digest is claimed in the database before it is sent,
+// so replicas running the job side by side don't send it twice, and released
+// if it failed to be sent, so it is retried on the next run. Empty digests
+// are not sent, but their day is claimed all the same.
+type Job struct {
+	service     *Service
+	subscribers SubscribersRepository
+	sender      mail.Sender
+	config      JobConfig
+	now         func() time.Time
+}
+
+func NewJob(service *Service, subscribers SubscribersRepository, sender mail.Sender, config JobConfig) *Job {
+	return &Job{
+		service:     service,
+		subscribers: subscribers,
+		sender:      sender,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Run sends due digests until the context is canceled.
+func (j *Job) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(j.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		_, err := j.RunOnce(ctx)
+		metrics.ObserveJob("digests", start, err)
+		if err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends every digest which is due and returns how many were sent.
+func (j *Job) RunOnce(ctx context.Context) (int, error) {
+	subscribers, err := j.subscribers.GetDigestSubscribers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+
+	now := j.now()
+	for _, preferences := range subscribers {
+		ok, err := j.send(ctx, preferences, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+func (j *Job) send(ctx context.Context, preferences domain.Preferences, now time.Time) (bool, error) {
+	digestTime, err := domain.ParseClock(preferences.DigestTime)
+	if err != nil {
+		return false, nil
+	}
+
+	local := now.In(preferences.Location())
+	date := local.Format(time.DateOnly)
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if sinceMidnight < digestTime || preferences.LastDigestOn >= date {
+		return false, nil
+	}
+
+	claimed, err := j.subscribers.ClaimDigest(ctx, preferences.UserId, date)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	sent, err := j.sendDigest(ctx, preferences.UserId, now)
+	if err != nil {
+		// The claim is released even if the job is being stopped.
+		if releaseErr := j.subscribers.ReleaseDigest(context.WithoutCancel(ctx), preferences.UserId, date, preferences.LastDigestOn); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+
+	return sent, nil
+}
+
+// sendDigest builds and emails the digest of a user, unless it is empty, and
+// reports whether it was sent.
+func (j *Job) sendDigest(ctx context.Context, userId int64, now time.Time) (bool, error) {
+	digest, err := j.service.Build(ctx, userId, now)
+	if err != nil {
+		return false, err
+	}
+
+	if digest.Empty() {
+		return false, nil
+	}
+
+	rendered, err := j.service.Render(digest)
+	if err != nil {
+		return false, err
+	}
+
+	err = j.sender.Send(ctx, mail.Email{
+		To:      digest.User.Email,
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
+	})
+	return err == nil, err
+}
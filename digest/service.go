@@ -0,0 +1,180 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"sort"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/task"
+	"github.com/krau5/hyper-todo/user"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// PreferencesProvider returns the preferences of a user.
+type PreferencesProvider interface {
+	GetByUser(context.Context, int64) (domain.Preferences, error)
+}
+
+// Digest summarizes the tasks of a user for one day.
+type Digest struct {
+	User               domain.User
+	Date               time.Time
+	Location           *time.Location
+	Overdue            []domain.Task
+	DueToday           []domain.Task
+	CompletedYesterday []domain.Task
+}
+
+// Empty reports whether the digest has nothing to show.
+func (d Digest) Empty() bool {
+	return len(d.Overdue) == 0 && len(d.DueToday) == 0 && len(d.CompletedYesterday) == 0
+}
+
+// Subject returns the email subject of the digest.
+func (d Digest) Subject() string {
+	return fmt.Sprintf(
+		"Your tasks for %s: %d overdue, %d due today",
+		d.Date.Format("Jan 2"),
+		len(d.Overdue),
+		len(d.DueToday),
+	)
+}
+
+// Rendered is a digest rendered as an email.
+type Rendered struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type Service struct {
+	tasksRepo   task.TasksRepository
+	usersRepo   user.UsersRepository
+	preferences PreferencesProvider
+	text        *textTemplate.Template
+	html        *htmlTemplate.Template
+}
+
+var ErrInvalidUserId = errors.New("userId is missing or empty")
+
+func NewService(tasksRepo task.TasksRepository, usersRepo user.UsersRepository, preferences PreferencesProvider) *Service {
+	funcs := map[string]any{
+		// Replaced with a function bound to the user's time zone on render.
+		"localTime": func(t time.Time) string { return t.Format(time.Kitchen) },
+	}
+
+	return &Service{
+		tasksRepo:   tasksRepo,
+		usersRepo:   usersRepo,
+		preferences: preferences,
+		text:        textTemplate.Must(textTemplate.New("digest.txt.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.txt.tmpl")),
+		html:        htmlTemplate.Must(htmlTemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.html.tmpl")),
+	}
+}
+
+// Build collects the overdue tasks, the tasks due for the rest of the day
+// and the tasks completed the day before, using the user's time zone.
+func (s *Service) Build(ctx context.Context, userId int64, now time.Time) (Digest, error) {
+	if userId == 0 {
+		return Digest{}, ErrInvalidUserId
+	}
+
+	u, err := s.usersRepo.GetById(ctx, userId)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	preferences, err := s.preferences.GetByUser(ctx, userId)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	tasks, err := s.tasksRepo.GetByUser(ctx, userId)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	loc := preferences.Location()
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	yesterday := today.AddDate(0, 0, -1)
+
+	digest := Digest{User: u, Date: today, Location: loc}
+	for _, t := range tasks {
+		switch {
+		case t.Completed:
+			if t.CompletedAt != nil && !t.CompletedAt.Before(yesterday) && t.CompletedAt.Before(today) {
+				digest.CompletedYesterday = append(digest.CompletedYesterday, t)
+			}
+		case t.Deadline.IsZero():
+		case t.Deadline.Before(now):
+			digest.Overdue = append(digest.Overdue, t)
+		case t.Deadline.Before(tomorrow):
+			digest.DueToday = append(digest.DueToday, t)
+		}
+	}
+
+	byDeadline := func(tasks []domain.Task) {
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].Deadline.Before(tasks[j].Deadline) })
+	}
+	byDeadline(digest.Overdue)
+	byDeadline(digest.DueToday)
+
+	return digest, nil
+}
+
+// Render renders the digest as plain text and HTML.
+func (s *Service) Render(digest Digest) (Rendered, error) {
+	funcs := map[string]any{
+		"localTime": func(t time.Time) string {
+			return t.In(digest.Location).Format("Mon 15:04")
+		},
+	}
+
+	data := struct {
+		Digest
+		Subject string
+	}{Digest: digest, Subject: digest.Subject()}
+
+	text, err := s.text.Clone()
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	var textBuf bytes.Buffer
+	if err := text.Funcs(funcs).Execute(&textBuf, data); err != nil {
+		return Rendered{}, err
+	}
+
+	html, err := s.html.Clone()
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := html.Funcs(funcs).Execute(&htmlBuf, data); err != nil {
+		return Rendered{}, err
+	}
+
+	return Rendered{Subject: data.Subject, Text: textBuf.String(), HTML: htmlBuf.String()}, nil
+}
+
+// Preview builds and renders the digest the user would receive right now.
+func (s *Service) Preview(ctx context.Context, userId int64) (Rendered, error) {
+	digest, err := s.Build(ctx, userId, time.Now())
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	return s.Render(digest)
+}
@@ -0,0 +1,211 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/digest/mocks"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/mail"
+	mailMocks "github.com/krau5/hyper-todo/mail/mocks"
+	taskMocks "github.com/krau5/hyper-todo/task/mocks"
+	userMocks "github.com/krau5/hyper-todo/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	now   = time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	owner = domain.User{ID: 1, Name: "user", Email: "user@example.com"}
+)
+
+func TestBuild(t *testing.T) {
+	ctx := context.TODO()
+	completedAt := now.Add(-20 * time.Hour)
+	completedToday := now.Add(-time.Hour)
+	tasks := []domain.Task{
+		{ID: 1, Name: "overdue", Deadline: now.Add(-time.Hour), UserId: owner.ID},
+		{ID: 2, Name: "later today", Deadline: now.Add(5 * time.Hour), UserId: owner.ID},
+		{ID: 3, Name: "tomorrow", Deadline: now.Add(20 * time.Hour), UserId: owner.ID},
+		{ID: 4, Name: "done yesterday", Completed: true, CompletedAt: &completedAt, UserId: owner.ID},
+		{ID: 5, Name: "done today", Completed: true, CompletedAt: &completedToday, UserId: owner.ID},
+		{ID: 6, Name: "earlier today", Deadline: now.Add(2 * time.Hour), UserId: owner.ID},
+	}
+
+	t.Run("groups tasks by the user's day", func(t *testing.T) {
+		service, tasksRepo, usersRepo := setupTest(t, domain.DefaultPreferences(owner.ID))
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+		tasksRepo.On("GetByUser", mock.Anything, owner.ID).Return(tasks, nil)
+
+		digest, err := service.Build(ctx, owner.ID, now)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{1}, taskIds(digest.Overdue))
+		assert.Equal(t, []int64{6, 2}, taskIds(digest.DueToday))
+		assert.Equal(t, []int64{4}, taskIds(digest.CompletedYesterday))
+	})
+
+	t.Run("uses the time zone of the user", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.Timezone = "America/Los_Angeles"
+		service, tasksRepo, usersRepo := setupTest(t, preferences)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+		tasksRepo.On("GetByUser", mock.Anything, owner.ID).Return(tasks, nil)
+
+		// It is 01:00 in Los Angeles, so the task due at 05:00 UTC tomorrow is
+		// still due today.
+		digest, err := service.Build(ctx, owner.ID, now)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{6, 2, 3}, taskIds(digest.DueToday))
+		assert.Equal(t, []int64{4}, taskIds(digest.CompletedYesterday))
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		service, _, _ := setupTest(t, domain.DefaultPreferences(owner.ID))
+
+		_, err := service.Build(ctx, 0, now)
+		assert.ErrorIs(t, err, ErrInvalidUserId)
+	})
+}
+
+func TestRender(t *testing.T) {
+	service, _, _ := setupTest(t, domain.DefaultPreferences(owner.ID))
+	digest := Digest{
+		User:     owner,
+		Date:     now,
+		Location: time.UTC,
+		Overdue:  []domain.Task{{ID: 1, Name: "<b>pay rent</b>", Deadline: now.Add(-time.Hour)}},
+	}
+
+	rendered, err := service.Render(digest)
+	assert.Nil(t, err)
+	assert.Equal(t, "Your tasks for Jan 2: 1 overdue, 0 due today", rendered.Subject)
+	assert.Contains(t, rendered.Text, "<b>pay rent</b> (was due Thu 08:00)")
+	assert.Contains(t, rendered.HTML, "&lt;b&gt;pay rent&lt;/b&gt;")
+	assert.NotContains(t, rendered.HTML, "Nothing is overdue")
+}
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.TODO()
+	dueToday := []domain.Task{{ID: 1, Name: "later today", Deadline: now.Add(5 * time.Hour), UserId: owner.ID}}
+
+	t.Run("sends due digests", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		job, subscribers, sender, tasksRepo, usersRepo := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+		subscribers.On("ClaimDigest", mock.Anything, owner.ID, "2025-01-02").Return(true, nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+		tasksRepo.On("GetByUser", mock.Anything, owner.ID).Return(dueToday, nil)
+		sender.On("Send", mock.Anything, mock.MatchedBy(func(email mail.Email) bool {
+			return email.To == owner.Email && email.Subject == "Your tasks for Jan 2: 0 overdue, 1 due today"
+		})).Return(nil)
+
+		sent, err := job.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("releases the claim of digests which failed to be sent", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		preferences.LastDigestOn = "2025-01-01"
+		job, subscribers, sender, tasksRepo, usersRepo := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+		subscribers.On("ClaimDigest", mock.Anything, owner.ID, "2025-01-02").Return(true, nil)
+		subscribers.On("ReleaseDigest", mock.Anything, owner.ID, "2025-01-02", "2025-01-01").Return(nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+		tasksRepo.On("GetByUser", mock.Anything, owner.ID).Return(dueToday, nil)
+		sender.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp is down"))
+
+		sent, err := job.RunOnce(ctx)
+		assert.ErrorContains(t, err, "smtp is down")
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("claims empty digests without sending them", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		job, subscribers, _, tasksRepo, usersRepo := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+		subscribers.On("ClaimDigest", mock.Anything, owner.ID, "2025-01-02").Return(true, nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+		tasksRepo.On("GetByUser", mock.Anything, owner.ID).Return([]domain.Task{}, nil)
+
+		sent, err := job.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("waits for the digest time", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		preferences.DigestTime = "10:00"
+		job, subscribers, _, _, _ := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+
+		sent, err := job.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("skips digests claimed by another instance", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		job, subscribers, _, _, _ := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+		subscribers.On("ClaimDigest", mock.Anything, owner.ID, "2025-01-02").Return(false, nil)
+
+		sent, err := job.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("skips digests already sent today", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.DigestEnabled = true
+		preferences.LastDigestOn = "2025-01-02"
+		job, subscribers, _, _, _ := setupJobTest(t, preferences)
+		subscribers.On("GetDigestSubscribers", mock.Anything).Return([]domain.Preferences{preferences}, nil)
+
+		sent, err := job.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+	})
+}
+
+func taskIds(tasks []domain.Task) []int64 {
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	return ids
+}
+
+type fakePreferences struct {
+	preferences domain.Preferences
+}
+
+func (p fakePreferences) GetByUser(context.Context, int64) (domain.Preferences, error) {
+	return p.preferences, nil
+}
+
+func setupTest(t *testing.T, preferences domain.Preferences) (*Service, *taskMocks.TasksRepository, *userMocks.UsersRepository) {
+	tasksRepo := taskMocks.NewTasksRepository(t)
+	usersRepo := userMocks.NewUsersRepository(t)
+	service := NewService(tasksRepo, usersRepo, fakePreferences{preferences})
+
+	return service, tasksRepo, usersRepo
+}
+
+func setupJobTest(t *testing.T, preferences domain.Preferences) (*Job, *mocks.SubscribersRepository, *mailMocks.Sender, *taskMocks.TasksRepository, *userMocks.UsersRepository) {
+	service, tasksRepo, usersRepo := setupTest(t, preferences)
+	subscribers := mocks.NewSubscribersRepository(t)
+	sender := mailMocks.NewSender(t)
+	job := NewJob(service, subscribers, sender, DefaultJobConfig())
+	job.now = func() time.Time { return now }
+
+	return job, subscribers, sender, tasksRepo, usersRepo
+}
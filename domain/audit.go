@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// AuditLog records an action of the admin API or of the admin command.
+type AuditLog struct {
+	ID           int64     `json:"id" gorm:"primaryKey"`
+	ActorId      int64     `json:"actorId,omitempty" gorm:"index"` // 0 for the admin command
+	Action       string    `json:"action" gorm:"not null" example:"users.disable"`
+	TargetUserId int64     `json:"targetUserId,omitempty" gorm:"index"`
+	Details      string    `json:"details,omitempty" example:"q=john"`
+	Succeeded    bool      `json:"succeeded" gorm:"not null"`
+	Error        string    `json:"error,omitempty" example:"forbidden"` // Problem code or error of failed actions
+	RequestId    string    `json:"requestId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"not null;index"`
+}
+
+// AuditLogFilter selects audit logs, newest first. Zero fields match any log.
+type AuditLogFilter struct {
+	ActorId      int64
+	TargetUserId int64
+	Action       string
+	Limit        int
+}
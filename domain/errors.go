@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+// Errors returned by every storage backend in place of driver specific errors.
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+)
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventTaskCreated  EventType = "task.created"
+	EventTaskUpdated  EventType = "task.updated"
+	EventTaskDeleted  EventType = "task.deleted"
+	EventTaskReminder EventType = "task.reminder"
+)
+
+type Event struct {
+	Type       EventType `json:"type" example:"task.created"`
+	UserId     int64     `json:"-"`
+	Task       Task      `json:"task"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func NewTaskEvent(eventType EventType, task Task) Event {
+	return Event{
+		Type:       eventType,
+		UserId:     task.UserId,
+		Task:       task,
+		OccurredAt: time.Now().UTC(),
+	}
+}
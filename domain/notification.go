@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type NotificationChannel string
+
+const (
+	ChannelInApp   NotificationChannel = "in_app"
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+)
+
+type Notification struct {
+	ID        int64     `json:"id" gorm:"unique;autoIncrement"`
+	TaskId    int64     `json:"taskId,omitempty" example:"1"`
+	Title     string    `json:"title" gorm:"not null" example:"\"Eat\" is due in 1h0m0s"`
+	Body      string    `json:"body" gorm:"not null" example:"Eat the pizza"`
+	Read      bool      `json:"read" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"createdAt"`
+	UserId    int64     `json:"-" gorm:"not null;index"`
+}
+
+type Reminder struct {
+	ID           int64         `json:"-" gorm:"unique;autoIncrement"`
+	TaskId       int64         `json:"-" gorm:"not null;uniqueIndex:idx_reminders_task_before"`
+	RemindBefore time.Duration `json:"-" gorm:"not null;uniqueIndex:idx_reminders_task_before"`
+	SentFor      *time.Time    `json:"-"` // Deadline the reminder was last sent for
+	SentAt       *time.Time    `json:"sentAt,omitempty"`
+	// NextAttemptAt hides the reminder until then, while it is being sent or
+	// once it was postponed.
+	NextAttemptAt *time.Time `json:"-"`
+}
+
+// DueReminder is a reminder whose time has come, along with its task.
+type DueReminder struct {
+	Reminder Reminder
+	Task     Task
+}
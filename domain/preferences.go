@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Preferences struct {
+	Timezone             string                `json:"timezone" gorm:"not null;default:UTC" example:"Europe/Berlin"`
+	QuietHoursStart      string                `json:"quietHoursStart,omitempty" example:"22:00"`
+	QuietHoursEnd        string                `json:"quietHoursEnd,omitempty" example:"07:00"`
+	NotificationChannels []NotificationChannel `json:"notificationChannels" gorm:"serializer:json;not null"`
+	DigestEnabled        bool                  `json:"digestEnabled" gorm:"not null;default:false"`
+	DigestTime           string                `json:"digestTime" gorm:"not null;default:08:00" example:"08:00"`
+	LastDigestOn         string                `json:"-"` // Local date of the last digest in the "2006-01-02" format
+	UserId               int64                 `json:"-" gorm:"not null;uniqueIndex"`
+}
+
+type UpdatePreferencesData struct {
+	Timezone             *string                `json:"timezone,omitempty"`
+	QuietHoursStart      *string                `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd        *string                `json:"quietHoursEnd,omitempty"`
+	NotificationChannels *[]NotificationChannel `json:"notificationChannels,omitempty"`
+	DigestEnabled        *bool                  `json:"digestEnabled,omitempty"`
+	DigestTime           *string                `json:"digestTime,omitempty"`
+}
+
+func DefaultPreferences(userId int64) Preferences {
+	return Preferences{
+		Timezone:             "UTC",
+		NotificationChannels: []NotificationChannel{ChannelInApp},
+		DigestTime:           "08:00",
+		UserId:               userId,
+	}
+}
+
+// Location returns the time zone of the user, falling back to UTC.
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// InQuietHours reports whether t falls into the quiet hours of the user.
+// Quiet hours may wrap around midnight, e.g. from 22:00 to 07:00.
+func (p Preferences) InQuietHours(t time.Time) bool {
+	start, err := ParseClock(p.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+
+	end, err := ParseClock(p.QuietHoursEnd)
+	if err != nil || start == end {
+		return false
+	}
+
+	local := t.In(p.Location())
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if start < end {
+		return now >= start && now < end
+	}
+
+	return now >= start || now < end
+}
+
+// EndOfQuietHours returns the end of the quiet hours t falls into, or t if it
+// doesn't fall into quiet hours.
+func (p Preferences) EndOfQuietHours(t time.Time) time.Time {
+	if !p.InQuietHours(t) {
+		return t
+	}
+
+	end, _ := ParseClock(p.QuietHoursEnd)
+	hour, minute := int(end/time.Hour), int(end%time.Hour/time.Minute)
+	local := t.In(p.Location())
+	year, month, day := local.Date()
+	endsAt := time.Date(year, month, day, hour, minute, 0, 0, local.Location())
+	if !endsAt.After(local) {
+		endsAt = time.Date(year, month, day+1, hour, minute, 0, 0, local.Location())
+	}
+
+	return endsAt
+}
+
+// ParseClock parses a time of day in the "15:04" format into the duration since midnight.
+func ParseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
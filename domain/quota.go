@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Resources limited by quotas.
+const (
+	QuotaTasks    = "tasks"
+	QuotaAPICalls = "api_calls"
+)
+
+// ErrQuotaExceeded matches every QuotaError.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Limits are the quotas of a plan, 0 for no limit.
+type Limits struct {
+	Tasks          int64
+	APICallsPerDay int64
+}
+
+// QuotaError is returned when an action would exceed a quota of the user.
+type QuotaError struct {
+	Resource string
+	Limit    int64
+	ResetsAt time.Time // Zero for quotas of resources which are not renewed
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s quota of %d exceeded", e.Resource, e.Limit)
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaUsage is the consumption of a resource against its limit.
+type QuotaUsage struct {
+	Used     int64      `json:"used" example:"42"`
+	Limit    *int64     `json:"limit" example:"100"` // Null for no limit
+	ResetsAt *time.Time `json:"resetsAt,omitempty"`
+}
+
+// Usage is the consumption of the quotas of a user.
+type Usage struct {
+	Plan     string     `json:"plan" example:"free"`
+	Tasks    QuotaUsage `json:"tasks"`
+	APICalls QuotaUsage `json:"apiCalls"`
+}
@@ -3,12 +3,14 @@ package domain
 import "time"
 
 type Task struct {
-	ID          int64     `json:"id" gorm:"unique;autoIncrement"`
-	Name        string    `json:"name" gorm:"not null"`
-	Description string    `json:"description" gorm:"not null"`
-	Deadline    time.Time `json:"deadline"`
-	Completed   bool      `json:"completed,omitempty" gorm:"default:false"`
-	UserId      int64     `json:"-" gorm:"not null"`
+	ID          int64      `json:"id" gorm:"unique;autoIncrement"`
+	Name        string     `json:"name" gorm:"not null"`
+	Description string     `json:"description" gorm:"not null"`
+	Deadline    time.Time  `json:"deadline"`
+	Completed   bool       `json:"completed,omitempty" gorm:"default:false"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Blocked     bool       `json:"blocked" gorm:"->;-:migration"` // Computed, true while any blocker is not completed
+	UserId      int64      `json:"-" gorm:"not null"`
 }
 
 type UpdateTaskData struct {
@@ -16,4 +18,18 @@ type UpdateTaskData struct {
 	Description *string    `json:"description,omitempty"`
 	Deadline    *time.Time `json:"deadline,omitempty"`
 	Completed   *bool      `json:"completed,omitempty"`
+	Force       bool       `json:"force,omitempty"` // Complete the task even if its blockers are not completed
+}
+
+// TaskDependency records that a task is blocked by another task of the same
+// user and can't be completed before it.
+type TaskDependency struct {
+	TaskId      int64 `json:"taskId" gorm:"primaryKey;autoIncrement:false"`
+	BlockedById int64 `json:"blockedById" gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+// TaskDependencies lists the tasks blocking a task and the tasks it blocks.
+type TaskDependencies struct {
+	BlockedBy []Task `json:"blockedBy"`
+	Blocks    []Task `json:"blocks"`
 }
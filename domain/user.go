@@ -1,8 +1,57 @@
 package domain
 
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Role grants access to the admin API on top of the tasks of the user.
+type Role string
+
+const (
+	RoleUser    Role = "user"    // Manages their own tasks only
+	RoleSupport Role = "support" // Also looks users up, reads their tasks and logs them out
+	RoleAdmin   Role = "admin"   // Also disables accounts and reads the audit log
+)
+
+// Roles lists the valid roles, from the least to the most privileged.
+var Roles = []Role{RoleUser, RoleSupport, RoleAdmin}
+
+// Valid reports whether the role is one of Roles.
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Outranks reports whether the role is more privileged than the other.
+func (r Role) Outranks(other Role) bool {
+	return slices.Index(Roles, r) > slices.Index(Roles, other)
+}
+
+var (
+	ErrAccountLocked  = errors.New("account is locked")
+	ErrSessionRevoked = errors.New("session was revoked, log in again")
+)
+
 type User struct {
-	ID       int64  `json:"-" gorm:"unique;autoIncrement"`
-	Name     string `json:"name" gorm:"not null" example:"user"`
-	Email    string `json:"email" gorm:"unique;not null" example:"user@example.com"`
-	Password string `json:"-" gorm:"not null"`
+	ID                int64      `json:"-" gorm:"unique;autoIncrement"`
+	Name              string     `json:"name" gorm:"not null" example:"user"`
+	Email             string     `json:"email" gorm:"unique;not null" example:"user@example.com"`
+	Password          string     `json:"-" gorm:"not null"`
+	Plan              string     `json:"-" gorm:"not null;default:''"` // Empty for the default plan
+	Role              Role       `json:"role" gorm:"not null;default:'user'" example:"user"`
+	LockedAt          *time.Time `json:"-"` // Set while the account is locked
+	SessionsRevokedAt *time.Time `json:"-"` // Tokens issued before are refused
+}
+
+// Locked reports whether the account is locked, refusing logins and token
+// refreshes.
+func (u User) Locked() bool {
+	return u.LockedAt != nil
 }
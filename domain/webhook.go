@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Webhook struct {
+	ID         int64       `json:"id" gorm:"unique;autoIncrement"`
+	URL        string      `json:"url" gorm:"not null" example:"https://example.com/hooks/tasks"`
+	Secret     string      `json:"secret,omitempty" gorm:"not null"`
+	EventTypes []EventType `json:"eventTypes" gorm:"serializer:json;not null"`
+	Active     bool        `json:"active" gorm:"not null;default:true"`
+	UserId     int64       `json:"-" gorm:"not null;index"`
+}
+
+type UpdateWebhookData struct {
+	URL        *string      `json:"url,omitempty"`
+	EventTypes *[]EventType `json:"eventTypes,omitempty"`
+	Active     *bool        `json:"active,omitempty"`
+}
+
+// Subscribes reports whether the webhook wants to receive events of the given type.
+// A webhook without event type filters receives every event.
+func (w Webhook) Subscribes(eventType EventType) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryRetrying  DeliveryStatus = "retrying"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id" gorm:"unique;autoIncrement"`
+	WebhookId      int64           `json:"webhookId" gorm:"not null;index"`
+	EventType      EventType       `json:"eventType" gorm:"not null"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status         DeliveryStatus  `json:"status" gorm:"not null;index"`
+	Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt" gorm:"not null;index"`
+	LastError      string          `json:"lastError,omitempty"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
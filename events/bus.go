@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+// Handler receives events published on the bus. Handlers are called
+// synchronously by the publisher, so they must not block.
+type Handler func(context.Context, domain.Event)
+
+// Bus is an in-process publish/subscribe hub for domain events.
+type Bus struct {
+	mu       sync.RWMutex
+	nextId   int
+	handlers map[int]Handler
+}
+
+// NewBus returns an empty event bus.
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[int]Handler)}
+}
+
+// Publish delivers the event to every subscribed handler.
+func (b *Bus) Publish(ctx context.Context, event domain.Event) {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+}
+
+// Subscribe registers a handler and returns a function that removes it.
+func (b *Bus) Subscribe(h Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextId
+	b.nextId++
+	b.handlers[id] = h
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.handlers, id)
+	}
+}
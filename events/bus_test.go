@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	ctx := context.TODO()
+	event := domain.NewTaskEvent(domain.EventTaskCreated, domain.Task{ID: 1, UserId: 1})
+
+	t.Run("delivers events to every subscriber", func(t *testing.T) {
+		bus := NewBus()
+
+		var first, second []domain.Event
+		bus.Subscribe(func(_ context.Context, e domain.Event) { first = append(first, e) })
+		bus.Subscribe(func(_ context.Context, e domain.Event) { second = append(second, e) })
+
+		bus.Publish(ctx, event)
+
+		assert.Equal(t, []domain.Event{event}, first)
+		assert.Equal(t, []domain.Event{event}, second)
+	})
+
+	t.Run("stops delivering after unsubscribe", func(t *testing.T) {
+		bus := NewBus()
+
+		var received []domain.Event
+		unsubscribe := bus.Subscribe(func(_ context.Context, e domain.Event) { received = append(received, e) })
+
+		bus.Publish(ctx, event)
+		unsubscribe()
+		bus.Publish(ctx, event)
+
+		assert.Len(t, received, 1)
+	})
+}
@@ -0,0 +1,79 @@
+// Package credentials saves the token of the command-line clients between
+// runs, in the config directory of the user.
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/krau5/hyper-todo/client"
+)
+
+// Credentials are saved at login so the next commands are authenticated.
+type Credentials struct {
+	Server string       `json:"server"`
+	Email  string       `json:"email"`
+	Token  client.Token `json:"token"`
+}
+
+// ErrNotLoggedIn is returned by commands needing credentials without any.
+var ErrNotLoggedIn = errors.New("not logged in, run hyper login first")
+
+// DefaultDir returns the directory the credentials are saved in by default.
+func DefaultDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "hyper-todo"), nil
+}
+
+// Path returns the path of the credentials file in the directory.
+func Path(dir string) string {
+	return filepath.Join(dir, "credentials.json")
+}
+
+// Load returns the credentials saved in the directory, or empty ones if there
+// are none.
+func Load(dir string) (Credentials, error) {
+	var creds Credentials
+
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return creds, nil
+	}
+	if err != nil {
+		return creds, err
+	}
+
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return creds, fmt.Errorf("invalid credentials file %s: %w", Path(dir), err)
+	}
+
+	return creds, nil
+}
+
+// Save saves the credentials in the directory, readable only by the user.
+func Save(dir string, creds Credentials) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written aside and renamed so a failed write doesn't lose the token.
+	path := Path(dir)
+	if err := os.WriteFile(path+".tmp", append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
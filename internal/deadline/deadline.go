@@ -0,0 +1,218 @@
+// Package deadline parses and formats the deadlines of tasks for the
+// command-line clients.
+package deadline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Help describes the formats accepted by Parse.
+const Help = `Deadlines are in the local time zone and can be written as:
+  2025-01-31T17:00:00+01:00   RFC 3339
+  2025-01-31 [17:00]          a date
+  today, tomorrow [17:00]     a day relative to today
+  [next] friday [5pm]         the next Friday
+  17:00, 5:30pm               the next time it is that hour
+  in 2h, in 3 days, in 1w     a duration from now
+A day without a time means the end of that day, 23:59.`
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Parse parses a deadline relative to now, in its location. See Help for the
+// accepted formats.
+func Parse(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.Join(strings.Fields(value), " "))
+	if len(value) == 0 {
+		return time.Time{}, fmt.Errorf("empty deadline")
+	}
+
+	if deadline, err := time.Parse(time.RFC3339, strings.ToUpper(value)); err == nil {
+		return deadline, nil
+	}
+	if rest, ok := strings.CutPrefix(value, "in "); ok {
+		d, err := parseDuration(rest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid deadline %q: %w", value, err)
+		}
+		return now.Add(d), nil
+	}
+
+	words := strings.Fields(value)
+	day, words, hasDay := parseDay(words, now)
+
+	hour, minute := 23, 59
+	if len(words) > 0 {
+		var err error
+		hour, minute, err = parseClock(strings.Join(words, ""))
+		if err != nil && hasDay {
+			return time.Time{}, fmt.Errorf("invalid deadline %q: %w", value, err)
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid deadline %q", value)
+		}
+	}
+
+	deadline := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+	if !hasDay && !deadline.After(now) {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+
+	return deadline, nil
+}
+
+// parseDay parses the day the words start with, returning today if they don't
+// start with one, and the remaining words.
+func parseDay(words []string, now time.Time) (time.Time, []string, bool) {
+	if len(words) == 0 {
+		return now, words, false
+	}
+
+	switch words[0] {
+	case "today", "tonight":
+		return now, words[1:], true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), words[1:], true
+	}
+
+	if day, err := time.ParseInLocation(time.DateOnly, words[0], now.Location()); err == nil {
+		return day, words[1:], true
+	}
+
+	rest := words
+	if rest[0] == "next" && len(rest) > 1 {
+		rest = rest[1:]
+	}
+	if weekday, ok := weekdays[rest[0]]; ok {
+		days := (int(weekday)-int(now.Weekday())+6)%7 + 1
+		return now.AddDate(0, 0, days), rest[1:], true
+	}
+
+	return now, words, false
+}
+
+// parseClock parses a time of the day such as "17:00", "5pm", "5:30am" or
+// "noon".
+func parseClock(value string) (hour, minute int, err error) {
+	if value == "noon" {
+		return 12, 0, nil
+	}
+
+	clock, meridiem := value, ""
+	if rest, ok := strings.CutSuffix(value, "am"); ok {
+		clock, meridiem = rest, "am"
+	} else if rest, ok := strings.CutSuffix(value, "pm"); ok {
+		clock, meridiem = rest, "pm"
+	}
+
+	rawHour, rawMinute, hasMinute := strings.Cut(clock, ":")
+	if hour, err = strconv.Atoi(rawHour); err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q", value)
+	}
+	if hasMinute {
+		if len(rawMinute) != 2 {
+			return 0, 0, fmt.Errorf("invalid time %q", value)
+		}
+		if minute, err = strconv.Atoi(rawMinute); err != nil || minute > 59 {
+			return 0, 0, fmt.Errorf("invalid time %q", value)
+		}
+	} else if len(meridiem) == 0 {
+		// A bare number is more likely a mistake than an hour.
+		return 0, 0, fmt.Errorf("invalid time %q", value)
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, fmt.Errorf("invalid time %q", value)
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid time %q", value)
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+
+	return hour, minute, nil
+}
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// parseDuration parses durations such as "2h", "3 days" or "1h30m".
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.ReplaceAll(value, " ", "")
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+
+	i := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if i <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	n, err := strconv.Atoi(value[:i])
+	unit, ok := durationUnits[value[i:]]
+	if err != nil || !ok || n == 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+// Format formats a deadline in the local time zone, omitting the date
+// for today.
+func Format(deadline, now time.Time) string {
+	deadline, now = deadline.Local(), now.Local()
+	if deadline.Year() == now.Year() && deadline.YearDay() == now.YearDay() {
+		return "today " + deadline.Format("15:04")
+	}
+	if deadline.Year() == now.Year() {
+		return deadline.Format("Mon Jan 2 15:04")
+	}
+
+	return deadline.Format("2006-01-02 15:04")
+}
+
+// Relative describes how far a time is from now, such as "in 3h" or "2d ago".
+func Relative(t, now time.Time) string {
+	d := t.Sub(now)
+	suffix := ""
+	if d < 0 {
+		d, suffix = -d, " ago"
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		amount = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 48*time.Hour:
+		amount = fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		amount = fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+
+	if len(suffix) == 0 {
+		return "in " + amount
+	}
+
+	return amount + suffix
+}
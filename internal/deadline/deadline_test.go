@@ -0,0 +1,58 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// A Wednesday afternoon.
+	now := time.Date(2025, time.January, 15, 14, 30, 0, 0, berlin)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2025-02-01T17:00:00Z", time.Date(2025, time.February, 1, 17, 0, 0, 0, time.UTC)},
+		{"2025-02-01", time.Date(2025, time.February, 1, 23, 59, 0, 0, berlin)},
+		{"2025-02-01 9:15", time.Date(2025, time.February, 1, 9, 15, 0, 0, berlin)},
+		{"today", time.Date(2025, time.January, 15, 23, 59, 0, 0, berlin)},
+		{"tomorrow 17:00", time.Date(2025, time.January, 16, 17, 0, 0, 0, berlin)},
+		{"Tomorrow  5pm", time.Date(2025, time.January, 16, 17, 0, 0, 0, berlin)},
+		{"tomorrow 12am", time.Date(2025, time.January, 16, 0, 0, 0, 0, berlin)},
+		{"tomorrow noon", time.Date(2025, time.January, 16, 12, 0, 0, 0, berlin)},
+		{"friday", time.Date(2025, time.January, 17, 23, 59, 0, 0, berlin)},
+		{"next fri 9:30am", time.Date(2025, time.January, 17, 9, 30, 0, 0, berlin)},
+		{"wednesday", time.Date(2025, time.January, 22, 23, 59, 0, 0, berlin)},
+		{"17:00", time.Date(2025, time.January, 15, 17, 0, 0, 0, berlin)},
+		{"9am", time.Date(2025, time.January, 16, 9, 0, 0, 0, berlin)},
+		{"in 2h", now.Add(2 * time.Hour)},
+		{"in 1h30m", now.Add(90 * time.Minute)},
+		{"in 3 days", now.Add(72 * time.Hour)},
+		{"in 1w", now.Add(7 * 24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			deadline, err := Parse(tt.value, now)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(deadline), "expected %s, got %s", tt.expected, deadline)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	now := time.Date(2025, time.January, 15, 14, 30, 0, 0, time.UTC)
+
+	for _, value := range []string{"", "soon", "tomorrow 25:00", "tomorrow 17", "13pm", "next", "in 2 fortnights", "in -2h", "friday at 5pm"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := Parse(value, now)
+			assert.Error(t, err)
+		})
+	}
+}
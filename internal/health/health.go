@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Check reports whether a dependency is usable by returning nil.
+type Check func(context.Context) error
+
+// Statuses of a report and of its checks.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Result is the outcome of a single check.
+type Result struct {
+	Status     string `json:"status" example:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs" example:"3"`
+}
+
+// Report is the outcome of every registered check. Its status is StatusOK
+// only if every check passed.
+type Report struct {
+	Status string            `json:"status" example:"ok"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK reports whether every check passed.
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Registry runs the registered checks concurrently, each bounded by the
+// registry timeout.
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a check. Checks registered under the same name replace each other.
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.checks {
+		if c.name == name {
+			r.checks[i].check = check
+			return
+		}
+	}
+
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Check runs every registered check and waits for all of them.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]namedCheck(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, c.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+
+	// Checks ignoring their context must not hold the response back.
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Draining returns a check failing once done is closed, used to take an
+// instance out of rotation during graceful shutdown.
+func Draining(done <-chan struct{}) Check {
+	return func(context.Context) error {
+		select {
+		case <-done:
+			return ErrShuttingDown
+		default:
+			return nil
+		}
+	}
+}
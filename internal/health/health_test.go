@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("passes when every check passes", func(t *testing.T) {
+		r := NewRegistry(time.Second)
+		r.Register("database", func(context.Context) error { return nil })
+		r.Register("migrations", func(context.Context) error { return nil })
+
+		report := r.Check(context.TODO())
+		assert.True(t, report.OK())
+		assert.Equal(t, StatusOK, report.Checks["database"].Status)
+		assert.Equal(t, StatusOK, report.Checks["migrations"].Status)
+	})
+
+	t.Run("fails when any check fails", func(t *testing.T) {
+		r := NewRegistry(time.Second)
+		r.Register("database", func(context.Context) error { return nil })
+		r.Register("migrations", func(context.Context) error { return errors.New("outdated") })
+
+		report := r.Check(context.TODO())
+		assert.False(t, report.OK())
+		assert.Equal(t, StatusOK, report.Checks["database"].Status)
+		assert.Equal(t, StatusFail, report.Checks["migrations"].Status)
+		assert.Equal(t, "outdated", report.Checks["migrations"].Error)
+	})
+
+	t.Run("times out slow checks", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+
+		r := NewRegistry(10 * time.Millisecond)
+		r.Register("database", func(context.Context) error {
+			<-block
+			return nil
+		})
+
+		report := r.Check(context.TODO())
+		assert.False(t, report.OK())
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+	})
+
+	t.Run("fails while draining", func(t *testing.T) {
+		done := make(chan struct{})
+		r := NewRegistry(time.Second)
+		r.Register("shutdown", Draining(done))
+
+		assert.True(t, r.Check(context.TODO()).OK())
+
+		close(done)
+		report := r.Check(context.TODO())
+		assert.False(t, report.OK())
+		assert.Equal(t, ErrShuttingDown.Error(), report.Checks["shutdown"].Error)
+	})
+}
@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Hook releases a resource during shutdown. It should return once the
+// context is done, even if the resource was not released cleanly.
+type Hook func(context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Lifecycle ties background workers and resources to the lifetime of the
+// process. Workers started with Go share a context which is canceled on
+// Shutdown; hooks registered with OnShutdown run once all workers returned,
+// in the reverse order of registration.
+type Lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	draining  chan struct{}
+	drainOnce sync.Once
+
+	mu       sync.Mutex
+	hooks    []namedHook
+	shutdown bool
+}
+
+var ErrShutdown = errors.New("lifecycle is shutting down")
+
+func New() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Lifecycle{ctx: ctx, cancel: cancel, draining: make(chan struct{})}
+}
+
+// Drain announces an upcoming shutdown, so the instance can be taken out of
+// rotation while it still serves requests. Shutdown drains implicitly.
+func (l *Lifecycle) Drain() {
+	l.drainOnce.Do(func() { close(l.draining) })
+}
+
+// Draining is closed once Drain or Shutdown has been called.
+func (l *Lifecycle) Draining() <-chan struct{} {
+	return l.draining
+}
+
+// Context returns the context shared by workers. It is canceled as soon as
+// Shutdown is called.
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Done is closed once Shutdown has been called.
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.ctx.Done()
+}
+
+// Go runs fn in a new goroutine. fn must return once its context is canceled.
+func (l *Lifecycle) Go(fn func(context.Context)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.shutdown {
+		return ErrShutdown
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		fn(l.ctx)
+	}()
+
+	return nil
+}
+
+// OnShutdown registers a hook run during Shutdown after every worker returned.
+func (l *Lifecycle) OnShutdown(name string, hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, namedHook{name: name, hook: hook})
+}
+
+// Shutdown cancels the workers, waits for them to return and runs the hooks.
+// If the context expires before the workers returned, the hooks still run
+// with the expired context so resources get released. The errors of all
+// hooks are joined.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	if l.shutdown {
+		l.mu.Unlock()
+		return ErrShutdown
+	}
+	l.shutdown = true
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	l.Drain()
+	l.cancel()
+
+	var errs []error
+
+	stopped := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("waiting for workers: %w", ctx.Err()))
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("stops workers before running hooks in reverse order", func(t *testing.T) {
+		l := New()
+
+		var calls []string
+		stopped := make(chan struct{})
+		l.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			calls = append(calls, "worker")
+			close(stopped)
+		})
+		l.OnShutdown("first", func(context.Context) error {
+			calls = append(calls, "first")
+			return nil
+		})
+		l.OnShutdown("second", func(context.Context) error {
+			calls = append(calls, "second")
+			return nil
+		})
+
+		err := l.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		<-stopped
+		assert.Equal(t, []string{"worker", "second", "first"}, calls)
+	})
+
+	t.Run("runs hooks when workers miss the deadline", func(t *testing.T) {
+		l := New()
+
+		block := make(chan struct{})
+		defer close(block)
+		l.Go(func(context.Context) { <-block })
+
+		hookRan := false
+		l.OnShutdown("database", func(context.Context) error {
+			hookRan = true
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		err := l.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, hookRan)
+	})
+
+	t.Run("joins hook errors", func(t *testing.T) {
+		l := New()
+		failure := errors.New("failed to close")
+		l.OnShutdown("database", func(context.Context) error { return failure })
+
+		err := l.Shutdown(context.TODO())
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorContains(t, err, "database")
+	})
+
+	t.Run("drains before canceling workers", func(t *testing.T) {
+		l := New()
+		l.Drain()
+
+		select {
+		case <-l.Draining():
+		default:
+			t.Error("expected the lifecycle to be draining")
+		}
+		assert.Nil(t, l.Context().Err())
+	})
+
+	t.Run("refuses new workers once shut down", func(t *testing.T) {
+		l := New()
+		assert.Nil(t, l.Shutdown(context.TODO()))
+
+		err := l.Go(func(context.Context) {})
+		assert.ErrorIs(t, err, ErrShutdown)
+		assert.ErrorIs(t, l.Shutdown(context.TODO()), ErrShutdown)
+	})
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger logs the queries of gorm with the logger of their context.
+// Queries are logged at debug level, failed ones at error level and those
+// slower than the threshold at warn level. Query parameters are left out.
+type GormLogger struct {
+	slowThreshold time.Duration
+	level         gormlogger.LogLevel
+}
+
+// NewGormLogger returns a GormLogger. A zero threshold disables slow query
+// warnings.
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{slowThreshold: slowThreshold, level: gormlogger.Info}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *l
+	c.level = level
+	return &c
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= gormlogger.Info {
+		FromContext(ctx).Sugar().Infof(msg, data...)
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= gormlogger.Warn {
+		FromContext(ctx).Sugar().Warnf(msg, data...)
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= gormlogger.Error {
+		FromContext(ctx).Sugar().Errorf(msg, data...)
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	logger := FromContext(ctx).WithOptions(zap.WithCaller(false))
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("elapsed", elapsed),
+			zap.String("source", querySource()),
+		}
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		logger.Error("Query failed", append(fields(), zap.Error(err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		logger.Warn("Slow query", append(fields(), zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info && logger.Core().Enabled(zap.DebugLevel):
+		logger.Debug("Query", fields()...)
+	}
+}
+
+// querySource returns the location of the code which ran the query, outside
+// of gorm and this logger.
+func querySource() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "gorm.io/") && !strings.HasSuffix(frame.File, "/internal/logging/gorm.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// ParamsFilter keeps query parameters, such as password hashes, out of the
+// logs.
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...any) (string, []any) {
+	return sql, nil
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	assert.Equal(t, zap.L(), FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), zap.New(core).With(zap.String("request_id", "1")))
+	ctx = With(ctx, zap.Int64("user_id", 2))
+	FromContext(ctx).Info("message")
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"request_id": "1", "user_id": int64(2)}, entries[0].ContextMap())
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Empty(t, RequestID(context.Background()))
+	assert.Equal(t, "1", RequestID(WithRequestID(context.Background(), "1")))
+}
+
+type record struct {
+	ID    uint
+	Email string
+}
+
+func setupGormTest(t *testing.T, level zapcore.Level, slowThreshold time.Duration) (context.Context, *gorm.DB, *observer.ObservedLogs) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: NewGormLogger(slowThreshold).LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Logger = db.Logger.LogMode(gormlogger.Info)
+
+	core, logs := observer.New(level)
+	ctx := NewContext(context.Background(), zap.New(core).With(zap.String("request_id", "1")))
+
+	return ctx, db, logs
+}
+
+func TestGormLogger(t *testing.T) {
+	t.Run("logs queries with the logger of their context", func(t *testing.T) {
+		ctx, db, logs := setupGormTest(t, zapcore.DebugLevel, time.Hour)
+
+		assert.Nil(t, db.WithContext(ctx).Create(&record{Email: "secret@example.com"}).Error)
+
+		entries := logs.FilterMessage("Query").All()
+		assert.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "1", fields["request_id"])
+		assert.Equal(t, int64(1), fields["rows"])
+		assert.Contains(t, fields["sql"], "INSERT INTO")
+		assert.NotContains(t, fields["sql"], "secret@example.com")
+		assert.Contains(t, fields["source"], "logging_test.go")
+	})
+
+	t.Run("warns about slow queries", func(t *testing.T) {
+		ctx, db, logs := setupGormTest(t, zapcore.InfoLevel, time.Nanosecond)
+
+		var records []record
+		assert.Nil(t, db.WithContext(ctx).Find(&records).Error)
+
+		assert.Equal(t, 0, logs.FilterMessage("Query").Len())
+		assert.Equal(t, 1, logs.FilterMessage("Slow query").FilterLevelExact(zapcore.WarnLevel).Len())
+	})
+
+	t.Run("reports failed queries but not missing records", func(t *testing.T) {
+		ctx, db, logs := setupGormTest(t, zapcore.InfoLevel, 0)
+
+		var r record
+		assert.ErrorIs(t, db.WithContext(ctx).First(&r, 42).Error, gorm.ErrRecordNotFound)
+		assert.NotNil(t, db.WithContext(ctx).Exec("SELECT * FROM missing").Error)
+
+		entries := logs.All()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "Query failed", entries[0].Message)
+		assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	})
+}
@@ -0,0 +1,145 @@
+// Package metrics defines the business-level Prometheus metrics of the
+// application. HTTP metrics are recorded by middleware.PrometheusMiddleware.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const namespace = "hyper_todo"
+
+// Login failure reasons.
+const (
+	LoginInvalidBody   = "invalid_body"
+	LoginUnknownEmail  = "unknown_email"
+	LoginWrongPassword = "wrong_password"
+	LoginLocked        = "locked"
+	LoginError         = "error"
+)
+
+var (
+	TasksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_created_total",
+		Help:      "Total number of tasks created",
+	})
+
+	TasksCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_completed_total",
+		Help:      "Total number of tasks marked as completed",
+	})
+
+	TasksDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_deleted_total",
+		Help:      "Total number of tasks deleted",
+	})
+
+	UsersRegistered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_registered_total",
+		Help:      "Total number of users registered",
+	})
+
+	logins = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Total number of login attempts by result and failure reason",
+		},
+		[]string{"result", "reason"},
+	)
+
+	rateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Total number of requests refused by rate limiting by policy",
+		},
+		[]string{"policy"},
+	)
+
+	jobDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Duration of background job runs in seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		// "job" is reserved for the scrape target by Prometheus.
+		[]string{"name", "result"},
+	)
+)
+
+// LoginSucceeded counts a successful login.
+func LoginSucceeded() {
+	logins.WithLabelValues("success", "").Inc()
+}
+
+// LoginFailed counts a failed login with one of the Login* reasons.
+func LoginFailed(reason string) {
+	logins.WithLabelValues("failure", reason).Inc()
+}
+
+// RateLimited counts a request refused by the rate limit policy.
+func RateLimited(policy string) {
+	rateLimited.WithLabelValues(policy).Inc()
+}
+
+// ObserveJob records a run of the background job which started at start
+// and returned err.
+func ObserveJob(job string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	jobDuration.WithLabelValues(job, result).Observe(time.Since(start).Seconds())
+}
+
+// OverdueCounter counts the tasks which are not completed past their
+// deadline.
+type OverdueCounter interface {
+	CountOverdue(ctx context.Context, now time.Time) (int64, error)
+}
+
+var overdueDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "tasks_overdue"),
+	"Number of tasks which are not completed past their deadline",
+	nil, nil,
+)
+
+type overdueCollector struct {
+	counter OverdueCounter
+	timeout time.Duration
+}
+
+// NewOverdueCollector returns a collector counting overdue tasks on every
+// scrape, giving up after timeout. The gauge is left out of scrapes whose
+// count failed.
+func NewOverdueCollector(counter OverdueCounter, timeout time.Duration) prometheus.Collector {
+	return &overdueCollector{counter: counter, timeout: timeout}
+}
+
+func (c *overdueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- overdueDesc
+}
+
+func (c *overdueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	count, err := c.counter.CountOverdue(ctx, time.Now())
+	if err != nil {
+		zap.L().Warn("Failed to count overdue tasks", zap.Error(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(overdueDesc, prometheus.GaugeValue, float64(count))
+}
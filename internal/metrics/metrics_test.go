@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type overdueCounterFunc func(ctx context.Context, now time.Time) (int64, error)
+
+func (f overdueCounterFunc) CountOverdue(ctx context.Context, now time.Time) (int64, error) {
+	return f(ctx, now)
+}
+
+func TestLogins(t *testing.T) {
+	success := testutil.ToFloat64(logins.WithLabelValues("success", ""))
+	wrongPassword := testutil.ToFloat64(logins.WithLabelValues("failure", LoginWrongPassword))
+
+	LoginSucceeded()
+	LoginFailed(LoginWrongPassword)
+	LoginFailed(LoginWrongPassword)
+
+	assert.Equal(t, success+1, testutil.ToFloat64(logins.WithLabelValues("success", "")))
+	assert.Equal(t, wrongPassword+2, testutil.ToFloat64(logins.WithLabelValues("failure", LoginWrongPassword)))
+}
+
+func TestObserveJob(t *testing.T) {
+	series := testutil.CollectAndCount(jobDuration)
+	ObserveJob("test", time.Now(), nil)
+	ObserveJob("test", time.Now(), errors.New("boom"))
+	ObserveJob("test", time.Now(), errors.New("boom"))
+
+	assert.Equal(t, series+2, testutil.CollectAndCount(jobDuration))
+}
+
+func TestOverdueCollector(t *testing.T) {
+	t.Run("reports the overdue count", func(t *testing.T) {
+		collector := NewOverdueCollector(overdueCounterFunc(func(context.Context, time.Time) (int64, error) {
+			return 3, nil
+		}), time.Second)
+
+		expected := `
+# HELP hyper_todo_tasks_overdue Number of tasks which are not completed past their deadline
+# TYPE hyper_todo_tasks_overdue gauge
+hyper_todo_tasks_overdue 3
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	})
+
+	t.Run("skips the sample when counting fails", func(t *testing.T) {
+		collector := NewOverdueCollector(overdueCounterFunc(func(context.Context, time.Time) (int64, error) {
+			return 0, errors.New("database is down")
+		}), time.Second)
+
+		assert.Equal(t, 0, testutil.CollectAndCount(collector))
+	})
+
+	t.Run("bounds the count by the timeout", func(t *testing.T) {
+		collector := NewOverdueCollector(overdueCounterFunc(func(ctx context.Context, _ time.Time) (int64, error) {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+			return 0, nil
+		}), time.Minute)
+
+		assert.Equal(t, 1, testutil.CollectAndCount(collector))
+	})
+}
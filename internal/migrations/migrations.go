@@ -0,0 +1,317 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey identifies the advisory lock held while migrations are applied, so
+// replicas starting at the same time don't run them concurrently.
+const lockKey = "hyper-todo:migrations"
+
+var (
+	ErrInvalidFileName = errors.New("migration file name must look like 0001_name.up.sql or 0001_name.down.sql")
+	ErrMissingDown     = errors.New("migration has no down file")
+	ErrDuplicate       = errors.New("migration version is used twice")
+	ErrSchemaOutdated  = errors.New("database schema is outdated, run the migrations")
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a pair of SQL scripts changing the schema to Version and back.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration along with the time it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies the embedded migrations to a Postgres database and
+// records them in the schema_migrations table.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version the embedded migrations bring the schema to.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the latest version applied to the database.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	db := m.db.WithContext(ctx)
+
+	exists, err := tableExists(db)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var version int64
+	err = db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+
+	return version, err
+}
+
+// Check returns ErrSchemaOutdated unless every embedded migration is
+// applied. A database ahead of the binary is accepted, so instances of the
+// previous release keep working while a new one is rolled out.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version < m.Latest() {
+		return fmt.Errorf("%w: at version %d, expected %d", ErrSchemaOutdated, version, m.Latest())
+	}
+
+	return nil
+}
+
+// Status lists the embedded migrations and when each of them was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if a, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &a.AppliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts up to steps of the most recently applied migrations and
+// returns the reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+
+				return tx.Delete(&schemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// withLock runs fn on a single connection holding the migrations advisory
+// lock, waiting for other migrators to release it first.
+func (m *Migrator) withLock(ctx context.Context, fn func(*gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(hashtext(?))", lockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", lockKey)
+
+		if err := ensureTable(conn); err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[int64]schemaMigration, error) {
+	exists, err := tableExists(db)
+	if err != nil || !exists {
+		return map[int64]schemaMigration{}, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+func tableExists(db *gorm.DB) (bool, error) {
+	var exists bool
+	err := db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error
+
+	return exists, err
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error
+}
+
+// load reads migrations from the sql directory of fsys, ordered by version.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicate, version)
+		}
+
+		script := &migration.Up
+		if match[3] == "down" {
+			script = &migration.Down
+		}
+
+		if *script != "" {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicate, version)
+		}
+		*script = string(content)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Down == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingDown, migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
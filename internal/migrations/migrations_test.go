@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("loads the embedded migrations in order", func(t *testing.T) {
+		migrations, err := load(files)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, migrations)
+
+		for i, migration := range migrations {
+			assert.Equal(t, int64(i+1), migration.Version)
+			assert.NotEmpty(t, migration.Up)
+			assert.NotEmpty(t, migration.Down)
+		}
+	})
+
+	t.Run("orders migrations by version", func(t *testing.T) {
+		migrations, err := load(fstest.MapFS{
+			"sql/0010_second.up.sql":   {Data: []byte("up 10")},
+			"sql/0010_second.down.sql": {Data: []byte("down 10")},
+			"sql/0002_first.up.sql":    {Data: []byte("up 2")},
+			"sql/0002_first.down.sql":  {Data: []byte("down 2")},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []Migration{
+			{Version: 2, Name: "first", Up: "up 2", Down: "down 2"},
+			{Version: 10, Name: "second", Up: "up 10", Down: "down 10"},
+		}, migrations)
+	})
+
+	t.Run("requires a down file", func(t *testing.T) {
+		_, err := load(fstest.MapFS{
+			"sql/0001_first.up.sql": {Data: []byte("up")},
+		})
+		assert.ErrorIs(t, err, ErrMissingDown)
+	})
+
+	t.Run("rejects reused versions", func(t *testing.T) {
+		_, err := load(fstest.MapFS{
+			"sql/0001_first.up.sql":    {Data: []byte("up")},
+			"sql/0001_first.down.sql":  {Data: []byte("down")},
+			"sql/0001_second.up.sql":   {Data: []byte("up")},
+			"sql/0001_second.down.sql": {Data: []byte("down")},
+		})
+		assert.ErrorIs(t, err, ErrDuplicate)
+	})
+
+	t.Run("rejects unknown files", func(t *testing.T) {
+		_, err := load(fstest.MapFS{
+			"sql/notes.sql": {Data: []byte("select 1")},
+		})
+		assert.ErrorIs(t, err, ErrInvalidFileName)
+	})
+}
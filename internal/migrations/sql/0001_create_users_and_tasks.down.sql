@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_models;
+DROP TABLE IF EXISTS user_models;
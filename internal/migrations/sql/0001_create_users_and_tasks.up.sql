@@ -0,0 +1,29 @@
+-- Tables created with IF NOT EXISTS so databases previously set up by
+-- gorm's AutoMigrate can adopt the versioned migrations as they are.
+
+CREATE TABLE IF NOT EXISTS user_models (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL,
+    email      text NOT NULL,
+    password   text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT uni_user_models_email UNIQUE (email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_models_deleted_at ON user_models (deleted_at);
+
+CREATE TABLE IF NOT EXISTS task_models (
+    id          bigserial PRIMARY KEY,
+    name        text NOT NULL,
+    description text NOT NULL,
+    deadline    timestamptz,
+    completed   boolean DEFAULT false,
+    user_id     bigint NOT NULL,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_models_deleted_at ON task_models (deleted_at);
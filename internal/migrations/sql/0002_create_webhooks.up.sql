@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS webhook_models (
+    id          bigserial PRIMARY KEY,
+    url         text NOT NULL,
+    secret      text NOT NULL,
+    event_types text NOT NULL,
+    active      boolean NOT NULL DEFAULT true,
+    user_id     bigint NOT NULL,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_models_user_id ON webhook_models (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_models_deleted_at ON webhook_models (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_models (
+    id              bigserial PRIMARY KEY,
+    webhook_id      bigint NOT NULL,
+    event_type      text NOT NULL,
+    payload         jsonb NOT NULL,
+    status          text NOT NULL,
+    attempts        bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_error      text,
+    response_status bigint,
+    delivered_at    timestamptz,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_models_webhook_id ON webhook_delivery_models (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_models_status ON webhook_delivery_models (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_models_next_attempt_at ON webhook_delivery_models (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_models_deleted_at ON webhook_delivery_models (deleted_at);
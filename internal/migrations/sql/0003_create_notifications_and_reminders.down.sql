@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS preferences_models;
+DROP TABLE IF EXISTS reminder_models;
+DROP TABLE IF EXISTS notification_models;
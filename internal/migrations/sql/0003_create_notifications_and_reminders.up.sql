@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS notification_models (
+    id         bigserial PRIMARY KEY,
+    task_id    bigint,
+    title      text NOT NULL,
+    body       text NOT NULL,
+    read       boolean NOT NULL DEFAULT false,
+    user_id    bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_models_user_id ON notification_models (user_id);
+CREATE INDEX IF NOT EXISTS idx_notification_models_deleted_at ON notification_models (deleted_at);
+
+CREATE TABLE IF NOT EXISTS reminder_models (
+    id            bigserial PRIMARY KEY,
+    task_id       bigint NOT NULL,
+    remind_before bigint NOT NULL,
+    sent_for      timestamptz,
+    sent_at       timestamptz,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_task_before ON reminder_models (task_id, remind_before);
+CREATE INDEX IF NOT EXISTS idx_reminder_models_deleted_at ON reminder_models (deleted_at);
+
+CREATE TABLE IF NOT EXISTS preferences_models (
+    id                    bigserial PRIMARY KEY,
+    timezone              text NOT NULL DEFAULT 'UTC',
+    quiet_hours_start     text,
+    quiet_hours_end       text,
+    notification_channels text NOT NULL,
+    user_id               bigint NOT NULL,
+    created_at            timestamptz,
+    updated_at            timestamptz,
+    deleted_at            timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_preferences_models_user_id ON preferences_models (user_id);
+CREATE INDEX IF NOT EXISTS idx_preferences_models_deleted_at ON preferences_models (deleted_at);
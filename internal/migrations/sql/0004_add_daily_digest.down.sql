@@ -0,0 +1,6 @@
+ALTER TABLE preferences_models
+    DROP COLUMN IF EXISTS last_digest_on,
+    DROP COLUMN IF EXISTS digest_time,
+    DROP COLUMN IF EXISTS digest_enabled;
+
+ALTER TABLE task_models DROP COLUMN IF EXISTS completed_at;
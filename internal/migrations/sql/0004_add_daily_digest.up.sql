@@ -0,0 +1,10 @@
+ALTER TABLE task_models ADD COLUMN IF NOT EXISTS completed_at timestamptz;
+
+-- Tasks completed before completion times were recorded count as completed
+-- when they were last updated.
+UPDATE task_models SET completed_at = updated_at WHERE completed AND completed_at IS NULL;
+
+ALTER TABLE preferences_models
+    ADD COLUMN IF NOT EXISTS digest_enabled boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS digest_time text NOT NULL DEFAULT '08:00',
+    ADD COLUMN IF NOT EXISTS last_digest_on text;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS task_dependency_models (
+    task_id       bigint NOT NULL,
+    blocked_by_id bigint NOT NULL,
+    created_at    timestamptz,
+    PRIMARY KEY (task_id, blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependency_models_blocked_by_id ON task_dependency_models (blocked_by_id);
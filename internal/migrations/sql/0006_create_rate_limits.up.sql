@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS rate_limit_models (
+    key text PRIMARY KEY,
+    tat timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_models_tat ON rate_limit_models (tat);
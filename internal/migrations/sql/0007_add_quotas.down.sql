@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS api_usage_models;
+
+ALTER TABLE user_models DROP COLUMN IF EXISTS plan;
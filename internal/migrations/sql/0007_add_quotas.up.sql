@@ -0,0 +1,8 @@
+ALTER TABLE user_models ADD COLUMN IF NOT EXISTS plan text NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS api_usage_models (
+    user_id bigint NOT NULL,
+    day     text   NOT NULL,
+    calls   bigint NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
@@ -0,0 +1 @@
+ALTER TABLE user_models ADD COLUMN IF NOT EXISTS locked_at timestamptz;
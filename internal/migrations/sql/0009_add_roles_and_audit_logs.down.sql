@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_log_models;
+
+ALTER TABLE user_models DROP COLUMN IF EXISTS sessions_revoked_at;
+ALTER TABLE user_models DROP COLUMN IF EXISTS role;
@@ -0,0 +1,18 @@
+ALTER TABLE user_models ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'user';
+ALTER TABLE user_models ADD COLUMN IF NOT EXISTS sessions_revoked_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS audit_log_models (
+    id             bigserial PRIMARY KEY,
+    actor_id       bigint,
+    action         text        NOT NULL,
+    target_user_id bigint,
+    details        text,
+    succeeded      boolean     NOT NULL,
+    error          text,
+    request_id     text,
+    created_at     timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_actor_id ON audit_log_models (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_target_user_id ON audit_log_models (target_user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_models_created_at ON audit_log_models (created_at);
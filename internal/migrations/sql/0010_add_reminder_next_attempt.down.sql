@@ -0,0 +1 @@
+ALTER TABLE reminder_models DROP COLUMN IF EXISTS next_attempt_at;
@@ -0,0 +1 @@
+ALTER TABLE reminder_models ADD COLUMN IF NOT EXISTS next_attempt_at timestamptz;
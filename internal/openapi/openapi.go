@@ -0,0 +1,552 @@
+// Package openapi validates HTTP requests and responses against an OpenAPI
+// 3.1 document. Schemas are JSON Schema draft 2020-12, validated with
+// santhosh-tekuri/jsonschema. Only the features the API uses are supported:
+// path, query and header parameters of primitive types, JSON request bodies
+// and $refs local to the document.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// documentURL identifies the document among the schema resources.
+const documentURL = "urn:hyper-todo:openapi"
+
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// ErrMalformedBody is returned for request bodies which are not JSON.
+var ErrMalformedBody = errors.New("request body is not valid JSON")
+
+// Spec is a loaded OpenAPI document.
+type Spec struct {
+	operations map[string]*Operation
+}
+
+// Operation is an operation of the document, with its schemas compiled.
+type Operation struct {
+	Method string
+	Path   string
+
+	parameters []parameter
+	body       *requestBody
+	responses  map[string]response
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	typ      string
+	schema   *jsonschema.Schema
+}
+
+type requestBody struct {
+	required bool
+	schema   *jsonschema.Schema // Of the application/json content, nil for any
+}
+
+type response struct {
+	content map[string]*jsonschema.Schema // By media type, nil schema for any
+}
+
+// Load parses and compiles a YAML or JSON OpenAPI document.
+func Load(data []byte) (*Spec, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+	// Round-trip through JSON for the types the validator expects.
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, errors.New("document must be an object")
+	}
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.1.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, must be 3.1", version)
+	}
+
+	l := &loader{root: root, compiler: jsonschema.NewCompiler()}
+	l.compiler.DefaultDraft(jsonschema.Draft2020)
+	l.compiler.AssertFormat()
+	if err := l.compiler.AddResource(documentURL, doc); err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{operations: map[string]*Operation{}}
+	paths, _ := root["paths"].(map[string]any)
+	for path, rawItem := range paths {
+		item, ptr, err := l.resolve(rawItem, "/paths/"+escape(path))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, method := range methods {
+			rawOp, ok := item[strings.ToLower(method)]
+			if !ok {
+				continue
+			}
+
+			op, err := l.operation(method, path, item, rawOp, ptr+"/"+strings.ToLower(method))
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			spec.operations[method+" "+path] = op
+		}
+	}
+
+	return spec, nil
+}
+
+// Operation returns the operation of the method on the path, as written in
+// the document such as "/tasks/{taskId}", or nil if there is none.
+func (s *Spec) Operation(method, path string) *Operation {
+	return s.operations[method+" "+path]
+}
+
+// Operations returns every operation, sorted by path and method.
+func (s *Spec) Operations() []*Operation {
+	ops := make([]*Operation, 0, len(s.operations))
+	for _, op := range s.operations {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+
+	return ops
+}
+
+var routeParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+// RoutePath converts a gin route such as "/tasks/:taskId" to the path of
+// the document, "/tasks/{taskId}".
+func RoutePath(route string) string {
+	return routeParam.ReplaceAllString(route, "{$1}")
+}
+
+// Violation describes why a part of a request or response does not match
+// the document. Field is the name of a parameter or the dot-separated path of
+// a body property, empty for the body itself.
+type Violation struct {
+	In      string // path, query, header, body or response
+	Field   string
+	Keyword string // The failing schema keyword, such as required or type
+	Message string
+}
+
+// ValidationError lists the violations of a request or response.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		location := v.In
+		if len(v.Field) != 0 {
+			location += " " + v.Field
+		}
+		messages[i] = location + ": " + v.Message
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// ValidateRequest validates the parameters and body of a request, restoring
+// the body for handlers. Bodies are validated as JSON whatever their content
+// type. It returns a *ValidationError, or ErrMalformedBody.
+func (o *Operation) ValidateRequest(req *http.Request, pathParams map[string]string) error {
+	var violations []Violation
+
+	for _, p := range o.parameters {
+		var (
+			value   string
+			present bool
+		)
+		switch p.in {
+		case "path":
+			value, present = pathParams[p.name]
+		case "query":
+			values, ok := req.URL.Query()[p.name]
+			if ok && len(values) != 0 {
+				value, present = values[0], true
+			}
+		case "header":
+			value = req.Header.Get(p.name)
+			present = len(value) != 0
+		}
+
+		if !present {
+			if p.required {
+				violations = append(violations, Violation{In: p.in, Field: p.name, Keyword: "required", Message: "is required"})
+			}
+			continue
+		}
+
+		violations = append(violations, validate(p.schema, p.value(value), p.in, p.name)...)
+	}
+
+	if o.body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			if o.body.required {
+				return ErrMalformedBody
+			}
+		} else if o.body.schema != nil {
+			body, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+			if err != nil {
+				return ErrMalformedBody
+			}
+			violations = append(violations, validate(o.body.schema, body, "body", "")...)
+		}
+	}
+
+	if len(violations) != 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// ValidateResponse validates the status, content type and JSON body of a
+// response. It returns a *ValidationError.
+func (o *Operation) ValidateResponse(status int, header http.Header, body []byte) error {
+	invalid := func(format string, args ...any) error {
+		return &ValidationError{Violations: []Violation{{In: "response", Message: fmt.Sprintf(format, args...)}}}
+	}
+
+	r, ok := o.responses[strconv.Itoa(status)]
+	if !ok {
+		if r, ok = o.responses["default"]; !ok {
+			return invalid("status %d is not documented", status)
+		}
+	}
+
+	if len(r.content) == 0 {
+		if len(body) != 0 {
+			return invalid("status %d has no content, got %d bytes", status, len(body))
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return invalid("invalid content type %q", header.Get("Content-Type"))
+	}
+	schema, ok := r.content[mediaType]
+	if !ok {
+		return invalid("content type %s is not documented for status %d", mediaType, status)
+	}
+	if schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return invalid("body is not valid JSON: %s", err)
+	}
+	if violations := validate(schema, value, "response", ""); len(violations) != 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// value converts the raw value of the parameter to the type of its schema,
+// leaving values which don't convert as strings for the schema to reject.
+func (p parameter) value(raw string) any {
+	switch p.typ {
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case "integer", "number":
+		if n, err := jsonschema.UnmarshalJSON(strings.NewReader(raw)); err == nil {
+			if _, ok := n.(json.Number); ok {
+				return n
+			}
+		}
+	}
+
+	return raw
+}
+
+var printer = message.NewPrinter(language.English)
+
+// validate returns the violations of the value, one per failing keyword.
+func validate(schema *jsonschema.Schema, value any, in, field string) []Violation {
+	err := schema.Validate(value)
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		if err != nil {
+			return []Violation{{In: in, Field: field, Message: err.Error()}}
+		}
+		return nil
+	}
+
+	var violations []Violation
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) != 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+
+		location := append([]string{}, e.InstanceLocation...)
+		if len(field) != 0 {
+			location = append([]string{field}, location...)
+		}
+
+		if required, ok := e.ErrorKind.(*kind.Required); ok {
+			for _, missing := range required.Missing {
+				violations = append(violations, Violation{
+					In:      in,
+					Field:   strings.Join(append(location, missing), "."),
+					Keyword: "required",
+					Message: "is required",
+				})
+			}
+			return
+		}
+
+		var keyword string
+		if path := e.ErrorKind.KeywordPath(); len(path) != 0 {
+			keyword = path[len(path)-1]
+		}
+		violations = append(violations, Violation{
+			In:      in,
+			Field:   strings.Join(location, "."),
+			Keyword: keyword,
+			Message: violationMessage(e.ErrorKind),
+		})
+	}
+	walk(verr)
+
+	// Properties are validated in no particular order.
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+
+	return violations
+}
+
+// violationMessage words the common violations like the validation errors
+// of handlers.
+func violationMessage(k jsonschema.ErrorKind) string {
+	switch k := k.(type) {
+	case *kind.Type:
+		return fmt.Sprintf("must be of type %s", strings.Join(k.Want, " or "))
+	case *kind.Enum:
+		values := make([]string, len(k.Want))
+		for i, v := range k.Want {
+			values[i] = fmt.Sprint(v)
+		}
+		return fmt.Sprintf("must be one of [%s]", strings.Join(values, " "))
+	case *kind.Format:
+		return fmt.Sprintf("must be a valid %s", k.Want)
+	case *kind.MinLength:
+		return fmt.Sprintf("must be at least %d characters long", k.Want)
+	case *kind.MaxLength:
+		return fmt.Sprintf("must be at most %d characters long", k.Want)
+	case *kind.Minimum:
+		return fmt.Sprintf("must be at least %s", k.Want.RatString())
+	case *kind.Maximum:
+		return fmt.Sprintf("must be at most %s", k.Want.RatString())
+	case *kind.MinItems:
+		return fmt.Sprintf("must have at least %d items", k.Want)
+	case *kind.MaxItems:
+		return fmt.Sprintf("must have at most %d items", k.Want)
+	}
+
+	return k.LocalizedString(printer)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// loader compiles the parts of the document.
+type loader struct {
+	root     map[string]any
+	compiler *jsonschema.Compiler
+}
+
+func (l *loader) operation(method, path string, item map[string]any, rawOp any, ptr string) (*Operation, error) {
+	op, ptr, err := l.resolve(rawOp, ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Operation{Method: method, Path: path, responses: map[string]response{}}
+
+	// Parameters of the operation override those of the path item.
+	params := map[string]parameter{}
+	for _, source := range []struct {
+		node map[string]any
+		ptr  string
+	}{{item, ""}, {op, ptr}} {
+		list, _ := source.node["parameters"].([]any)
+		for i, rawParam := range list {
+			paramPtr := source.ptr + "/parameters/" + strconv.Itoa(i)
+			if source.ptr == "" {
+				paramPtr = "/paths/" + escape(path) + "/parameters/" + strconv.Itoa(i)
+			}
+			p, err := l.parameter(rawParam, paramPtr)
+			if err != nil {
+				return nil, err
+			}
+			params[p.in+" "+p.name] = p
+		}
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o.parameters = append(o.parameters, params[key])
+	}
+
+	if rawBody, ok := op["requestBody"]; ok {
+		body, bodyPtr, err := l.resolve(rawBody, ptr+"/requestBody")
+		if err != nil {
+			return nil, err
+		}
+		o.body = &requestBody{}
+		o.body.required, _ = body["required"].(bool)
+		content, _ := body["content"].(map[string]any)
+		if media, ok := content["application/json"].(map[string]any); ok {
+			if _, ok := media["schema"]; ok {
+				if o.body.schema, err = l.compile(bodyPtr + "/content/application~1json/schema"); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	for status, rawResponse := range responses {
+		resp, respPtr, err := l.resolve(rawResponse, ptr+"/responses/"+escape(status))
+		if err != nil {
+			return nil, err
+		}
+
+		r := response{content: map[string]*jsonschema.Schema{}}
+		content, _ := resp["content"].(map[string]any)
+		for mediaType, rawMedia := range content {
+			media, _ := rawMedia.(map[string]any)
+			if _, ok := media["schema"]; !ok {
+				r.content[mediaType] = nil
+				continue
+			}
+			if r.content[mediaType], err = l.compile(respPtr + "/content/" + escape(mediaType) + "/schema"); err != nil {
+				return nil, err
+			}
+		}
+		o.responses[status] = r
+	}
+
+	return o, nil
+}
+
+func (l *loader) parameter(raw any, ptr string) (parameter, error) {
+	node, ptr, err := l.resolve(raw, ptr)
+	if err != nil {
+		return parameter{}, err
+	}
+
+	p := parameter{}
+	p.name, _ = node["name"].(string)
+	p.in, _ = node["in"].(string)
+	p.required, _ = node["required"].(bool)
+
+	schema, ok := node["schema"].(map[string]any)
+	if !ok {
+		return parameter{}, fmt.Errorf("parameter %s: schema is required", p.name)
+	}
+	p.typ, _ = schema["type"].(string)
+	if p.schema, err = l.compile(ptr + "/schema"); err != nil {
+		return parameter{}, err
+	}
+
+	return p, nil
+}
+
+// resolve follows the $ref of a node of the document, returning the node
+// and its JSON pointer.
+func (l *loader) resolve(raw any, ptr string) (map[string]any, string, error) {
+	for range 16 {
+		node, ok := raw.(map[string]any)
+		if !ok {
+			return nil, "", fmt.Errorf("%s: must be an object", ptr)
+		}
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, ptr, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, "", fmt.Errorf("%s: only local $refs are supported, got %q", ptr, ref)
+		}
+
+		ptr = ref[1:]
+		raw = l.root
+		for _, token := range strings.Split(ptr[1:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			parent, ok := raw.(map[string]any)
+			if !ok {
+				return nil, "", fmt.Errorf("unresolved $ref %q", ref)
+			}
+			if raw, ok = parent[token]; !ok {
+				return nil, "", fmt.Errorf("unresolved $ref %q", ref)
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("%s: too many nested $refs", ptr)
+}
+
+func (l *loader) compile(ptr string) (*jsonschema.Schema, error) {
+	return l.compiler.Compile(documentURL + "#" + ptr)
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
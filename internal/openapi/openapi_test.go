@@ -0,0 +1,464 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `
+openapi: 3.1.0
+info: {title: test, version: "1"}
+paths:
+  /tasks/{taskId}:
+    parameters:
+      - $ref: "#/components/parameters/TaskId"
+    patch:
+      parameters:
+        - {name: notify, in: query, schema: {type: boolean}}
+        - {name: X-CSRF-Token, in: header, required: true, schema: {type: string}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Task"}
+      responses:
+        "200":
+          description: Updated task
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Task"}
+        "204":
+          description: No content
+        default:
+          $ref: "#/components/responses/Problem"
+components:
+  parameters:
+    TaskId: {name: taskId, in: path, required: true, schema: {type: integer, minimum: 1}}
+  responses:
+    Problem:
+      description: Error
+      content:
+        application/problem+json:
+          schema: {type: object, required: [code]}
+  schemas:
+    Task:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, minLength: 1}
+        deadline: {type: string, format: date-time}
+        tags: {type: array, items: {type: string}}
+`
+
+func loadTestSpec(t *testing.T) *Spec {
+	spec, err := Load([]byte(testDocument))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestLoad(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	ops := spec.Operations()
+	require.Len(t, ops, 1)
+	assert.Equal(t, "PATCH", ops[0].Method)
+	assert.Equal(t, "/tasks/{taskId}", ops[0].Path)
+	assert.Same(t, ops[0], spec.Operation("PATCH", "/tasks/{taskId}"))
+	assert.Nil(t, spec.Operation("GET", "/tasks/{taskId}"))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{"not yaml", "openapi: [", "parsing document"},
+		{"version", "openapi: 3.0.3\npaths: {}", `unsupported OpenAPI version "3.0.3"`},
+		{"unresolved ref", "openapi: 3.1.0\npaths:\n  /a:\n    $ref: '#/components/pathItems/A'", `unresolved $ref "#/components/pathItems/A"`},
+		{"remote ref", "openapi: 3.1.0\npaths:\n  /a:\n    $ref: 'other.yaml#/A'", "only local $refs are supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]byte(tt.document))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestRoutePath(t *testing.T) {
+	assert.Equal(t, "/tasks", RoutePath("/tasks"))
+	assert.Equal(t, "/tasks/{taskId}/blockers/{blockerId}", RoutePath("/tasks/:taskId/blockers/:blockerId"))
+	assert.Equal(t, "/files/{path}", RoutePath("/files/*path"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	op := loadTestSpec(t).Operation("PATCH", "/tasks/{taskId}")
+
+	tests := []struct {
+		name       string
+		query      string
+		csrf       string
+		taskId     string
+		body       string
+		violations []Violation
+		err        error
+	}{
+		{
+			name:   "valid",
+			query:  "?notify=true",
+			csrf:   "token",
+			taskId: "42",
+			body:   `{"name":"Task","tags":["a"]}`,
+		},
+		{
+			name:   "invalid parameters",
+			query:  "?notify=maybe",
+			taskId: "0",
+			body:   `{"name":"Task"}`,
+			violations: []Violation{
+				{In: "header", Field: "X-CSRF-Token", Keyword: "required", Message: "is required"},
+				{In: "path", Field: "taskId", Keyword: "minimum", Message: "must be at least 1"},
+				{In: "query", Field: "notify", Keyword: "type", Message: "must be of type boolean"},
+			},
+		},
+		{
+			name:   "invalid body",
+			csrf:   "token",
+			taskId: "42",
+			body:   `{"deadline":"tomorrow","tags":[1]}`,
+			violations: []Violation{
+				{In: "body", Field: "name", Keyword: "required", Message: "is required"},
+				{In: "body", Field: "deadline", Keyword: "format", Message: "must be a valid date-time"},
+				{In: "body", Field: "tags.0", Keyword: "type", Message: "must be of type string"},
+			},
+		},
+		{
+			name:   "malformed body",
+			csrf:   "token",
+			taskId: "42",
+			body:   `{"name":`,
+			err:    ErrMalformedBody,
+		},
+		{
+			name:   "missing body",
+			csrf:   "token",
+			taskId: "42",
+			err:    ErrMalformedBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PATCH", "/tasks/"+tt.taskId+tt.query, strings.NewReader(tt.body))
+			if len(tt.csrf) != 0 {
+				req.Header.Set("X-CSRF-Token", tt.csrf)
+			}
+
+			err := op.ValidateRequest(req, map[string]string{"taskId": tt.taskId})
+
+			switch {
+			case tt.err != nil:
+				assert.ErrorIs(t, err, tt.err)
+			case tt.violations == nil:
+				require.NoError(t, err)
+			default:
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.ElementsMatch(t, tt.violations, validationErr.Violations)
+			}
+		})
+	}
+}
+
+func TestValidateRequest_RestoresBody(t *testing.T) {
+	op := loadTestSpec(t).Operation("PATCH", "/tasks/{taskId}")
+	req, _ := http.NewRequest("PATCH", "/tasks/1", strings.NewReader(`{"name":"Task"}`))
+	req.Header.Set("X-CSRF-Token", "token")
+
+	require.NoError(t, op.ValidateRequest(req, map[string]string{"taskId": "1"}))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Task"}`, string(body))
+}
+
+func TestValidateResponse(t *testing.T) {
+	op := loadTestSpec(t).Operation("PATCH", "/tasks/{taskId}")
+	jsonHeader := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	problemHeader := http.Header{"Content-Type": {"application/problem+json"}}
+
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		err    string
+	}{
+		{"valid", 200, jsonHeader, `{"name":"Task"}`, ""},
+		{"no content", 204, http.Header{}, "", ""},
+		{"default", 404, problemHeader, `{"code":"task_not_found"}`, ""},
+		{"invalid body", 200, jsonHeader, `{"name":""}`, "response name:"},
+		{"invalid default", 404, problemHeader, `{}`, "response code: is required"},
+		{"content type", 200, http.Header{"Content-Type": {"text/plain"}}, "Task", "content type text/plain is not documented"},
+		{"unexpected content", 204, http.Header{}, "{}", "status 204 has no content"},
+		{"malformed", 200, jsonHeader, `{"name"`, "body is not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := op.ValidateResponse(tt.status, tt.header, []byte(tt.body))
+			if len(tt.err) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+const templatingDocument = `
+openapi: 3.1.0
+info: {title: test, version: "1"}
+paths:
+  /users/{userId}/tasks/{taskId}:
+    parameters:
+      - {name: userId, in: path, required: true, schema: {type: integer}}
+      - {name: taskId, in: path, required: true, schema: {type: integer}}
+    get:
+      responses:
+        "200": {description: Task}
+    delete:
+      parameters:
+        - {name: taskId, in: path, required: true, schema: {type: string, pattern: "^[a-z]+$"}}
+      responses:
+        "204": {description: Deleted}
+  /files/{path}:
+    $ref: "#/components/pathItems/File"
+components:
+  pathItems:
+    File:
+      get:
+        parameters:
+          - {name: path, in: path, required: true, schema: {type: string}}
+        responses:
+          "200": {description: File}
+`
+
+func TestOperation_PathTemplating(t *testing.T) {
+	spec, err := Load([]byte(templatingDocument))
+	require.NoError(t, err)
+
+	t.Run("finds operations by gin route", func(t *testing.T) {
+		get := spec.Operation("GET", RoutePath("/users/:userId/tasks/:taskId"))
+		require.NotNil(t, get)
+		assert.Equal(t, "/users/{userId}/tasks/{taskId}", get.Path)
+		assert.NotNil(t, spec.Operation("DELETE", RoutePath("/users/:userId/tasks/:taskId")))
+		assert.NotNil(t, spec.Operation("GET", RoutePath("/files/*path")))
+		assert.Nil(t, spec.Operation("GET", "/users/:userId/tasks/:taskId"))
+		assert.Nil(t, spec.Operation("PUT", "/users/{userId}/tasks/{taskId}"))
+	})
+
+	t.Run("applies path item parameters to every operation", func(t *testing.T) {
+		get := spec.Operation("GET", "/users/{userId}/tasks/{taskId}")
+		req, _ := http.NewRequest("GET", "/users/x/tasks/1", nil)
+
+		err := get.ValidateRequest(req, map[string]string{"userId": "x", "taskId": "1"})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []Violation{
+			{In: "path", Field: "userId", Keyword: "type", Message: "must be of type integer"},
+		}, validationErr.Violations)
+	})
+
+	t.Run("overrides path item parameters with those of the operation", func(t *testing.T) {
+		del := spec.Operation("DELETE", "/users/{userId}/tasks/{taskId}")
+		req, _ := http.NewRequest("DELETE", "/users/1/tasks/abc", nil)
+
+		assert.NoError(t, del.ValidateRequest(req, map[string]string{"userId": "1", "taskId": "abc"}))
+
+		err := del.ValidateRequest(req, map[string]string{"userId": "1", "taskId": "42"})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "path", validationErr.Violations[0].In)
+		assert.Equal(t, "taskId", validationErr.Violations[0].Field)
+		assert.Equal(t, "pattern", validationErr.Violations[0].Keyword)
+	})
+
+	t.Run("resolves path items by $ref", func(t *testing.T) {
+		file := spec.Operation("GET", "/files/{path}")
+		req, _ := http.NewRequest("GET", "/files/a/b", nil)
+
+		assert.NoError(t, file.ValidateRequest(req, map[string]string{"path": "/a/b"}))
+
+		err := file.ValidateRequest(req, map[string]string{})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []Violation{{In: "path", Field: "path", Keyword: "required", Message: "is required"}}, validationErr.Violations)
+	})
+}
+
+const parametersDocument = `
+openapi: 3.1.0
+info: {title: test, version: "1"}
+paths:
+  /tasks:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, minimum: 1, maximum: 100}}
+        - {name: ratio, in: query, schema: {type: number}}
+        - {name: done, in: query, schema: {type: boolean}}
+        - {name: q, in: query, schema: {type: string, maxLength: 3}}
+        - {name: status, in: query, schema: {type: string, enum: [open, closed]}}
+        - {name: X-Page, in: header, schema: {type: integer}}
+      responses:
+        "200": {description: Tasks}
+`
+
+func TestValidateRequest_ParameterCoercion(t *testing.T) {
+	spec, err := Load([]byte(parametersDocument))
+	require.NoError(t, err)
+	op := spec.Operation("GET", "/tasks")
+
+	tests := []struct {
+		name      string
+		query     string
+		header    string
+		violation *Violation
+	}{
+		{name: "integer", query: "limit=42"},
+		{name: "integer bounds", query: "limit=101", violation: &Violation{In: "query", Field: "limit", Keyword: "maximum", Message: "must be at most 100"}},
+		{name: "fractional integer", query: "limit=4.2", violation: &Violation{In: "query", Field: "limit", Keyword: "type", Message: "must be of type integer"}},
+		{name: "non-numeric integer", query: "limit=ten", violation: &Violation{In: "query", Field: "limit", Keyword: "type", Message: "must be of type integer"}},
+		{name: "empty integer", query: "limit=", violation: &Violation{In: "query", Field: "limit", Keyword: "type", Message: "must be of type integer"}},
+		{name: "number", query: "ratio=0.5"},
+		{name: "number exponent", query: "ratio=1e3"},
+		{name: "non-numeric number", query: "ratio=half", violation: &Violation{In: "query", Field: "ratio", Keyword: "type", Message: "must be of type number"}},
+		{name: "boolean", query: "done=false"},
+		{name: "boolean digit", query: "done=1"},
+		{name: "non-boolean", query: "done=yes", violation: &Violation{In: "query", Field: "done", Keyword: "type", Message: "must be of type boolean"}},
+		{name: "numeric string", query: "q=42"},
+		{name: "string length", query: "q=long", violation: &Violation{In: "query", Field: "q", Keyword: "maxLength", Message: "must be at most 3 characters long"}},
+		{name: "enum", query: "status=open"},
+		{name: "not in enum", query: "status=done", violation: &Violation{In: "query", Field: "status", Keyword: "enum", Message: "must be one of [open closed]"}},
+		{name: "first of repeated values", query: "limit=1&limit=ten"},
+		{name: "header", header: "2"},
+		{name: "invalid header", header: "two", violation: &Violation{In: "header", Field: "X-Page", Keyword: "type", Message: "must be of type integer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/tasks?"+tt.query, nil)
+			if len(tt.header) != 0 {
+				req.Header.Set("x-page", tt.header)
+			}
+
+			err := op.ValidateRequest(req, nil)
+			if tt.violation == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []Violation{*tt.violation}, validationErr.Violations)
+		})
+	}
+}
+
+const contentDocument = `
+openapi: 3.1.0
+info: {title: test, version: "1"}
+paths:
+  /tasks:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, required: [name]}
+      responses:
+        "201":
+          description: Created task
+          content:
+            application/json:
+              schema: {type: object, required: [id]}
+            application/vnd.task+json:
+              schema: {type: object, required: [id]}
+        "202":
+          description: Export
+          content:
+            text/csv: {}
+`
+
+func TestContentTypes(t *testing.T) {
+	spec, err := Load([]byte(contentDocument))
+	require.NoError(t, err)
+	op := spec.Operation("POST", "/tasks")
+
+	t.Run("validates request bodies as JSON whatever their content type", func(t *testing.T) {
+		for _, contentType := range []string{"application/json", "text/plain", ""} {
+			req, _ := http.NewRequest("POST", "/tasks", strings.NewReader(`{}`))
+			if len(contentType) != 0 {
+				req.Header.Set("Content-Type", contentType)
+			}
+
+			err := op.ValidateRequest(req, nil)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr, contentType)
+			assert.Equal(t, "name", validationErr.Violations[0].Field, contentType)
+		}
+	})
+
+	t.Run("accepts missing optional request bodies", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/tasks", nil)
+		req.Body = http.NoBody
+
+		assert.NoError(t, op.ValidateRequest(req, nil))
+	})
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		err         string
+	}{
+		{"json", 201, "application/json", `{"id":1}`, ""},
+		{"media type parameters", 201, "application/json; charset=utf-8", `{"id":1}`, ""},
+		{"case of the media type", 201, "Application/JSON", `{"id":1}`, ""},
+		{"json suffix", 201, "application/vnd.task+json", `{}`, "response id: is required"},
+		{"schemaless media type", 202, "text/csv", "id\n1\n", ""},
+		{"undocumented media type", 201, "text/plain", `{"id":1}`, "content type text/plain is not documented for status 201"},
+		{"missing content type", 201, "", `{"id":1}`, `invalid content type ""`},
+		{"malformed content type", 201, "application/json; charset", `{"id":1}`, "invalid content type"},
+	}
+
+	for _, tt := range tests {
+		t.Run("response "+tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if len(tt.contentType) != 0 {
+				header.Set("Content-Type", tt.contentType)
+			}
+
+			err := op.ValidateResponse(tt.status, header, []byte(tt.body))
+			if len(tt.err) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often stores forget buckets which are full again.
+const pruneInterval = time.Minute
+
+// MemoryStore keeps buckets in memory, limits only hold per replica.
+type MemoryStore struct {
+	mu         sync.Mutex
+	buckets    map[string]time.Time
+	lastPruned time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]time.Time{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rate Rate, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPruned) >= pruneInterval {
+		s.prune(now)
+	}
+
+	tat, result := Take(s.buckets[key], now, rate)
+	s.buckets[key] = tat
+
+	return result, nil
+}
+
+// prune deletes the buckets which are full at now, they are equivalent to
+// new ones.
+func (s *MemoryStore) prune(now time.Time) {
+	for key, tat := range s.buckets {
+		if !tat.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastPruned = now
+}
+
+// Len returns the number of buckets kept.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
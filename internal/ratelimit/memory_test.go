@@ -0,0 +1,31 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/krau5/hyper-todo/internal/ratelimit/ratelimittest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ratelimittest.Run(t, func(t *testing.T) ratelimit.Store {
+		return ratelimit.NewMemoryStore()
+	})
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	ctx := context.TODO()
+	rate := ratelimit.Rate{Requests: 10, Period: time.Second}
+	now := time.Now()
+
+	store := ratelimit.NewMemoryStore()
+	store.Take(ctx, "first", rate, now)
+	store.Take(ctx, "second", rate, now)
+	assert.Equal(t, 2, store.Len())
+
+	store.Take(ctx, "third", rate, now.Add(2*time.Minute))
+	assert.Equal(t, 1, store.Len())
+}
@@ -0,0 +1,172 @@
+// Package ratelimit limits how often clients may call the API. Limits are
+// token buckets implemented with the generic cell rate algorithm (GCRA), so
+// the state of a bucket is a single time which stores update atomically.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate allows bursts of up to Requests requests, refilled evenly over Period.
+// The zero Rate does not limit requests.
+type Rate struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited reports whether the rate lets every request through.
+func (r Rate) Unlimited() bool {
+	return r.Requests <= 0
+}
+
+// String formats the rate as parsed by ParseRate.
+func (r Rate) String() string {
+	if r.Unlimited() {
+		return "0"
+	}
+
+	return fmt.Sprintf("%d/%s", r.Requests, r.Period)
+}
+
+// interval is the time it takes to refill one request.
+func (r Rate) interval() time.Duration {
+	return r.Period / time.Duration(r.Requests)
+}
+
+var ErrInvalidRate = errors.New("rate must be 0 or <requests>/<period>, e.g. 60/1m")
+
+// ParseRate parses a rate formatted as "<requests>/<period>", e.g. "60/1m".
+// "0" disables the limit.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return Rate{}, nil
+	}
+
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("%w, got %q", ErrInvalidRate, s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("%w, got %q", ErrInvalidRate, s)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d < time.Duration(n) {
+		return Rate{}, fmt.Errorf("%w, got %q", ErrInvalidRate, s)
+	}
+
+	return Rate{Requests: n, Period: d}, nil
+}
+
+// Policies selects the rate of a request. Routes are keyed by "METHOD /path"
+// or "/path" for every method, where the path is the route pattern such as
+// "/tasks/:taskId". Requests to other routes get the Anonymous rate, keyed by
+// client IP, or the Authenticated rate, keyed by user.
+type Policies struct {
+	Anonymous     Rate
+	Authenticated Rate
+	Routes        map[string]Rate
+}
+
+// ParseRoutes parses comma-separated route rates such as
+// "POST /login=10/1m,POST /register=5/1h".
+func ParseRoutes(s string) (map[string]Rate, error) {
+	routes := map[string]Rate{}
+	if len(strings.TrimSpace(s)) == 0 {
+		return routes, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		route, rate, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("route rate must be <route>=<rate>, got %q", strings.TrimSpace(entry))
+		}
+
+		route = strings.Join(strings.Fields(route), " ")
+		method, path, hasMethod := strings.Cut(route, " ")
+		if !hasMethod {
+			method, path = "", route
+		}
+		if !strings.HasPrefix(path, "/") || strings.Contains(path, " ") || method != strings.ToUpper(method) {
+			return nil, fmt.Errorf("route must be \"METHOD /path\" or \"/path\", got %q", route)
+		}
+
+		r, err := ParseRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", route, err)
+		}
+		routes[route] = r
+	}
+
+	return routes, nil
+}
+
+// For returns the name and rate of the policy applying to the request. The
+// name scopes buckets: route policies have a bucket per route and client.
+func (p Policies) For(method, route string, authenticated bool) (string, Rate) {
+	if rate, ok := p.Routes[method+" "+route]; ok {
+		return method + " " + route, rate
+	}
+	if rate, ok := p.Routes[route]; ok {
+		return route, rate
+	}
+
+	if authenticated {
+		return "authenticated", p.Authenticated
+	}
+
+	return "anonymous", p.Anonymous
+}
+
+// Result describes the state of a bucket after taking a request from it.
+type Result struct {
+	Allowed bool
+	// Remaining is the number of requests which would be allowed right away.
+	Remaining int
+	// RetryAfter is the time to wait before the request would be allowed,
+	// zero if it was.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps the buckets. Take must be atomic for a key, across every
+// replica sharing the store.
+type Store interface {
+	Take(ctx context.Context, key string, rate Rate, now time.Time) (Result, error)
+}
+
+// Take applies a request at now to a bucket which is full at tat, the
+// theoretical arrival time of the next request, zero for a new bucket. It
+// returns the new tat, unchanged if the request is refused.
+func Take(tat, now time.Time, rate Rate) (time.Time, Result) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	interval := rate.interval()
+	next := tat.Add(interval)
+	allowAt := next.Add(-rate.Period)
+	if now.Before(allowAt) {
+		return tat, Result{
+			Allowed:    false,
+			Remaining:  0,
+			RetryAfter: allowAt.Sub(now),
+			Reset:      tat.Sub(now),
+		}
+	}
+
+	return next, Result{
+		Allowed:   true,
+		Remaining: int(now.Sub(allowAt) / interval),
+		Reset:     next.Sub(now),
+	}
+}
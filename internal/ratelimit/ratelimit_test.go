@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Rate
+	}{
+		{"60/1m", Rate{Requests: 60, Period: time.Minute}},
+		{" 5/1h ", Rate{Requests: 5, Period: time.Hour}},
+		{"0", Rate{}},
+	} {
+		rate, err := ParseRate(tc.in)
+		assert.Nil(t, err, tc.in)
+		assert.Equal(t, tc.want, rate, tc.in)
+	}
+
+	for _, in := range []string{"", "60", "60/", "-1/1m", "a/1m", "60/minute", "10/5ns"} {
+		_, err := ParseRate(in)
+		assert.ErrorIs(t, err, ErrInvalidRate, in)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("POST /login=10/1m, /tasks = 100/1m ,GET  /tasks/:taskId=0")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Rate{
+		"POST /login":        {Requests: 10, Period: time.Minute},
+		"/tasks":             {Requests: 100, Period: time.Minute},
+		"GET /tasks/:taskId": {},
+	}, routes)
+
+	routes, err = ParseRoutes("")
+	assert.Nil(t, err)
+	assert.Empty(t, routes)
+
+	for _, in := range []string{"POST /login", "login=1/1m", "post /login=1/1m", "POST /login=1"} {
+		_, err := ParseRoutes(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	login := Rate{Requests: 10, Period: time.Minute}
+	tasks := Rate{Requests: 100, Period: time.Minute}
+	policies := Policies{
+		Anonymous:     Rate{Requests: 1, Period: time.Second},
+		Authenticated: Rate{Requests: 2, Period: time.Second},
+		Routes:        map[string]Rate{"POST /login": login, "/tasks": tasks},
+	}
+
+	name, rate := policies.For("POST", "/login", false)
+	assert.Equal(t, "POST /login", name)
+	assert.Equal(t, login, rate)
+
+	name, rate = policies.For("GET", "/tasks", true)
+	assert.Equal(t, "/tasks", name)
+	assert.Equal(t, tasks, rate)
+
+	name, rate = policies.For("GET", "/login", false)
+	assert.Equal(t, "anonymous", name)
+	assert.Equal(t, policies.Anonymous, rate)
+
+	name, rate = policies.For("GET", "/me", true)
+	assert.Equal(t, "authenticated", name)
+	assert.Equal(t, policies.Authenticated, rate)
+}
+
+func TestTake(t *testing.T) {
+	rate := Rate{Requests: 2, Period: 10 * time.Second}
+	now := time.Now()
+
+	tat, result := Take(time.Time{}, now, rate)
+	assert.Equal(t, Result{Allowed: true, Remaining: 1, Reset: 5 * time.Second}, result)
+
+	tat, result = Take(tat, now, rate)
+	assert.Equal(t, Result{Allowed: true, Remaining: 0, Reset: 10 * time.Second}, result)
+
+	refused, result := Take(tat, now.Add(time.Second), rate)
+	assert.Equal(t, tat, refused)
+	assert.Equal(t, Result{Allowed: false, RetryAfter: 4 * time.Second, Reset: 9 * time.Second}, result)
+}
@@ -0,0 +1,94 @@
+// Package ratelimittest holds the conformance suite every rate limit store
+// must pass.
+package ratelimittest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run runs the suite. setup is called for every test and must return an
+// empty store.
+func Run(t *testing.T, setup func(t *testing.T) ratelimit.Store) {
+	ctx := context.TODO()
+	rate := ratelimit.Rate{Requests: 3, Period: time.Minute}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("allows a burst then refuses", func(t *testing.T) {
+		store := setup(t)
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			result, err := store.Take(ctx, "key", rate, now)
+			require.Nil(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, remaining, result.Remaining)
+		}
+
+		result, err := store.Take(ctx, "key", rate, now)
+		require.Nil(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.InDelta(t, 20*time.Second, result.RetryAfter, float64(time.Millisecond))
+	})
+
+	t.Run("refills over the period", func(t *testing.T) {
+		store := setup(t)
+		for range 3 {
+			store.Take(ctx, "key", rate, now)
+		}
+
+		result, err := store.Take(ctx, "key", rate, now.Add(20*time.Second))
+		require.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+
+		result, err = store.Take(ctx, "key", rate, now.Add(time.Hour))
+		require.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("keeps a bucket per key", func(t *testing.T) {
+		store := setup(t)
+		for range 3 {
+			store.Take(ctx, "first", rate, now)
+		}
+
+		result, err := store.Take(ctx, "second", rate, now)
+		require.Nil(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("takes requests atomically", func(t *testing.T) {
+		store := setup(t)
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			allowed int
+		)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := store.Take(ctx, "key", rate, now)
+				assert.Nil(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if result.Allowed {
+					allowed++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, allowed)
+	})
+}
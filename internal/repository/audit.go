@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+)
+
+// AuditLogModel stores audit logs, which are never updated nor deleted.
+type AuditLogModel struct {
+	domain.AuditLog
+}
+
+type auditLogsRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogsRepository(db *gorm.DB) *auditLogsRepository {
+	return &auditLogsRepository{db: db}
+}
+
+func (r *auditLogsRepository) Create(ctx context.Context, log domain.AuditLog) (domain.AuditLog, error) {
+	logModel := AuditLogModel{AuditLog: log}
+
+	result := r.db.WithContext(ctx).Create(&logModel)
+	if result.Error != nil {
+		return domain.AuditLog{}, translateError(r.db, result.Error)
+	}
+
+	return logModel.AuditLog, nil
+}
+
+func (r *auditLogsRepository) List(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	query := r.db.WithContext(ctx).Order("id DESC").Limit(filter.Limit)
+	if filter.ActorId != 0 {
+		query = query.Where("actor_id = ?", filter.ActorId)
+	}
+	if filter.TargetUserId != 0 {
+		query = query.Where("target_user_id = ?", filter.TargetUserId)
+	}
+	if len(filter.Action) != 0 {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	rawLogs := []AuditLogModel{}
+	if result := query.Find(&rawLogs); result.Error != nil {
+		return []domain.AuditLog{}, translateError(r.db, result.Error)
+	}
+
+	logs := make([]domain.AuditLog, len(rawLogs))
+	for i, logModel := range rawLogs {
+		logs[i] = logModel.AuditLog
+	}
+
+	return logs, nil
+}
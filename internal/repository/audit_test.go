@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogsRepository(t *testing.T) {
+	ctx := context.TODO()
+	repo := NewAuditLogsRepository(newTestDB(t))
+
+	logs := []domain.AuditLog{
+		{ActorId: 1, Action: "users.search", Details: "q=doe", Succeeded: true, RequestId: "first", CreatedAt: deadline},
+		{ActorId: 1, Action: "users.disable", TargetUserId: 3, Succeeded: true, CreatedAt: deadline},
+		{ActorId: 2, Action: "users.disable", TargetUserId: 3, Error: "forbidden", CreatedAt: deadline},
+		{Action: "users.lock", TargetUserId: 4, Succeeded: true, CreatedAt: deadline},
+	}
+	for i, log := range logs {
+		created, err := repo.Create(ctx, log)
+		require.Nil(t, err)
+		require.NotZero(t, created.ID)
+		logs[i].ID = created.ID
+	}
+
+	logId := func(log domain.AuditLog) int64 { return log.ID }
+	for _, tc := range []struct {
+		name     string
+		filter   domain.AuditLogFilter
+		expected []int64
+	}{
+		{"lists the newest first", domain.AuditLogFilter{Limit: 10}, []int64{logs[3].ID, logs[2].ID, logs[1].ID, logs[0].ID}},
+		{"limits the logs", domain.AuditLogFilter{Limit: 1}, []int64{logs[3].ID}},
+		{"filters by actor", domain.AuditLogFilter{ActorId: 1, Limit: 10}, []int64{logs[1].ID, logs[0].ID}},
+		{"filters by target", domain.AuditLogFilter{TargetUserId: 3, Limit: 10}, []int64{logs[2].ID, logs[1].ID}},
+		{"filters by action", domain.AuditLogFilter{Action: "users.disable", ActorId: 2, Limit: 10}, []int64{logs[2].ID}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			found, err := repo.List(ctx, tc.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, ids(found, logId))
+		})
+	}
+
+	found, err := repo.List(ctx, domain.AuditLogFilter{Limit: 10})
+	require.Nil(t, err)
+	first := found[len(found)-1]
+	assert.True(t, deadline.Equal(first.CreatedAt))
+	first.CreatedAt = logs[0].CreatedAt
+	assert.Equal(t, logs[0], first)
+}
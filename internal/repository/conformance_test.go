@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/krau5/hyper-todo/internal/repository/repositorytest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func repositories(db *gorm.DB) repositorytest.Repositories {
+	return repositorytest.Repositories{
+		Users:        NewUserRepository(db),
+		Tasks:        NewTasksRepository(db),
+		Dependencies: NewDependenciesRepository(db),
+		Usage:        NewUsageRepository(db),
+	}
+}
+
+func TestPostgresConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		return repositories(newTestDB(t))
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db, err := OpenSQLite(":memory:", &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		})
+
+		return repositories(db)
+	})
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskDependencyModel has no surrogate key or soft deletion: a dependency is
+// identified by the pair of tasks and removing it deletes the row.
+type TaskDependencyModel struct {
+	domain.TaskDependency
+	CreatedAt time.Time
+}
+
+type dependenciesRepository struct {
+	db *gorm.DB
+}
+
+func NewDependenciesRepository(db *gorm.DB) *dependenciesRepository {
+	return &dependenciesRepository{db: db}
+}
+
+func (r *dependenciesRepository) Create(ctx context.Context, taskId, blockedById int64) error {
+	dependencyModel := TaskDependencyModel{
+		TaskDependency: domain.TaskDependency{TaskId: taskId, BlockedById: blockedById},
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&dependencyModel)
+	return translateError(r.db, result.Error)
+}
+
+// CreateChecked creates a dependency between tasks of the user unless check
+// fails on the dependencies the user has. Concurrent calls for the user are
+// serialized by locking the user's row, so dependencies checked one by one
+// can't be created together.
+func (r *dependenciesRepository) CreateChecked(ctx context.Context, userId, taskId, blockedById int64, check func([]domain.TaskDependency) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", userId).Take(&UserModel{}).Error; err != nil {
+			return err
+		}
+
+		dependencies, err := getDependencies(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		if err := check(dependencies); err != nil {
+			return err
+		}
+
+		dependencyModel := TaskDependencyModel{
+			TaskDependency: domain.TaskDependency{TaskId: taskId, BlockedById: blockedById},
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dependencyModel).Error
+	})
+
+	return translateError(r.db, err)
+}
+
+func (r *dependenciesRepository) Delete(ctx context.Context, taskId, blockedById int64) error {
+	result := r.db.WithContext(ctx).
+		Where("task_id = ? AND blocked_by_id = ?", taskId, blockedById).
+		Delete(&TaskDependencyModel{})
+
+	return translateError(r.db, result.Error)
+}
+
+func (r *dependenciesRepository) GetByUser(ctx context.Context, userId int64) ([]domain.TaskDependency, error) {
+	dependencies, err := getDependencies(r.db.WithContext(ctx), userId)
+	if err != nil {
+		return []domain.TaskDependency{}, translateError(r.db, err)
+	}
+
+	return dependencies, nil
+}
+
+// getDependencies returns the dependencies between the live tasks of a user.
+func getDependencies(db *gorm.DB, userId int64) ([]domain.TaskDependency, error) {
+	rawDependencies := []TaskDependencyModel{}
+	result := db.
+		Joins("JOIN task_models ON task_models.id = task_dependency_models.task_id AND task_models.deleted_at IS NULL").
+		Where("task_models.user_id = ?", userId).
+		Find(&rawDependencies)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	dependencies := make([]domain.TaskDependency, len(rawDependencies))
+	for i, dependencyModel := range rawDependencies {
+		dependencies[i] = dependencyModel.TaskDependency
+	}
+
+	return dependencies, nil
+}
+
+// GetBlockers returns the tasks blocking a task.
+func (r *dependenciesRepository) GetBlockers(ctx context.Context, taskId int64) ([]domain.Task, error) {
+	return r.findTasks(ctx, "task_dependency_models.blocked_by_id = task_models.id", "task_dependency_models.task_id = ?", taskId)
+}
+
+// GetBlocked returns the tasks blocked by a task.
+func (r *dependenciesRepository) GetBlocked(ctx context.Context, taskId int64) ([]domain.Task, error) {
+	return r.findTasks(ctx, "task_dependency_models.task_id = task_models.id", "task_dependency_models.blocked_by_id = ?", taskId)
+}
+
+func (r *dependenciesRepository) findTasks(ctx context.Context, join, where string, taskId int64) ([]domain.Task, error) {
+	rawTasks := []TaskModel{}
+	result := r.db.WithContext(ctx).
+		Select(taskColumns).
+		Joins("JOIN task_dependency_models ON "+join).
+		Where(where, taskId).
+		Order("task_models.id").
+		Find(&rawTasks)
+	if result.Error != nil {
+		return []domain.Task{}, translateError(r.db, result.Error)
+	}
+
+	tasks := make([]domain.Task, len(rawTasks))
+	for i, taskModel := range rawTasks {
+		tasks[i] = taskModel.Task
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependenciesRepository(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("creates dependencies once", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		blocked := createTask(t, db, "blocked", deadline, u.ID)
+		blocker := createTask(t, db, "blocker", deadline, u.ID)
+		repo := NewDependenciesRepository(db)
+
+		assert.Nil(t, repo.Create(ctx, blocked.ID, blocker.ID))
+		assert.Nil(t, repo.Create(ctx, blocked.ID, blocker.ID))
+
+		dependencies, err := repo.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TaskDependency{{TaskId: blocked.ID, BlockedById: blocker.ID}}, dependencies)
+	})
+
+	t.Run("gets blockers and blocked tasks in order", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		task := createTask(t, db, "task", deadline, u.ID)
+		first := createTask(t, db, "first", deadline, u.ID)
+		second := createTask(t, db, "second", deadline, u.ID)
+		repo := NewDependenciesRepository(db)
+		assert.Nil(t, repo.Create(ctx, task.ID, second.ID))
+		assert.Nil(t, repo.Create(ctx, task.ID, first.ID))
+		assert.Nil(t, repo.Create(ctx, second.ID, first.ID))
+
+		blockers, err := repo.GetBlockers(ctx, task.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{first.ID, second.ID}, ids(blockers, taskId))
+
+		blocked, err := repo.GetBlocked(ctx, first.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{task.ID, second.ID}, ids(blocked, taskId))
+		for _, task := range blocked {
+			assert.True(t, task.Blocked)
+		}
+	})
+
+	t.Run("computes the blocked flag", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		blocked := createTask(t, db, "blocked", deadline, u.ID)
+		blocker := createTask(t, db, "blocker", deadline, u.ID)
+		assert.Nil(t, NewDependenciesRepository(db).Create(ctx, blocked.ID, blocker.ID))
+		tasks := NewTasksRepository(db)
+
+		found, err := tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.True(t, found.Blocked)
+
+		byUser, err := tasks.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		for _, task := range byUser {
+			assert.Equal(t, task.ID == blocked.ID, task.Blocked)
+		}
+
+		completed := true
+		_, err = tasks.UpdateById(ctx, blocker.ID, domain.UpdateTaskData{Completed: &completed})
+		assert.Nil(t, err)
+
+		found, err = tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Blocked)
+	})
+
+	t.Run("ignores dependencies of other users and deleted tasks", func(t *testing.T) {
+		db := newTestDB(t)
+		first := createUser(t, db, "first@example.com")
+		second := createUser(t, db, "second@example.com")
+		a := createTask(t, db, "a", deadline, first.ID)
+		b := createTask(t, db, "b", deadline, first.ID)
+		c := createTask(t, db, "c", deadline, second.ID)
+		d := createTask(t, db, "d", deadline, second.ID)
+		repo := NewDependenciesRepository(db)
+		assert.Nil(t, repo.Create(ctx, a.ID, b.ID))
+		assert.Nil(t, repo.Create(ctx, c.ID, d.ID))
+		assert.Nil(t, db.Delete(&TaskModel{}, c.ID).Error)
+
+		dependencies, err := repo.GetByUser(ctx, first.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TaskDependency{{TaskId: a.ID, BlockedById: b.ID}}, dependencies)
+
+		dependencies, err = repo.GetByUser(ctx, second.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, dependencies)
+
+		blocked, err := repo.GetBlocked(ctx, d.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, blocked)
+	})
+
+	t.Run("deletes dependencies", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		blocked := createTask(t, db, "blocked", deadline, u.ID)
+		blocker := createTask(t, db, "blocker", deadline, u.ID)
+		repo := NewDependenciesRepository(db)
+		assert.Nil(t, repo.Create(ctx, blocked.ID, blocker.ID))
+
+		assert.Nil(t, repo.Delete(ctx, blocked.ID, blocker.ID))
+		assert.Nil(t, repo.Delete(ctx, blocked.ID, blocker.ID), "deleting a missing dependency is a no-op")
+
+		blockers, err := repo.GetBlockers(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, blockers)
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+)
+
+// translateError maps gorm and driver errors to the errors of the domain
+// package, so callers don't depend on the database in use.
+func translateError(db *gorm.DB, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domain.ErrDuplicate
+	}
+
+	return err
+}
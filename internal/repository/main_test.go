@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/repository/pgtest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The integration tests run against Postgres, see the pgtest package for how
+// the server is found. They are skipped if there is none.
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	return pgtest.New(t)
+}
+
+// newSQLiteTestDB returns an empty in-memory SQLite database.
+func newSQLiteTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := OpenSQLite(":memory:", &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	return db
+}
+
+// deadline is rounded since Postgres stores times with microsecond precision.
+var deadline = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+func createUser(t *testing.T, db *gorm.DB, email string) domain.User {
+	t.Helper()
+
+	repo := NewUserRepository(db)
+	if err := repo.Create(context.TODO(), "user", email, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := repo.GetByEmail(context.TODO(), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u
+}
+
+func createTask(t *testing.T, db *gorm.DB, name string, deadline time.Time, userId int64) domain.Task {
+	t.Helper()
+
+	created, err := NewTasksRepository(db).Create(context.TODO(), name, "description", deadline, userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+func ids[T any](items []T, id func(T) int64) []int64 {
+	result := make([]int64, len(items))
+	for i, item := range items {
+		result[i] = id(item)
+	}
+
+	return result
+}
+
+func taskId(t domain.Task) int64 { return t.ID }
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+type dependenciesRepository struct {
+	store *Store
+}
+
+func NewDependenciesRepository(store *Store) *dependenciesRepository {
+	return &dependenciesRepository{store: store}
+}
+
+func (r *dependenciesRepository) Create(ctx context.Context, taskId, blockedById int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.dependencies[domain.TaskDependency{TaskId: taskId, BlockedById: blockedById}] = struct{}{}
+
+	return nil
+}
+
+func (r *dependenciesRepository) Delete(ctx context.Context, taskId, blockedById int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.dependencies, domain.TaskDependency{TaskId: taskId, BlockedById: blockedById})
+
+	return nil
+}
+
+// CreateChecked creates a dependency between tasks of the user unless check
+// fails on the dependencies the user has, holding the lock meanwhile.
+func (r *dependenciesRepository) CreateChecked(ctx context.Context, userId, taskId, blockedById int64, check func([]domain.TaskDependency) error) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return domain.ErrNotFound
+	}
+
+	if err := check(r.store.userDependencies(userId)); err != nil {
+		return err
+	}
+
+	r.store.dependencies[domain.TaskDependency{TaskId: taskId, BlockedById: blockedById}] = struct{}{}
+
+	return nil
+}
+
+func (r *dependenciesRepository) GetByUser(ctx context.Context, userId int64) ([]domain.TaskDependency, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.userDependencies(userId), nil
+}
+
+// GetBlockers returns the tasks blocking a task.
+func (r *dependenciesRepository) GetBlockers(ctx context.Context, taskId int64) ([]domain.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.findTasks(func(task domain.Task) bool {
+		_, ok := r.store.dependencies[domain.TaskDependency{TaskId: taskId, BlockedById: task.ID}]
+		return ok
+	}), nil
+}
+
+// GetBlocked returns the tasks blocked by a task.
+func (r *dependenciesRepository) GetBlocked(ctx context.Context, taskId int64) ([]domain.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.findTasks(func(task domain.Task) bool {
+		_, ok := r.store.dependencies[domain.TaskDependency{TaskId: task.ID, BlockedById: taskId}]
+		return ok
+	}), nil
+}
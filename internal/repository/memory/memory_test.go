@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/krau5/hyper-todo/internal/repository/repositorytest"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := NewStore()
+
+		return repositorytest.Repositories{
+			Users:        NewUsersRepository(store),
+			Tasks:        NewTasksRepository(store),
+			Dependencies: NewDependenciesRepository(store),
+			Usage:        NewUsageRepository(store),
+		}
+	})
+}
@@ -0,0 +1,90 @@
+// Package memory implements the user, task and usage repositories without a
+// database, for tests and local demos. Data is lost when the process exits.
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+// Store holds the data of the in-memory repositories. Repositories created
+// from the same store share it, e.g. deleting a task removes its dependencies.
+type Store struct {
+	mu           sync.RWMutex
+	users        map[int64]domain.User
+	tasks        map[int64]domain.Task
+	dependencies map[domain.TaskDependency]struct{}
+	apiCalls     map[usageKey]int64
+	lastUserId   int64
+	lastTaskId   int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:        make(map[int64]domain.User),
+		tasks:        make(map[int64]domain.Task),
+		dependencies: make(map[domain.TaskDependency]struct{}),
+		apiCalls:     make(map[usageKey]int64),
+	}
+}
+
+// userDependencies returns the dependencies between the tasks of a user,
+// sorted. The caller must hold the lock.
+func (s *Store) userDependencies(userId int64) []domain.TaskDependency {
+	dependencies := []domain.TaskDependency{}
+	for dependency := range s.dependencies {
+		if task, ok := s.tasks[dependency.TaskId]; ok && task.UserId == userId {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].TaskId != dependencies[j].TaskId {
+			return dependencies[i].TaskId < dependencies[j].TaskId
+		}
+		return dependencies[i].BlockedById < dependencies[j].BlockedById
+	})
+
+	return dependencies
+}
+
+// task returns a task along with its computed blocked flag. The caller must
+// hold the lock.
+func (s *Store) task(id int64) (domain.Task, bool) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return domain.Task{}, false
+	}
+
+	task.Blocked = false
+	for dependency := range s.dependencies {
+		if dependency.TaskId != id {
+			continue
+		}
+
+		if blocker, ok := s.tasks[dependency.BlockedById]; ok && !blocker.Completed {
+			task.Blocked = true
+			break
+		}
+	}
+
+	return task, true
+}
+
+// findTasks returns the tasks matching the filter ordered by id. The caller
+// must hold the lock.
+func (s *Store) findTasks(match func(domain.Task) bool) []domain.Task {
+	tasks := []domain.Task{}
+	for id := range s.tasks {
+		task, _ := s.task(id)
+		if match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks
+}
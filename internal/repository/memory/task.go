@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+type tasksRepository struct {
+	store *Store
+}
+
+func NewTasksRepository(store *Store) *tasksRepository {
+	return &tasksRepository{store: store}
+}
+
+func (r *tasksRepository) Create(ctx context.Context, name, description string, deadline time.Time, userId int64) (domain.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.lastTaskId++
+	task := domain.Task{
+		ID:          r.store.lastTaskId,
+		Name:        name,
+		Description: description,
+		Deadline:    deadline,
+		UserId:      userId,
+	}
+	r.store.tasks[task.ID] = task
+
+	return task, nil
+}
+
+// CreateChecked creates a task of the user unless check fails on the number
+// of tasks the user has, holding the lock meanwhile.
+func (r *tasksRepository) CreateChecked(ctx context.Context, name, description string, deadline time.Time, userId int64, check func(int64) error) (domain.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userId]; !ok {
+		return domain.Task{}, domain.ErrNotFound
+	}
+
+	var count int64
+	for _, task := range r.store.tasks {
+		if task.UserId == userId {
+			count++
+		}
+	}
+
+	if err := check(count); err != nil {
+		return domain.Task{}, err
+	}
+
+	r.store.lastTaskId++
+	task := domain.Task{
+		ID:          r.store.lastTaskId,
+		Name:        name,
+		Description: description,
+		Deadline:    deadline,
+		UserId:      userId,
+	}
+	r.store.tasks[task.ID] = task
+
+	return task, nil
+}
+
+func (r *tasksRepository) GetById(ctx context.Context, id int64) (domain.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	task, ok := r.store.task(id)
+	if !ok {
+		return domain.Task{}, domain.ErrNotFound
+	}
+
+	return task, nil
+}
+
+func (r *tasksRepository) GetByUser(ctx context.Context, userId int64) ([]domain.Task, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.findTasks(func(task domain.Task) bool {
+		return task.UserId == userId
+	}), nil
+}
+
+func (r *tasksRepository) UpdateById(ctx context.Context, id int64, data domain.UpdateTaskData) (domain.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.task(id)
+	if !ok {
+		return domain.Task{}, domain.ErrNotFound
+	}
+
+	if data.Name != nil && len(*data.Name) != 0 {
+		task.Name = *data.Name
+	}
+	if data.Description != nil && len(*data.Description) != 0 {
+		task.Description = *data.Description
+	}
+	if data.Deadline != nil && !(*data.Deadline).IsZero() {
+		task.Deadline = *data.Deadline
+	}
+	if data.Completed != nil && *data.Completed != task.Completed {
+		task.Completed = *data.Completed
+		if task.Completed {
+			completedAt := time.Now()
+			task.CompletedAt = &completedAt
+		} else {
+			task.CompletedAt = nil
+		}
+	}
+
+	r.store.tasks[id] = task
+
+	return task, nil
+}
+
+func (r *tasksRepository) DeleteById(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.tasks, id)
+	for dependency := range r.store.dependencies {
+		if dependency.TaskId == id || dependency.BlockedById == id {
+			delete(r.store.dependencies, dependency)
+		}
+	}
+
+	return nil
+}
+
+func (r *tasksRepository) Reassign(ctx context.Context, fromUserId, toUserId int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var count int64
+	for id, task := range r.store.tasks {
+		if task.UserId == fromUserId {
+			task.UserId = toUserId
+			r.store.tasks[id] = task
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *tasksRepository) CountByUser(ctx context.Context, userId int64) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, task := range r.store.tasks {
+		if task.UserId == userId {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *tasksRepository) CountOverdue(ctx context.Context, now time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, task := range r.store.tasks {
+		if !task.Completed && task.Deadline.Before(now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,33 @@
+package memory
+
+import "context"
+
+type usageKey struct {
+	userId int64
+	day    string
+}
+
+type usageRepository struct {
+	store *Store
+}
+
+func NewUsageRepository(store *Store) *usageRepository {
+	return &usageRepository{store: store}
+}
+
+func (r *usageRepository) IncrementAPICalls(ctx context.Context, userId int64, day string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := usageKey{userId: userId, day: day}
+	r.store.apiCalls[key]++
+
+	return r.store.apiCalls[key], nil
+}
+
+func (r *usageRepository) GetAPICalls(ctx context.Context, userId int64, day string) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.apiCalls[usageKey{userId: userId, day: day}], nil
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/utils"
+)
+
+type usersRepository struct {
+	store *Store
+}
+
+func NewUsersRepository(store *Store) *usersRepository {
+	return &usersRepository{store: store}
+}
+
+func (r *usersRepository) Create(ctx context.Context, name, email, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return domain.ErrDuplicate
+		}
+	}
+
+	r.store.lastUserId++
+	r.store.users[r.store.lastUserId] = domain.User{
+		ID:       r.store.lastUserId,
+		Name:     name,
+		Email:    email,
+		Password: hash,
+		Role:     domain.RoleUser,
+	}
+
+	return nil
+}
+
+func (r *usersRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return domain.User{}, domain.ErrNotFound
+}
+
+func (r *usersRepository) GetById(ctx context.Context, id int64) (domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+
+	return user, nil
+}
+
+func (r *usersRepository) List(ctx context.Context) ([]domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(r.store.users))
+	for _, user := range r.store.users {
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+func (r *usersRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return r.update(id, func(user *domain.User) { user.Password = hash })
+}
+
+func (r *usersRepository) SetLocked(ctx context.Context, id int64, lockedAt *time.Time) error {
+	return r.update(id, func(user *domain.User) { user.LockedAt = lockedAt })
+}
+
+func (r *usersRepository) SetRole(ctx context.Context, id int64, role domain.Role) error {
+	return r.update(id, func(user *domain.User) { user.Role = role })
+}
+
+func (r *usersRepository) RevokeSessions(ctx context.Context, id int64, revokedAt time.Time) error {
+	return r.update(id, func(user *domain.User) { user.SessionsRevokedAt = &revokedAt })
+}
+
+func (r *usersRepository) Search(ctx context.Context, query string, limit, offset int) ([]domain.User, error) {
+	users, _ := r.List(ctx)
+
+	query = strings.ToLower(query)
+	found := []domain.User{}
+	for _, user := range users {
+		if strings.Contains(strings.ToLower(user.Name), query) || strings.Contains(strings.ToLower(user.Email), query) {
+			found = append(found, user)
+		}
+	}
+
+	found = found[min(offset, len(found)):]
+	if limit >= 0 {
+		found = found[:min(limit, len(found))]
+	}
+
+	return found, nil
+}
+
+// update changes a user, failing with domain.ErrNotFound if there is no such
+// user.
+func (r *usersRepository) update(id int64, change func(*domain.User)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	change(&user)
+	r.store.users[id] = user
+
+	return nil
+}
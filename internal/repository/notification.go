@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+)
+
+type NotificationModel struct {
+	domain.Notification
+	gorm.Model
+}
+
+type notificationsRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationsRepository(db *gorm.DB) *notificationsRepository {
+	return &notificationsRepository{db: db}
+}
+
+func (r *notificationsRepository) Create(ctx context.Context, notification domain.Notification) (domain.Notification, error) {
+	notificationModel := NotificationModel{Notification: notification}
+
+	result := r.db.WithContext(ctx).Create(&notificationModel)
+	if result.Error != nil {
+		return domain.Notification{}, translateError(r.db, result.Error)
+	}
+
+	return notificationModel.Notification, nil
+}
+
+func (r *notificationsRepository) GetById(ctx context.Context, id int64) (domain.Notification, error) {
+	notification := NotificationModel{}
+
+	result := r.db.WithContext(ctx).First(&notification, id)
+	if result.Error != nil {
+		return domain.Notification{}, translateError(r.db, result.Error)
+	}
+
+	return notification.Notification, nil
+}
+
+func (r *notificationsRepository) GetByUser(ctx context.Context, userId int64, unreadOnly bool) ([]domain.Notification, error) {
+	rawNotifications := []NotificationModel{}
+
+	query := r.db.WithContext(ctx).Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("read = ?", false)
+	}
+
+	result := query.Order("id DESC").Find(&rawNotifications)
+	if result.Error != nil {
+		return []domain.Notification{}, translateError(r.db, result.Error)
+	}
+
+	notifications := make([]domain.Notification, len(rawNotifications))
+	for i, notificationModel := range rawNotifications {
+		notifications[i] = notificationModel.Notification
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationsRepository) SetRead(ctx context.Context, id int64, read bool) (domain.Notification, error) {
+	notificationModel := NotificationModel{}
+
+	result := r.db.WithContext(ctx).First(&notificationModel, id)
+	if result.Error != nil {
+		return domain.Notification{}, translateError(r.db, result.Error)
+	}
+
+	result = r.db.WithContext(ctx).Model(&notificationModel).Update("read", read)
+	if result.Error != nil {
+		return domain.Notification{}, translateError(r.db, result.Error)
+	}
+
+	notificationModel.Read = read
+
+	return notificationModel.Notification, nil
+}
+
+func (r *notificationsRepository) SetAllRead(ctx context.Context, userId int64) error {
+	result := r.db.WithContext(ctx).Model(&NotificationModel{}).
+		Where("user_id = ? AND read = ?", userId, false).
+		Update("read", true)
+
+	return translateError(r.db, result.Error)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func notificationId(n domain.Notification) int64 { return n.ID }
+
+func TestNotificationsRepository(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("creates and gets notifications", func(t *testing.T) {
+		repo := NewNotificationsRepository(newTestDB(t))
+
+		created, err := repo.Create(ctx, domain.Notification{TaskId: 3, Title: "title", Body: "body", UserId: 1})
+		assert.Nil(t, err)
+		assert.NotZero(t, created.ID)
+		assert.False(t, created.CreatedAt.IsZero())
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, int64(3), found.TaskId)
+		assert.Equal(t, "title", found.Title)
+		assert.Equal(t, "body", found.Body)
+		assert.False(t, found.Read)
+		assert.Equal(t, int64(1), found.UserId)
+	})
+
+	t.Run("gets the notifications of a user, newest first", func(t *testing.T) {
+		repo := NewNotificationsRepository(newTestDB(t))
+		first, _ := repo.Create(ctx, domain.Notification{Title: "first", Body: "body", UserId: 1})
+		second, _ := repo.Create(ctx, domain.Notification{Title: "second", Body: "body", UserId: 1})
+		repo.Create(ctx, domain.Notification{Title: "other", Body: "body", UserId: 2})
+		_, err := repo.SetRead(ctx, first.ID, true)
+		assert.Nil(t, err)
+
+		all, err := repo.GetByUser(ctx, 1, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{second.ID, first.ID}, ids(all, notificationId))
+
+		unread, err := repo.GetByUser(ctx, 1, true)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{second.ID}, ids(unread, notificationId))
+	})
+
+	t.Run("marks notifications as read", func(t *testing.T) {
+		repo := NewNotificationsRepository(newTestDB(t))
+		created, _ := repo.Create(ctx, domain.Notification{Title: "title", Body: "body", UserId: 1})
+
+		updated, err := repo.SetRead(ctx, created.ID, true)
+		assert.Nil(t, err)
+		assert.True(t, updated.Read)
+
+		updated, err = repo.SetRead(ctx, created.ID, false)
+		assert.Nil(t, err)
+		assert.False(t, updated.Read)
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Read)
+	})
+
+	t.Run("marks all notifications of a user as read", func(t *testing.T) {
+		repo := NewNotificationsRepository(newTestDB(t))
+		repo.Create(ctx, domain.Notification{Title: "first", Body: "body", UserId: 1})
+		repo.Create(ctx, domain.Notification{Title: "second", Body: "body", UserId: 1})
+		other, _ := repo.Create(ctx, domain.Notification{Title: "other", Body: "body", UserId: 2})
+
+		assert.Nil(t, repo.SetAllRead(ctx, 1))
+
+		unread, err := repo.GetByUser(ctx, 1, true)
+		assert.Nil(t, err)
+		assert.Empty(t, unread)
+
+		found, err := repo.GetById(ctx, other.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Read)
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewNotificationsRepository(newTestDB(t))
+
+		_, err := repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = repo.SetRead(ctx, 42, true)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,282 @@
+// Package pgtest provides throwaway Postgres databases to the integration
+// tests of the repositories, without network access:
+//
+//   - if TEST_POSTGRES_DSN is set, tests use that server,
+//   - otherwise initdb and postgres are looked up in TEST_POSTGRES_BIN_DIR,
+//     PATH and the usual install locations, and a temporary cluster is
+//     started for the test binary,
+//   - otherwise the tests are skipped.
+//
+// Every test gets its own schema with all migrations applied, dropped when
+// the test ends.
+package pgtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/internal/migrations"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	DSNEnv    = "TEST_POSTGRES_DSN"
+	BinDirEnv = "TEST_POSTGRES_BIN_DIR"
+)
+
+// errNotFound is the reason tests are skipped without a Postgres server.
+var errNotFound = errors.New("Postgres not found: set " + DSNEnv + " or install initdb and postgres")
+
+var (
+	once    sync.Once
+	baseDSN string
+	initErr error
+	cluster *localCluster
+	schemas atomic.Int64
+)
+
+// Main runs the tests and stops the local cluster, if one was started. Call
+// it from TestMain.
+func Main(m *testing.M) {
+	code := m.Run()
+	if cluster != nil {
+		cluster.stop()
+	}
+	os.Exit(code)
+}
+
+// New returns a connection to a new schema migrated to the latest version.
+func New(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	once.Do(func() {
+		baseDSN, initErr = start()
+	})
+	if errors.Is(initErr, errNotFound) {
+		t.Skip(initErr)
+	}
+	if initErr != nil {
+		t.Fatalf("starting Postgres: %v", initErr)
+	}
+
+	admin, err := open(baseDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), schemas.Add(1))
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := open(withSearchPath(baseDSN, schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("applying migrations: %v", err)
+	}
+
+	return db
+}
+
+func open(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+}
+
+// withSearchPath sets the schema of every connection, through a runtime
+// parameter in either DSN format.
+func withSearchPath(dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func start() (string, error) {
+	if dsn := os.Getenv(DSNEnv); dsn != "" {
+		return dsn, nil
+	}
+
+	binDir, err := findBinDir()
+	if err != nil {
+		return "", err
+	}
+
+	cluster, err = startCluster(binDir)
+	if err != nil {
+		return "", err
+	}
+
+	return cluster.dsn(), nil
+}
+
+func findBinDir() (string, error) {
+	candidates := []string{os.Getenv(BinDirEnv)}
+	if path, err := exec.LookPath("initdb"); err == nil {
+		candidates = append(candidates, filepath.Dir(path))
+	}
+	installed, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	candidates = append(candidates, installed...)
+	candidates = append(candidates, "/usr/local/pgsql/bin", "/opt/homebrew/bin", "/usr/local/bin")
+
+	for _, dir := range candidates {
+		if dir == "" {
+			continue
+		}
+
+		if isFile(filepath.Join(dir, "initdb")) && isFile(filepath.Join(dir, "postgres")) {
+			return dir, nil
+		}
+	}
+
+	return "", errNotFound
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// localCluster is a Postgres server started from a temporary data directory,
+// listening on a Unix socket only.
+type localCluster struct {
+	dir  string
+	port int
+	cmd  *exec.Cmd
+}
+
+func startCluster(binDir string) (*localCluster, error) {
+	dir, err := os.MkdirTemp("", "hyper-todo-pg")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &localCluster{dir: dir}
+	dataDir := filepath.Join(dir, "data")
+
+	initdb := exec.Command(filepath.Join(binDir, "initdb"), "-D", dataDir, "-U", "postgres", "--auth=trust", "--encoding=UTF8", "--no-sync")
+	if output, err := initdb.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb: %w\n%s", err, output)
+	}
+
+	// The port only names the socket file, as TCP is disabled.
+	c.port, err = freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, "postgres.log"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	defer logFile.Close()
+
+	c.cmd = exec.Command(filepath.Join(binDir, "postgres"),
+		"-D", dataDir,
+		"-k", dir,
+		"-p", fmt.Sprint(c.port),
+		"-c", "listen_addresses=",
+		"-c", "fsync=off",
+		"-c", "synchronous_commit=off",
+		"-c", "full_page_writes=off",
+	)
+	c.cmd.Stdout = logFile
+	c.cmd.Stderr = logFile
+	if err := c.cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	if err := c.waitReady(10 * time.Second); err != nil {
+		c.stop()
+		log, _ := os.ReadFile(filepath.Join(dir, "postgres.log"))
+		return nil, fmt.Errorf("%w\n%s", err, log)
+	}
+
+	return c, nil
+}
+
+func (c *localCluster) dsn() string {
+	return fmt.Sprintf("host=%s port=%d user=postgres dbname=postgres sslmode=disable", c.dir, c.port)
+}
+
+func (c *localCluster) waitReady(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		db, err := open(c.dsn())
+		if err == nil {
+			sqlDB, _ := db.DB()
+			err = sqlDB.Ping()
+			sqlDB.Close()
+		}
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("postgres not ready after %s: %w", timeout, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (c *localCluster) stop() {
+	if c.cmd != nil && c.cmd.Process != nil {
+		// SIGINT requests a fast shutdown, rolling back open transactions.
+		c.cmd.Process.Signal(os.Interrupt)
+		c.cmd.Wait()
+	}
+	os.RemoveAll(c.dir)
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
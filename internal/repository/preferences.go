@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferencesModel struct {
+	domain.Preferences
+	gorm.Model
+}
+
+type preferencesRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferencesRepository(db *gorm.DB) *preferencesRepository {
+	return &preferencesRepository{db: db}
+}
+
+func (r *preferencesRepository) GetByUser(ctx context.Context, userId int64) (domain.Preferences, error) {
+	preferences := PreferencesModel{}
+
+	result := r.db.WithContext(ctx).Where("user_id = ?", userId).First(&preferences)
+	if result.Error != nil {
+		return domain.Preferences{}, translateError(r.db, result.Error)
+	}
+
+	return preferences.Preferences, nil
+}
+
+func (r *preferencesRepository) Save(ctx context.Context, preferences domain.Preferences) (domain.Preferences, error) {
+	preferencesModel := PreferencesModel{Preferences: preferences}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"timezone",
+			"quiet_hours_start",
+			"quiet_hours_end",
+			"notification_channels",
+			"digest_enabled",
+			"digest_time",
+			"updated_at",
+		}),
+	}).Create(&preferencesModel)
+	if result.Error != nil {
+		return domain.Preferences{}, translateError(r.db, result.Error)
+	}
+
+	return preferencesModel.Preferences, nil
+}
+
+func (r *preferencesRepository) GetDigestSubscribers(ctx context.Context) ([]domain.Preferences, error) {
+	rawPreferences := []PreferencesModel{}
+
+	result := r.db.WithContext(ctx).Where("digest_enabled = ?", true).Find(&rawPreferences)
+	if result.Error != nil {
+		return []domain.Preferences{}, translateError(r.db, result.Error)
+	}
+
+	preferences := make([]domain.Preferences, len(rawPreferences))
+	for i, preferencesModel := range rawPreferences {
+		preferences[i] = preferencesModel.Preferences
+	}
+
+	return preferences, nil
+}
+
+// ClaimDigest records that the digest of the given local date is being sent
+// to the user. It returns false if the digest was already claimed, e.g. by
+// another replica.
+func (r *preferencesRepository) ClaimDigest(ctx context.Context, userId int64, date string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&PreferencesModel{}).
+		Where("user_id = ? AND (last_digest_on IS NULL OR last_digest_on < ?)", userId, date).
+		Update("last_digest_on", date)
+	if result.Error != nil {
+		return false, translateError(r.db, result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseDigest undoes the claim of the digest of the given local date,
+// restoring the date of the previous digest, so that a digest which failed
+// to be sent is sent on the next run.
+func (r *preferencesRepository) ReleaseDigest(ctx context.Context, userId int64, date, previous string) error {
+	result := r.db.WithContext(ctx).Model(&PreferencesModel{}).
+		Where("user_id = ? AND last_digest_on = ?", userId, date).
+		Update("last_digest_on", previous)
+
+	return translateError(r.db, result.Error)
+}
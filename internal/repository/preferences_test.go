@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesRepository(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("saves and updates preferences", func(t *testing.T) {
+		repo := NewPreferencesRepository(newTestDB(t))
+
+		preferences := domain.DefaultPreferences(1)
+		saved, err := repo.Save(ctx, preferences)
+		assert.Nil(t, err)
+		assert.Equal(t, preferences, saved)
+
+		preferences.Timezone = "Europe/Berlin"
+		preferences.QuietHoursStart = "22:00"
+		preferences.QuietHoursEnd = "07:00"
+		preferences.NotificationChannels = []domain.NotificationChannel{domain.ChannelEmail, domain.ChannelWebhook}
+		preferences.DigestEnabled = true
+		preferences.DigestTime = "09:30"
+		_, err = repo.Save(ctx, preferences)
+		assert.Nil(t, err)
+
+		found, err := repo.GetByUser(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, preferences, found)
+	})
+
+	t.Run("gets digest subscribers", func(t *testing.T) {
+		repo := NewPreferencesRepository(newTestDB(t))
+		subscriber := domain.DefaultPreferences(1)
+		subscriber.DigestEnabled = true
+		repo.Save(ctx, subscriber)
+		repo.Save(ctx, domain.DefaultPreferences(2))
+
+		subscribers, err := repo.GetDigestSubscribers(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Preferences{subscriber}, subscribers)
+	})
+
+	t.Run("claims each digest once", func(t *testing.T) {
+		repo := NewPreferencesRepository(newTestDB(t))
+		repo.Save(ctx, domain.DefaultPreferences(1))
+
+		claimed, err := repo.ClaimDigest(ctx, 1, "2025-03-10")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = repo.ClaimDigest(ctx, 1, "2025-03-10")
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		claimed, err = repo.ClaimDigest(ctx, 1, "2025-03-11")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		found, err := repo.GetByUser(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "2025-03-11", found.LastDigestOn)
+	})
+
+	t.Run("releases claimed digests", func(t *testing.T) {
+		repo := NewPreferencesRepository(newTestDB(t))
+		repo.Save(ctx, domain.DefaultPreferences(1))
+		repo.ClaimDigest(ctx, 1, "2025-03-10")
+
+		claimed, err := repo.ClaimDigest(ctx, 1, "2025-03-11")
+		require.Nil(t, err)
+		require.True(t, claimed)
+
+		assert.Nil(t, repo.ReleaseDigest(ctx, 1, "2025-03-11", "2025-03-10"))
+
+		found, err := repo.GetByUser(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "2025-03-10", found.LastDigestOn)
+
+		claimed, err = repo.ClaimDigest(ctx, 1, "2025-03-11")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewPreferencesRepository(newTestDB(t))
+
+		_, err := repo.GetByUser(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// softDeleted lists the models deleted by setting their deleted_at column.
+var softDeleted = []interface{}{
+	&UserModel{},
+	&TaskModel{},
+	&WebhookModel{},
+	&WebhookDeliveryModel{},
+	&NotificationModel{},
+	&ReminderModel{},
+	&PreferencesModel{},
+}
+
+// orphans lists the rows left without their user, task or webhook once the
+// soft-deleted rows are removed, in the order they must be purged. The data
+// of purged users goes with them, whether it was deleted or not.
+var orphans = []struct {
+	model interface{}
+	query string
+}{
+	{&TaskModel{}, "NOT EXISTS (SELECT 1 FROM user_models WHERE user_models.id = task_models.user_id)"},
+	{&TaskDependencyModel{}, "NOT EXISTS (SELECT 1 FROM task_models WHERE task_models.id = task_dependency_models.task_id) OR " +
+		"NOT EXISTS (SELECT 1 FROM task_models WHERE task_models.id = task_dependency_models.blocked_by_id)"},
+	{&ReminderModel{}, "NOT EXISTS (SELECT 1 FROM task_models WHERE task_models.id = reminder_models.task_id)"},
+	{&WebhookModel{}, "NOT EXISTS (SELECT 1 FROM user_models WHERE user_models.id = webhook_models.user_id)"},
+	{&WebhookDeliveryModel{}, "NOT EXISTS (SELECT 1 FROM webhook_models WHERE webhook_models.id = webhook_delivery_models.webhook_id)"},
+	{&NotificationModel{}, "NOT EXISTS (SELECT 1 FROM user_models WHERE user_models.id = notification_models.user_id)"},
+	{&PreferencesModel{}, "NOT EXISTS (SELECT 1 FROM user_models WHERE user_models.id = preferences_models.user_id)"},
+	{&APIUsageModel{}, "NOT EXISTS (SELECT 1 FROM user_models WHERE user_models.id = api_usage_models.user_id)"},
+}
+
+// Purged is the number of rows removed from a table by PurgeDeleted.
+type Purged struct {
+	Table string
+	Rows  int64
+}
+
+// PurgeDeleted permanently removes the rows soft-deleted before the time,
+// along with the rows left without their user, task or webhook, see orphans.
+// It returns the tables rows were removed from.
+func PurgeDeleted(ctx context.Context, db *gorm.DB, before time.Time) ([]Purged, error) {
+	condition := "deleted_at IS NOT NULL AND deleted_at < ?"
+	if db.Dialector.Name() == "sqlite" {
+		// SQLite stores times as text, which may carry different offsets.
+		condition = "deleted_at IS NOT NULL AND julianday(deleted_at) < julianday(?)"
+	}
+
+	var purged []Purged
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		purge := func(model interface{}, query string, args ...interface{}) error {
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(model); err != nil {
+				return err
+			}
+
+			result := tx.Unscoped().Where(query, args...).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+
+			for i := range purged {
+				if purged[i].Table == stmt.Schema.Table {
+					purged[i].Rows += result.RowsAffected
+					return nil
+				}
+			}
+			purged = append(purged, Purged{Table: stmt.Schema.Table, Rows: result.RowsAffected})
+
+			return nil
+		}
+
+		for _, model := range softDeleted {
+			if err := purge(model, condition, before); err != nil {
+				return err
+			}
+		}
+
+		for _, orphan := range orphans {
+			if err := purge(orphan.model, orphan.query); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(db, err)
+	}
+
+	return purged, nil
+}
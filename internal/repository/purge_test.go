@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPurgeDeleted(t *testing.T) {
+	t.Run("Postgres", func(t *testing.T) { testPurgeDeleted(t, newTestDB(t)) })
+	t.Run("SQLite", func(t *testing.T) {
+		db, err := OpenSQLite(":memory:", &gorm.Config{Logger: logger.Discard})
+		require.Nil(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		})
+
+		testPurgeDeleted(t, db)
+	})
+}
+
+func testPurgeDeleted(t *testing.T, db *gorm.DB) {
+	ctx := context.TODO()
+	u := createUser(t, db, "user@example.com")
+	kept := createTask(t, db, "kept", deadline, u.ID)
+	deleted := createTask(t, db, "deleted", deadline, u.ID)
+
+	reminders := NewRemindersRepository(db)
+	_, err := reminders.ReplaceForTask(ctx, kept.ID, []time.Duration{time.Hour})
+	require.Nil(t, err)
+	_, err = reminders.ReplaceForTask(ctx, deleted.ID, []time.Duration{time.Hour, 2 * time.Hour})
+	require.Nil(t, err)
+
+	webhook, err := NewWebhooksRepository(db).Create(ctx, "https://example.com/hook", "secret", []domain.EventType{domain.EventTaskCreated}, u.ID)
+	require.Nil(t, err)
+	_, err = NewDeliveriesRepository(db).Create(ctx, webhook.ID, domain.EventTaskCreated, []byte("{}"))
+	require.Nil(t, err)
+
+	tasks := NewTasksRepository(db)
+	require.Nil(t, tasks.DeleteById(ctx, deleted.ID))
+	require.Nil(t, NewWebhooksRepository(db).DeleteById(ctx, webhook.ID))
+
+	// The data of a deleted user is purged with them, deleted or not.
+	gone := createUser(t, db, "gone@example.com")
+	goneBlocked := createTask(t, db, "blocked", deadline, gone.ID)
+	goneBlocker := createTask(t, db, "blocker", deadline, gone.ID)
+	require.Nil(t, NewDependenciesRepository(db).Create(ctx, goneBlocked.ID, goneBlocker.ID))
+	_, err = reminders.ReplaceForTask(ctx, goneBlocked.ID, []time.Duration{time.Hour})
+	require.Nil(t, err)
+	goneWebhook, err := NewWebhooksRepository(db).Create(ctx, "https://example.com/hook", "secret", []domain.EventType{domain.EventTaskCreated}, gone.ID)
+	require.Nil(t, err)
+	_, err = NewDeliveriesRepository(db).Create(ctx, goneWebhook.ID, domain.EventTaskCreated, []byte("{}"))
+	require.Nil(t, err)
+	_, err = NewNotificationsRepository(db).Create(ctx, domain.Notification{Title: "hello", UserId: gone.ID})
+	require.Nil(t, err)
+	_, err = NewPreferencesRepository(db).Save(ctx, domain.DefaultPreferences(gone.ID))
+	require.Nil(t, err)
+	_, err = NewUsageRepository(db).IncrementAPICalls(ctx, gone.ID, "2025-01-01")
+	require.Nil(t, err)
+	_, err = NewPreferencesRepository(db).Save(ctx, domain.DefaultPreferences(u.ID))
+	require.Nil(t, err)
+	require.Nil(t, db.Delete(&UserModel{}, gone.ID).Error)
+
+	purged, err := PurgeDeleted(ctx, db, time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Empty(t, purged, "rows deleted after the time are kept")
+
+	purged, err = PurgeDeleted(ctx, db, time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Purged{
+		{Table: "user_models", Rows: 1},
+		{Table: "task_models", Rows: 3},
+		{Table: "task_dependency_models", Rows: 1},
+		{Table: "webhook_models", Rows: 2},
+		{Table: "reminder_models", Rows: 3},
+		{Table: "webhook_delivery_models", Rows: 2},
+		{Table: "notification_models", Rows: 1},
+		{Table: "preferences_models", Rows: 1},
+		{Table: "api_usage_models", Rows: 1},
+	}, purged)
+
+	for model, expected := range map[interface{}]int64{
+		&UserModel{}:            1,
+		&TaskModel{}:            1,
+		&TaskDependencyModel{}:  0,
+		&WebhookModel{}:         0,
+		&NotificationModel{}:    0,
+		&PreferencesModel{}:     1,
+		&APIUsageModel{}:        0,
+		&WebhookDeliveryModel{}: 0,
+	} {
+		var count int64
+		require.Nil(t, db.Unscoped().Model(model).Count(&count).Error)
+		assert.Equal(t, expected, count, "%T", model)
+	}
+
+	remaining, err := reminders.GetByTask(ctx, kept.ID)
+	assert.Nil(t, err)
+	assert.Len(t, remaining, 1)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitModel is the bucket of a rate limit key, full once TAT (the
+// theoretical arrival time of the next request) is in the past.
+type RateLimitModel struct {
+	Key string    `gorm:"primaryKey"`
+	TAT time.Time `gorm:"column:tat;not null;index"`
+}
+
+// rateLimitPruneInterval is how often each replica deletes full buckets.
+const rateLimitPruneInterval = time.Minute
+
+type rateLimitStore struct {
+	db *gorm.DB
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// NewRateLimitStore returns a rate limit store sharing limits between every
+// replica using the database.
+func NewRateLimitStore(db *gorm.DB) *rateLimitStore {
+	return &rateLimitStore{db: db}
+}
+
+func (s *rateLimitStore) Take(ctx context.Context, key string, rate ratelimit.Rate, now time.Time) (ratelimit.Result, error) {
+	// Times are compared as text by SQLite.
+	now = now.UTC()
+	if err := s.pruneIfDue(ctx, now); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	var result ratelimit.Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The row must exist to be locked by concurrent requests.
+		bucket := RateLimitModel{Key: key}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bucket).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&bucket).Error; err != nil {
+			return err
+		}
+
+		var tat time.Time
+		tat, result = ratelimit.Take(bucket.TAT, now, rate)
+		if !result.Allowed {
+			return nil
+		}
+
+		return tx.Model(&RateLimitModel{}).Where("key = ?", key).Update("tat", tat.UTC()).Error
+	})
+	if err != nil {
+		return ratelimit.Result{}, translateError(s.db, err)
+	}
+
+	return result, nil
+}
+
+// pruneIfDue deletes the buckets which are full at now, they are equivalent
+// to missing ones.
+func (s *rateLimitStore) pruneIfDue(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	due := now.Sub(s.lastPruned) >= rateLimitPruneInterval
+	if due {
+		s.lastPruned = now
+	}
+	s.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+
+	result := s.db.WithContext(ctx).Where("tat <= ?", now).Delete(&RateLimitModel{})
+	return translateError(s.db, result.Error)
+}
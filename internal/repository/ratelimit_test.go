@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/krau5/hyper-todo/internal/ratelimit/ratelimittest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	ratelimittest.Run(t, func(t *testing.T) ratelimit.Store {
+		return NewRateLimitStore(newTestDB(t))
+	})
+}
+
+func TestSQLiteRateLimitStore(t *testing.T) {
+	ratelimittest.Run(t, func(t *testing.T) ratelimit.Store {
+		db, err := OpenSQLite(":memory:", &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		})
+
+		return NewRateLimitStore(db)
+	})
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderModel struct {
+	domain.Reminder
+	gorm.Model
+}
+
+type remindersRepository struct {
+	db *gorm.DB
+}
+
+func NewRemindersRepository(db *gorm.DB) *remindersRepository {
+	return &remindersRepository{db: db}
+}
+
+func (r *remindersRepository) GetByTask(ctx context.Context, taskId int64) ([]domain.Reminder, error) {
+	rawReminders := []ReminderModel{}
+
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskId).Order("remind_before DESC").Find(&rawReminders)
+	if result.Error != nil {
+		return []domain.Reminder{}, translateError(r.db, result.Error)
+	}
+
+	return toReminders(rawReminders), nil
+}
+
+func (r *remindersRepository) ReplaceForTask(ctx context.Context, taskId int64, offsets []time.Duration) ([]domain.Reminder, error) {
+	rawReminders := make([]ReminderModel, len(offsets))
+	for i, offset := range offsets {
+		rawReminders[i] = ReminderModel{Reminder: domain.Reminder{TaskId: taskId, RemindBefore: offset}}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("task_id = ?", taskId).Delete(&ReminderModel{})
+		if result.Error != nil || len(rawReminders) == 0 {
+			return result.Error
+		}
+
+		return tx.Create(&rawReminders).Error
+	})
+	if err != nil {
+		return []domain.Reminder{}, translateError(r.db, err)
+	}
+
+	return toReminders(rawReminders), nil
+}
+
+// lateReminderWindow is how long past the deadline of a task its postponed
+// or retried reminders are still sent.
+const lateReminderWindow = 24 * time.Hour
+
+// ClaimDue locks a batch of reminders of open tasks whose time has come,
+// skipping rows locked by other schedulers, and hides them for the lease so
+// they are not picked up again while being sent. Reminders are sent outside
+// of the transaction, then marked sent or postponed.
+func (r *remindersRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.DueReminder, error) {
+	due := []domain.DueReminder{}
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rawReminders := []ReminderModel{}
+		result := tx.
+			Joins("JOIN task_models ON task_models.id = reminder_models.task_id AND task_models.deleted_at IS NULL").
+			Where("task_models.completed = ?", false).
+			Where(dueConditions(tx), now, now, now.Add(-lateReminderWindow), now).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reminder_models"}, Options: "SKIP LOCKED"}).
+			Order("task_models.deadline").
+			Limit(limit).
+			Find(&rawReminders)
+		if result.Error != nil || len(rawReminders) == 0 {
+			return result.Error
+		}
+
+		ids := make([]int64, len(rawReminders))
+		taskIds := make([]int64, len(rawReminders))
+		for i, reminderModel := range rawReminders {
+			ids[i] = reminderModel.Reminder.ID
+			taskIds[i] = reminderModel.TaskId
+		}
+
+		rawTasks := []TaskModel{}
+		if err := tx.Where("id IN ?", taskIds).Find(&rawTasks).Error; err != nil {
+			return err
+		}
+
+		tasks := make(map[int64]domain.Task, len(rawTasks))
+		for _, taskModel := range rawTasks {
+			tasks[taskModel.Task.ID] = taskModel.Task
+		}
+
+		for _, reminderModel := range rawReminders {
+			if task, ok := tasks[reminderModel.TaskId]; ok {
+				due = append(due, domain.DueReminder{Reminder: reminderModel.Reminder, Task: task})
+			}
+		}
+
+		return tx.Model(&ReminderModel{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return []domain.DueReminder{}, translateError(r.db, err)
+	}
+
+	return due, nil
+}
+
+// MarkSent records that a reminder was sent for the deadline, so it fires
+// again only if the deadline is moved.
+func (r *remindersRepository) MarkSent(ctx context.Context, id int64, deadline time.Time) error {
+	result := r.db.WithContext(ctx).Model(&ReminderModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sent_for": deadline, "sent_at": time.Now(), "next_attempt_at": nil})
+
+	return translateError(r.db, result.Error)
+}
+
+// Postpone hides a reminder until the time.
+func (r *remindersRepository) Postpone(ctx context.Context, id int64, until time.Time) error {
+	result := r.db.WithContext(ctx).Model(&ReminderModel{}).
+		Where("id = ?", id).
+		Update("next_attempt_at", until)
+
+	return translateError(r.db, result.Error)
+}
+
+// dueConditions selects reminders whose time has come, which were not sent
+// for the current deadline and are not hidden. Tasks must be due in the
+// future, or recently past due for reminders which were postponed or whose
+// sending failed. Its arguments are now, now, the start of the late window
+// and now. RemindBefore is stored in nanoseconds. SQLite has no interval
+// type and stores times as text, so it compares Julian days.
+func dueConditions(db *gorm.DB) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "julianday(task_models.deadline) - reminder_models.remind_before / 86400000000000.0 <= julianday(?) " +
+			"AND (reminder_models.sent_for IS NULL OR julianday(reminder_models.sent_for) <> julianday(task_models.deadline)) " +
+			"AND (julianday(task_models.deadline) > julianday(?) OR (reminder_models.next_attempt_at IS NOT NULL AND julianday(task_models.deadline) > julianday(?))) " +
+			"AND (reminder_models.next_attempt_at IS NULL OR julianday(reminder_models.next_attempt_at) <= julianday(?))"
+	}
+
+	return "task_models.deadline - reminder_models.remind_before / 1000 * interval '1 microsecond' <= ? " +
+		"AND (reminder_models.sent_for IS NULL OR reminder_models.sent_for <> task_models.deadline) " +
+		"AND (task_models.deadline > ? OR (reminder_models.next_attempt_at IS NOT NULL AND task_models.deadline > ?)) " +
+		"AND (reminder_models.next_attempt_at IS NULL OR reminder_models.next_attempt_at <= ?)"
+}
+
+func toReminders(rawReminders []ReminderModel) []domain.Reminder {
+	reminders := make([]domain.Reminder, len(rawReminders))
+	for i, reminderModel := range rawReminders {
+		reminders[i] = reminderModel.Reminder
+	}
+
+	return reminders
+}
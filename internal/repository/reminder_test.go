@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/preference"
+	"github.com/krau5/hyper-todo/reminder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func remindBefore(r domain.Reminder) int64 { return int64(r.RemindBefore) }
+
+func TestRemindersRepository(t *testing.T) {
+	t.Run("Postgres", func(t *testing.T) { testRemindersRepository(t, newTestDB) })
+	t.Run("SQLite", func(t *testing.T) { testRemindersRepository(t, newSQLiteTestDB) })
+}
+
+func testRemindersRepository(t *testing.T, newDB func(*testing.T) *gorm.DB) {
+	ctx := context.TODO()
+
+	t.Run("replaces the reminders of a task", func(t *testing.T) {
+		repo := NewRemindersRepository(newDB(t))
+
+		created, err := repo.ReplaceForTask(ctx, 1, []time.Duration{time.Hour, 24 * time.Hour})
+		assert.Nil(t, err)
+		assert.Len(t, created, 2)
+		assert.NotZero(t, created[0].ID)
+
+		_, err = repo.ReplaceForTask(ctx, 1, []time.Duration{10 * time.Minute, 2 * time.Hour})
+		assert.Nil(t, err)
+		repo.ReplaceForTask(ctx, 2, []time.Duration{time.Hour})
+
+		reminders, err := repo.GetByTask(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{int64(2 * time.Hour), int64(10 * time.Minute)}, ids(reminders, remindBefore))
+
+		_, err = repo.ReplaceForTask(ctx, 1, nil)
+		assert.Nil(t, err)
+
+		reminders, err = repo.GetByTask(ctx, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, reminders)
+	})
+
+	t.Run("maps unique violations to ErrDuplicate", func(t *testing.T) {
+		repo := NewRemindersRepository(newDB(t))
+
+		_, err := repo.ReplaceForTask(ctx, 1, []time.Duration{time.Hour, time.Hour})
+		assert.ErrorIs(t, err, domain.ErrDuplicate)
+	})
+
+	t.Run("claims due reminders of open tasks once per deadline", func(t *testing.T) {
+		db := newDB(t)
+		u := createUser(t, db, "user@example.com")
+		now := time.Now()
+		due := createTask(t, db, "due", now.Add(30*time.Minute), u.ID)
+		later := createTask(t, db, "later", now.Add(3*time.Hour), u.ID)
+		overdue := createTask(t, db, "overdue", now.Add(-time.Minute), u.ID)
+		completed := createTask(t, db, "completed", now.Add(30*time.Minute), u.ID)
+		deleted := createTask(t, db, "deleted", now.Add(30*time.Minute), u.ID)
+
+		tasks := NewTasksRepository(db)
+		done := true
+		tasks.UpdateById(ctx, completed.ID, domain.UpdateTaskData{Completed: &done})
+		tasks.DeleteById(ctx, deleted.ID)
+
+		repo := NewRemindersRepository(db)
+		for _, task := range []domain.Task{due, later, overdue, completed, deleted} {
+			repo.ReplaceForTask(ctx, task.ID, []time.Duration{time.Hour})
+		}
+
+		claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, due.ID, claimed[0].Task.ID)
+		assert.Equal(t, due.ID, claimed[0].Reminder.TaskId)
+
+		// Claimed reminders are hidden for the lease.
+		again, err := repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Empty(t, again)
+
+		require.Nil(t, repo.MarkSent(ctx, claimed[0].Reminder.ID, due.Deadline))
+		reminders, _ := repo.GetByTask(ctx, due.ID)
+		assert.NotNil(t, reminders[0].SentAt)
+		assert.True(t, due.Deadline.Equal(*reminders[0].SentFor))
+		assert.Nil(t, reminders[0].NextAttemptAt)
+
+		again, err = repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Empty(t, again)
+
+		// Moving the deadline makes the reminder due again.
+		newDeadline := now.Add(45 * time.Minute)
+		tasks.UpdateById(ctx, due.ID, domain.UpdateTaskData{Deadline: &newDeadline})
+
+		again, err = repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Len(t, again, 1)
+	})
+
+	t.Run("claims reminders again once their lease expired", func(t *testing.T) {
+		db := newDB(t)
+		u := createUser(t, db, "user@example.com")
+		due := createTask(t, db, "due", time.Now().Add(30*time.Minute), u.ID)
+		repo := NewRemindersRepository(db)
+		repo.ReplaceForTask(ctx, due.ID, []time.Duration{time.Hour})
+
+		claimed, err := repo.ClaimDue(ctx, 10, -time.Second)
+		assert.Nil(t, err)
+		assert.Len(t, claimed, 1)
+
+		claimed, err = repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Len(t, claimed, 1)
+	})
+
+	t.Run("postponed reminders don't hold up the others", func(t *testing.T) {
+		db := newDB(t)
+		quiet := createUser(t, db, "quiet@example.com")
+		other := createUser(t, db, "other@example.com")
+		now := time.Now()
+		first := createTask(t, db, "first", now.Add(10*time.Minute), quiet.ID)
+		second := createTask(t, db, "second", now.Add(20*time.Minute), other.ID)
+		repo := NewRemindersRepository(db)
+		repo.ReplaceForTask(ctx, first.ID, []time.Duration{time.Hour})
+		repo.ReplaceForTask(ctx, second.ID, []time.Duration{time.Hour})
+
+		claimed, err := repo.ClaimDue(ctx, 1, time.Minute)
+		assert.Nil(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, first.ID, claimed[0].Task.ID)
+		require.Nil(t, repo.Postpone(ctx, claimed[0].Reminder.ID, now.Add(time.Hour)))
+
+		claimed, err = repo.ClaimDue(ctx, 1, time.Minute)
+		assert.Nil(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, second.ID, claimed[0].Task.ID)
+	})
+
+	t.Run("sends postponed reminders past the deadline", func(t *testing.T) {
+		db := newDB(t)
+		u := createUser(t, db, "user@example.com")
+		now := time.Now()
+		due := createTask(t, db, "due", now.Add(time.Second), u.ID)
+		repo := NewRemindersRepository(db)
+		repo.ReplaceForTask(ctx, due.ID, []time.Duration{time.Hour})
+
+		claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		require.Len(t, claimed, 1)
+		require.Nil(t, repo.Postpone(ctx, claimed[0].Reminder.ID, now))
+
+		// The task is past due once the quiet hours are over.
+		pastDue := now.Add(-time.Hour)
+		NewTasksRepository(db).UpdateById(ctx, due.ID, domain.UpdateTaskData{Deadline: &pastDue})
+
+		claimed, err = repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Len(t, claimed, 1)
+	})
+
+	t.Run("lets the scheduler query the database while sending", func(t *testing.T) {
+		db := newDB(t)
+		u := createUser(t, db, "user@example.com")
+		due := createTask(t, db, "due", time.Now().Add(30*time.Minute), u.ID)
+		repo := NewRemindersRepository(db)
+		repo.ReplaceForTask(ctx, due.ID, []time.Duration{time.Hour})
+
+		notifications := NewNotificationsRepository(db)
+		scheduler := reminder.NewScheduler(repo, NewUserRepository(db), preference.NewService(NewPreferencesRepository(db)), notifierFunc(func(ctx context.Context, _ domain.User, _ domain.Preferences, notification domain.Notification) (int, error) {
+			_, err := notifications.Create(ctx, notification)
+			return 1, err
+		}), reminder.DefaultSchedulerConfig())
+
+		// Were the only SQLite connection held while sending, the notifier
+		// would wait for it until the timeout.
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		sent, err := scheduler.RunOnce(timeoutCtx)
+		require.Nil(t, err)
+		assert.Equal(t, 1, sent)
+
+		created, err := notifications.GetByUser(ctx, u.ID, false)
+		assert.Nil(t, err)
+		assert.Len(t, created, 1)
+
+		reminders, _ := repo.GetByTask(ctx, due.ID)
+		assert.NotNil(t, reminders[0].SentAt)
+	})
+}
+
+type notifierFunc func(context.Context, domain.User, domain.Preferences, domain.Notification) (int, error)
+
+func (f notifierFunc) Notify(ctx context.Context, u domain.User, preferences domain.Preferences, notification domain.Notification) (int, error) {
+	return f(ctx, u, preferences, notification)
+}
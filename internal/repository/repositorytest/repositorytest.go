@@ -0,0 +1,630 @@
+// Package repositorytest holds the conformance suite every storage backend
+// of the user, task and usage repositories must pass.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/krau5/hyper-todo/quota"
+	"github.com/krau5/hyper-todo/task"
+	"github.com/krau5/hyper-todo/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the repositories of a backend, sharing one database.
+type Repositories struct {
+	Users        user.UsersRepository
+	Tasks        task.TasksRepository
+	Dependencies task.DependenciesRepository
+	Usage        quota.UsageRepository
+}
+
+// Run runs the suite. setup is called for every test and must return
+// repositories backed by an empty database.
+func Run(t *testing.T, setup func(t *testing.T) Repositories) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, setup) })
+	t.Run("Tasks", func(t *testing.T) { testTasks(t, setup) })
+	t.Run("Dependencies", func(t *testing.T) { testDependencies(t, setup) })
+	t.Run("Usage", func(t *testing.T) { testUsage(t, setup) })
+}
+
+// deadline is rounded to the second since backends store times with
+// different precision.
+var deadline = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+func createUser(t *testing.T, repos Repositories, email string) domain.User {
+	t.Helper()
+
+	ctx := context.TODO()
+	require.Nil(t, repos.Users.Create(ctx, "user", email, "password"))
+
+	u, err := repos.Users.GetByEmail(ctx, email)
+	require.Nil(t, err)
+
+	return u
+}
+
+func createTask(t *testing.T, repos Repositories, name string, userId int64) domain.Task {
+	t.Helper()
+
+	created, err := repos.Tasks.Create(context.TODO(), name, "description", deadline, userId)
+	require.Nil(t, err)
+
+	return created
+}
+
+func assertTask(t *testing.T, expected, actual domain.Task) {
+	t.Helper()
+
+	assert.True(t, expected.Deadline.Equal(actual.Deadline), "deadline %s, got %s", expected.Deadline, actual.Deadline)
+	assert.Equal(t, expected.CompletedAt == nil, actual.CompletedAt == nil)
+
+	expected.Deadline, actual.Deadline = time.Time{}, time.Time{}
+	expected.CompletedAt, actual.CompletedAt = nil, nil
+	assert.Equal(t, expected, actual)
+}
+
+func taskIds(tasks []domain.Task) []int64 {
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	return ids
+}
+
+func testUsers(t *testing.T, setup func(t *testing.T) Repositories) {
+	ctx := context.TODO()
+
+	t.Run("creates and finds users", func(t *testing.T) {
+		repos := setup(t)
+
+		err := repos.Users.Create(ctx, "user", "user@example.com", "password")
+		assert.Nil(t, err)
+
+		byEmail, err := repos.Users.GetByEmail(ctx, "user@example.com")
+		assert.Nil(t, err)
+		assert.NotZero(t, byEmail.ID)
+		assert.Equal(t, "user", byEmail.Name)
+		assert.Equal(t, "user@example.com", byEmail.Email)
+		assert.True(t, utils.VerifyPassword("password", byEmail.Password))
+
+		byId, err := repos.Users.GetById(ctx, byEmail.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, byEmail, byId)
+	})
+
+	t.Run("rejects duplicated emails", func(t *testing.T) {
+		repos := setup(t)
+		createUser(t, repos, "user@example.com")
+
+		err := repos.Users.Create(ctx, "other", "user@example.com", "password")
+		assert.ErrorIs(t, err, domain.ErrDuplicate)
+	})
+
+	t.Run("returns ErrNotFound for missing users", func(t *testing.T) {
+		repos := setup(t)
+
+		_, err := repos.Users.GetByEmail(ctx, "missing@example.com")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = repos.Users.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		err = repos.Users.UpdatePassword(ctx, 42, "password")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		err = repos.Users.SetLocked(ctx, 42, nil)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		err = repos.Users.SetRole(ctx, 42, domain.RoleAdmin)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		err = repos.Users.RevokeSessions(ctx, 42, time.Now())
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("lists users by id", func(t *testing.T) {
+		repos := setup(t)
+
+		users, err := repos.Users.List(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, users)
+
+		first := createUser(t, repos, "first@example.com")
+		second := createUser(t, repos, "second@example.com")
+
+		users, err = repos.Users.List(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.User{first, second}, users)
+	})
+
+	t.Run("updates passwords", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+
+		err := repos.Users.UpdatePassword(ctx, u.ID, "new password")
+		assert.Nil(t, err)
+
+		found, err := repos.Users.GetById(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.True(t, utils.VerifyPassword("new password", found.Password))
+		assert.False(t, utils.VerifyPassword("password", found.Password))
+	})
+
+	t.Run("creates users with the user role", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		assert.Equal(t, domain.RoleUser, u.Role)
+
+		err := repos.Users.SetRole(ctx, u.ID, domain.RoleSupport)
+		assert.Nil(t, err)
+
+		found, err := repos.Users.GetById(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.RoleSupport, found.Role)
+	})
+
+	t.Run("searches users by name or email", func(t *testing.T) {
+		repos := setup(t)
+		require.Nil(t, repos.Users.Create(ctx, "John Doe", "john@example.com", "password"))
+		require.Nil(t, repos.Users.Create(ctx, "Jane Doe", "jane@example.org", "password"))
+		require.Nil(t, repos.Users.Create(ctx, "Bob_Smith", "bob@example.com", "password"))
+
+		emails := func(users []domain.User) []string {
+			result := make([]string, len(users))
+			for i, u := range users {
+				result[i] = u.Email
+			}
+			return result
+		}
+
+		users, err := repos.Users.Search(ctx, "DOE", 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"john@example.com", "jane@example.org"}, emails(users))
+
+		users, err = repos.Users.Search(ctx, "example.com", 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"john@example.com", "bob@example.com"}, emails(users))
+
+		users, err = repos.Users.Search(ctx, "_", 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"bob@example.com"}, emails(users), "wildcards are matched literally")
+
+		users, err = repos.Users.Search(ctx, "", 1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"jane@example.org"}, emails(users))
+	})
+
+	t.Run("revokes sessions", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		assert.Nil(t, u.SessionsRevokedAt)
+
+		revokedAt := time.Now()
+		err := repos.Users.RevokeSessions(ctx, u.ID, revokedAt)
+		assert.Nil(t, err)
+
+		found, err := repos.Users.GetById(ctx, u.ID)
+		assert.Nil(t, err)
+		if assert.NotNil(t, found.SessionsRevokedAt) {
+			assert.WithinDuration(t, revokedAt, *found.SessionsRevokedAt, time.Millisecond)
+		}
+	})
+
+	t.Run("locks and unlocks users", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		assert.False(t, u.Locked())
+
+		lockedAt := time.Now()
+		err := repos.Users.SetLocked(ctx, u.ID, &lockedAt)
+		assert.Nil(t, err)
+
+		found, err := repos.Users.GetById(ctx, u.ID)
+		assert.Nil(t, err)
+		if assert.True(t, found.Locked()) {
+			assert.WithinDuration(t, lockedAt, *found.LockedAt, time.Second)
+		}
+
+		err = repos.Users.SetLocked(ctx, u.ID, nil)
+		assert.Nil(t, err)
+
+		found, err = repos.Users.GetById(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Locked())
+	})
+}
+
+func testTasks(t *testing.T, setup func(t *testing.T) Repositories) {
+	ctx := context.TODO()
+
+	t.Run("creates and finds tasks", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+
+		created, err := repos.Tasks.Create(ctx, "task", "description", deadline, u.ID)
+		assert.Nil(t, err)
+		assert.NotZero(t, created.ID)
+		assertTask(t, domain.Task{
+			ID:          created.ID,
+			Name:        "task",
+			Description: "description",
+			Deadline:    deadline,
+			UserId:      u.ID,
+		}, created)
+
+		found, err := repos.Tasks.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assertTask(t, created, found)
+	})
+
+	t.Run("finds tasks by user", func(t *testing.T) {
+		repos := setup(t)
+		first := createUser(t, repos, "first@example.com")
+		second := createUser(t, repos, "second@example.com")
+
+		a := createTask(t, repos, "a", first.ID)
+		createTask(t, repos, "b", second.ID)
+		c := createTask(t, repos, "c", first.ID)
+
+		tasks, err := repos.Tasks.GetByUser(ctx, first.ID)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []int64{a.ID, c.ID}, taskIds(tasks))
+
+		tasks, err = repos.Tasks.GetByUser(ctx, 42)
+		assert.Nil(t, err)
+		assert.Empty(t, tasks)
+	})
+
+	t.Run("updates the given fields", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		created := createTask(t, repos, "task", u.ID)
+
+		name := "renamed"
+		newDeadline := deadline.Add(time.Hour)
+		completed := true
+		updated, err := repos.Tasks.UpdateById(ctx, created.ID, domain.UpdateTaskData{
+			Name:      &name,
+			Deadline:  &newDeadline,
+			Completed: &completed,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "renamed", updated.Name)
+		assert.Equal(t, "description", updated.Description)
+		assert.True(t, newDeadline.Equal(updated.Deadline))
+		assert.True(t, updated.Completed)
+		assert.NotNil(t, updated.CompletedAt)
+
+		found, err := repos.Tasks.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assertTask(t, updated, found)
+
+		completed = false
+		updated, err = repos.Tasks.UpdateById(ctx, created.ID, domain.UpdateTaskData{Completed: &completed})
+		assert.Nil(t, err)
+		assert.False(t, updated.Completed)
+		assert.Nil(t, updated.CompletedAt)
+	})
+
+	t.Run("deletes tasks", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		created := createTask(t, repos, "task", u.ID)
+
+		err := repos.Tasks.DeleteById(ctx, created.ID)
+		assert.Nil(t, err)
+
+		_, err = repos.Tasks.GetById(ctx, created.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		tasks, err := repos.Tasks.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, tasks)
+	})
+
+	t.Run("counts overdue tasks", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+
+		createTask(t, repos, "due", u.ID)
+		overdue := createTask(t, repos, "overdue", u.ID)
+		completed := createTask(t, repos, "completed", u.ID)
+
+		past := deadline.Add(-48 * time.Hour)
+		done := true
+		_, err := repos.Tasks.UpdateById(ctx, overdue.ID, domain.UpdateTaskData{Deadline: &past})
+		require.Nil(t, err)
+		_, err = repos.Tasks.UpdateById(ctx, completed.ID, domain.UpdateTaskData{Deadline: &past, Completed: &done})
+		require.Nil(t, err)
+
+		count, err := repos.Tasks.CountOverdue(ctx, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("counts the tasks of a user", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		other := createUser(t, repos, "other@example.com")
+
+		createTask(t, repos, "first", u.ID)
+		second := createTask(t, repos, "second", u.ID)
+		createTask(t, repos, "other", other.ID)
+		require.Nil(t, repos.Tasks.DeleteById(ctx, second.ID))
+
+		count, err := repos.Tasks.CountByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("creates checked tasks unless the check fails", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		other := createUser(t, repos, "other@example.com")
+		createTask(t, repos, "first", u.ID)
+		createTask(t, repos, "other", other.ID)
+		deadline := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		errRefused := errors.New("refused")
+
+		_, err := repos.Tasks.CreateChecked(ctx, "second", "description", deadline, u.ID, func(int64) error { return errRefused })
+		assert.ErrorIs(t, err, errRefused)
+
+		var checked int64
+		created, err := repos.Tasks.CreateChecked(ctx, "second", "description", deadline, u.ID, func(count int64) error {
+			checked = count
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), checked)
+
+		found, err := repos.Tasks.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assertTask(t, created, found)
+
+		count, err := repos.Tasks.CountByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		_, err = repos.Tasks.CreateChecked(ctx, "second", "description", deadline, 42, func(int64) error { return nil })
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("checks concurrent tasks one at a time", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		deadline := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		errQuota := errors.New("quota exceeded")
+
+		// Only one task is allowed, whichever is checked first.
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, 5)
+		)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = repos.Tasks.CreateChecked(ctx, "task", "description", deadline, u.ID, func(count int64) error {
+					if count > 0 {
+						return errQuota
+					}
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+
+		count, err := repos.Tasks.CountByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.ElementsMatch(t, []error{nil, errQuota, errQuota, errQuota, errQuota}, errs)
+	})
+
+	t.Run("reassigns the tasks of a user", func(t *testing.T) {
+		repos := setup(t)
+		from := createUser(t, repos, "from@example.com")
+		to := createUser(t, repos, "to@example.com")
+
+		a := createTask(t, repos, "a", from.ID)
+		b := createTask(t, repos, "b", to.ID)
+		c := createTask(t, repos, "c", from.ID)
+
+		count, err := repos.Tasks.Reassign(ctx, from.ID, to.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		tasks, err := repos.Tasks.GetByUser(ctx, to.ID)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []int64{a.ID, b.ID, c.ID}, taskIds(tasks))
+
+		tasks, err = repos.Tasks.GetByUser(ctx, from.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, tasks)
+	})
+
+	t.Run("returns ErrNotFound for missing tasks", func(t *testing.T) {
+		repos := setup(t)
+
+		_, err := repos.Tasks.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		name := "renamed"
+		_, err = repos.Tasks.UpdateById(ctx, 42, domain.UpdateTaskData{Name: &name})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func testDependencies(t *testing.T, setup func(t *testing.T) Repositories) {
+	ctx := context.TODO()
+
+	t.Run("blocks tasks until their blockers are completed", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		blocked := createTask(t, repos, "blocked", u.ID)
+		blocker := createTask(t, repos, "blocker", u.ID)
+
+		assert.Nil(t, repos.Dependencies.Create(ctx, blocked.ID, blocker.ID))
+		assert.Nil(t, repos.Dependencies.Create(ctx, blocked.ID, blocker.ID), "creating a dependency twice is a no-op")
+
+		found, err := repos.Tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.True(t, found.Blocked)
+
+		blockers, err := repos.Dependencies.GetBlockers(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{blocker.ID}, taskIds(blockers))
+
+		blockedTasks, err := repos.Dependencies.GetBlocked(ctx, blocker.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{blocked.ID}, taskIds(blockedTasks))
+
+		dependencies, err := repos.Dependencies.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TaskDependency{{TaskId: blocked.ID, BlockedById: blocker.ID}}, dependencies)
+
+		completed := true
+		_, err = repos.Tasks.UpdateById(ctx, blocker.ID, domain.UpdateTaskData{Completed: &completed})
+		assert.Nil(t, err)
+
+		found, err = repos.Tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Blocked)
+	})
+
+	t.Run("creates checked dependencies unless the check fails", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		blocked := createTask(t, repos, "blocked", u.ID)
+		blocker := createTask(t, repos, "blocker", u.ID)
+		errRefused := errors.New("refused")
+
+		err := repos.Dependencies.CreateChecked(ctx, u.ID, blocked.ID, blocker.ID, func([]domain.TaskDependency) error { return errRefused })
+		assert.ErrorIs(t, err, errRefused)
+
+		dependencies, err := repos.Dependencies.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, dependencies)
+
+		var checked []domain.TaskDependency
+		err = repos.Dependencies.CreateChecked(ctx, u.ID, blocked.ID, blocker.ID, func(dependencies []domain.TaskDependency) error {
+			checked = dependencies
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Empty(t, checked)
+
+		dependencies, err = repos.Dependencies.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TaskDependency{{TaskId: blocked.ID, BlockedById: blocker.ID}}, dependencies)
+
+		err = repos.Dependencies.CreateChecked(ctx, 42, blocked.ID, blocker.ID, func([]domain.TaskDependency) error { return nil })
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("checks concurrent dependencies one at a time", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		first := createTask(t, repos, "first", u.ID)
+		second := createTask(t, repos, "second", u.ID)
+		errCycle := errors.New("cycle")
+
+		// Each dependency is refused once the other one exists.
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, 2)
+		)
+		for i, pair := range [][2]int64{{first.ID, second.ID}, {second.ID, first.ID}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = repos.Dependencies.CreateChecked(ctx, u.ID, pair[0], pair[1], func(dependencies []domain.TaskDependency) error {
+					if len(dependencies) > 0 {
+						return errCycle
+					}
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+
+		dependencies, err := repos.Dependencies.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Len(t, dependencies, 1)
+		assert.ElementsMatch(t, []error{nil, errCycle}, errs)
+	})
+
+	t.Run("removes dependencies", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		blocked := createTask(t, repos, "blocked", u.ID)
+		blocker := createTask(t, repos, "blocker", u.ID)
+		require.Nil(t, repos.Dependencies.Create(ctx, blocked.ID, blocker.ID))
+
+		assert.Nil(t, repos.Dependencies.Delete(ctx, blocked.ID, blocker.ID))
+
+		found, err := repos.Tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Blocked)
+
+		dependencies, err := repos.Dependencies.GetByUser(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, dependencies)
+	})
+
+	t.Run("deleting a task removes its dependencies", func(t *testing.T) {
+		repos := setup(t)
+		u := createUser(t, repos, "user@example.com")
+		blocked := createTask(t, repos, "blocked", u.ID)
+		blocker := createTask(t, repos, "blocker", u.ID)
+		require.Nil(t, repos.Dependencies.Create(ctx, blocked.ID, blocker.ID))
+
+		assert.Nil(t, repos.Tasks.DeleteById(ctx, blocker.ID))
+
+		found, err := repos.Tasks.GetById(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.False(t, found.Blocked)
+
+		blockers, err := repos.Dependencies.GetBlockers(ctx, blocked.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, blockers)
+	})
+}
+
+func testUsage(t *testing.T, setup func(t *testing.T) Repositories) {
+	ctx := context.TODO()
+
+	t.Run("counts API calls per user and day", func(t *testing.T) {
+		repos := setup(t)
+
+		for want := int64(1); want <= 3; want++ {
+			calls, err := repos.Usage.IncrementAPICalls(ctx, 1, "2025-03-10")
+			require.Nil(t, err)
+			assert.Equal(t, want, calls)
+		}
+		_, err := repos.Usage.IncrementAPICalls(ctx, 1, "2025-03-11")
+		require.Nil(t, err)
+		_, err = repos.Usage.IncrementAPICalls(ctx, 2, "2025-03-10")
+		require.Nil(t, err)
+
+		calls, err := repos.Usage.GetAPICalls(ctx, 1, "2025-03-10")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), calls)
+	})
+
+	t.Run("returns no calls for days without any", func(t *testing.T) {
+		repos := setup(t)
+
+		calls, err := repos.Usage.GetAPICalls(ctx, 1, "2025-03-10")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), calls)
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// models lists the models stored by the repositories of this package.
+var models = []interface{}{
+	&UserModel{},
+	&TaskModel{},
+	&TaskDependencyModel{},
+	&WebhookModel{},
+	&WebhookDeliveryModel{},
+	&NotificationModel{},
+	&ReminderModel{},
+	&PreferencesModel{},
+	&RateLimitModel{},
+	&APIUsageModel{},
+	&AuditLogModel{},
+}
+
+// OpenSQLite opens the SQLite database at path, ":memory:" for a database
+// discarded on exit, and creates the missing tables. The versioned migrations
+// are written for Postgres, so the SQLite schema is derived from the models.
+func OpenSQLite(path string, config *gorm.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(path), config)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer. A single connection also keeps an
+	// in-memory database alive and shared by every query.
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(models...); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
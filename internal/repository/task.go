@@ -6,6 +6,7 @@ import (
 
 	"github.com/krau5/hyper-todo/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskModel struct {
@@ -13,6 +14,13 @@ type TaskModel struct {
 	gorm.Model
 }
 
+// taskColumns selects the task columns along with the computed blocked flag.
+const taskColumns = `task_models.*, EXISTS (
+	SELECT 1 FROM task_dependency_models dependency
+	JOIN task_models blocker ON blocker.id = dependency.blocked_by_id AND blocker.deleted_at IS NULL
+	WHERE dependency.task_id = task_models.id AND NOT blocker.completed
+) AS blocked`
+
 type tasksRepository struct {
 	db *gorm.DB
 }
@@ -28,10 +36,40 @@ func (r *tasksRepository) Create(ctx context.Context, name, description string,
 
 	result := r.db.WithContext(ctx).Create(&taskModel)
 	if result.Error != nil {
-		return domain.Task{}, result.Error
+		return domain.Task{}, translateError(r.db, result.Error)
 	}
 
-	taskModel.Task.ID = int64(taskModel.Model.ID)
+	return taskModel.Task, nil
+}
+
+// CreateChecked creates a task of the user unless check fails on the number
+// of tasks the user has. Concurrent calls for the user are serialized by
+// locking the user's row, so tasks checked one by one can't be created
+// together.
+func (r *tasksRepository) CreateChecked(ctx context.Context, name, description string, deadline time.Time, userId int64, check func(int64) error) (domain.Task, error) {
+	taskModel := TaskModel{
+		Task: domain.Task{Name: name, Description: description, Deadline: deadline, UserId: userId},
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", userId).Take(&UserModel{}).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&TaskModel{}).Where("user_id = ?", userId).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if err := check(count); err != nil {
+			return err
+		}
+
+		return tx.Create(&taskModel).Error
+	})
+	if err != nil {
+		return domain.Task{}, translateError(r.db, err)
+	}
 
 	return taskModel.Task, nil
 }
@@ -39,9 +77,9 @@ func (r *tasksRepository) Create(ctx context.Context, name, description string,
 func (r *tasksRepository) GetById(ctx context.Context, id int64) (domain.Task, error) {
 	task := TaskModel{}
 
-	result := r.db.WithContext(ctx).First(&task, id)
+	result := r.db.WithContext(ctx).Select(taskColumns).First(&task, id)
 	if result.Error != nil {
-		return domain.Task{}, result.Error
+		return domain.Task{}, translateError(r.db, result.Error)
 	}
 
 	return task.Task, nil
@@ -49,9 +87,9 @@ func (r *tasksRepository) GetById(ctx context.Context, id int64) (domain.Task, e
 
 func (r *tasksRepository) GetByUser(ctx context.Context, userId int64) ([]domain.Task, error) {
 	rawTasks := []TaskModel{}
-	result := r.db.WithContext(ctx).Where("user_id = ?", userId).Find(&rawTasks)
+	result := r.db.WithContext(ctx).Select(taskColumns).Where("user_id = ?", userId).Find(&rawTasks)
 	if result.Error != nil {
-		return []domain.Task{}, result.Error
+		return []domain.Task{}, translateError(r.db, result.Error)
 	}
 
 	tasks := make([]domain.Task, len(rawTasks))
@@ -65,9 +103,9 @@ func (r *tasksRepository) GetByUser(ctx context.Context, userId int64) ([]domain
 func (r *tasksRepository) UpdateById(ctx context.Context, id int64, data domain.UpdateTaskData) (domain.Task, error) {
 	taskModel := TaskModel{}
 
-	result := r.db.WithContext(ctx).First(&taskModel, id)
+	result := r.db.WithContext(ctx).Select(taskColumns).First(&taskModel, id)
 	if result.Error != nil {
-		return domain.Task{}, result.Error
+		return domain.Task{}, translateError(r.db, result.Error)
 	}
 
 	updates := make(map[string]interface{})
@@ -80,19 +118,60 @@ func (r *tasksRepository) UpdateById(ctx context.Context, id int64, data domain.
 	if data.Deadline != nil && !(*data.Deadline).IsZero() {
 		updates["deadline"] = *data.Deadline
 	}
-	if data.Completed != nil {
+	if data.Completed != nil && *data.Completed != taskModel.Completed {
 		updates["completed"] = *data.Completed
+		if *data.Completed {
+			updates["completed_at"] = time.Now()
+		} else {
+			updates["completed_at"] = nil
+		}
 	}
 
 	result = r.db.WithContext(ctx).Model(&taskModel).Updates(updates)
 	if result.Error != nil {
-		return domain.Task{}, result.Error
+		return domain.Task{}, translateError(r.db, result.Error)
 	}
 
-	return taskModel.Task, nil
+	// Read the task back rather than returning the model, whose values may
+	// differ from the stored ones, e.g. in time precision.
+	return r.GetById(ctx, id)
 }
 
 func (r *tasksRepository) DeleteById(ctx context.Context, id int64) error {
-	result := r.db.WithContext(ctx).Delete(&TaskModel{}, id)
-	return result.Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("task_id = ? OR blocked_by_id = ?", id, id).Delete(&TaskDependencyModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Delete(&TaskModel{}, id).Error
+	})
+
+	return translateError(r.db, err)
+}
+
+func (r *tasksRepository) Reassign(ctx context.Context, fromUserId, toUserId int64) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&TaskModel{}).Where("user_id = ?", fromUserId).Update("user_id", toUserId)
+
+	return result.RowsAffected, translateError(r.db, result.Error)
+}
+
+func (r *tasksRepository) CountByUser(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&TaskModel{}).Where("user_id = ?", userId).Count(&count)
+
+	return count, translateError(r.db, result.Error)
+}
+
+func (r *tasksRepository) CountOverdue(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	condition := "NOT completed AND deadline < ?"
+	if r.db.Dialector.Name() == "sqlite" {
+		// SQLite stores times as text, which may carry different offsets.
+		condition = "NOT completed AND julianday(deadline) < julianday(?)"
+	}
+
+	result := r.db.WithContext(ctx).Model(&TaskModel{}).Where(condition, now).Count(&count)
+
+	return count, translateError(r.db, result.Error)
 }
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTasksRepository(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("returns the id of created tasks", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		repo := NewTasksRepository(db)
+
+		first, err := repo.Create(ctx, "first", "description", deadline, u.ID)
+		assert.Nil(t, err)
+		second, err := repo.Create(ctx, "second", "description", deadline, u.ID)
+		assert.Nil(t, err)
+
+		assert.NotZero(t, first.ID)
+		assert.NotEqual(t, first.ID, second.ID)
+
+		found, err := repo.GetById(ctx, second.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "second", found.Name)
+		assert.Equal(t, "description", found.Description)
+		assert.True(t, deadline.Equal(found.Deadline))
+		assert.Equal(t, u.ID, found.UserId)
+		assert.False(t, found.Completed)
+		assert.Nil(t, found.CompletedAt)
+	})
+
+	t.Run("gets the tasks of a user", func(t *testing.T) {
+		db := newTestDB(t)
+		first := createUser(t, db, "first@example.com")
+		second := createUser(t, db, "second@example.com")
+		a := createTask(t, db, "a", deadline, first.ID)
+		createTask(t, db, "b", deadline, second.ID)
+		c := createTask(t, db, "c", deadline, first.ID)
+
+		tasks, err := NewTasksRepository(db).GetByUser(ctx, first.ID)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []int64{a.ID, c.ID}, ids(tasks, taskId))
+	})
+
+	t.Run("returns the stored task after an update", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		created := createTask(t, db, "task", deadline, u.ID)
+		repo := NewTasksRepository(db)
+
+		name := "renamed"
+		// Nanoseconds are dropped by Postgres, the returned task must match.
+		newDeadline := deadline.Add(time.Hour + time.Nanosecond)
+		completed := true
+		updated, err := repo.UpdateById(ctx, created.ID, domain.UpdateTaskData{
+			Name:      &name,
+			Deadline:  &newDeadline,
+			Completed: &completed,
+		})
+		assert.Nil(t, err)
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, created.ID, updated.ID)
+		assert.Equal(t, "renamed", updated.Name)
+		assert.Equal(t, "description", updated.Description)
+		assert.True(t, updated.Completed)
+		assert.NotNil(t, updated.CompletedAt)
+		assert.True(t, found.Deadline.Equal(updated.Deadline))
+		assert.True(t, found.CompletedAt.Equal(*updated.CompletedAt))
+
+		completed = false
+		updated, err = repo.UpdateById(ctx, created.ID, domain.UpdateTaskData{Completed: &completed})
+		assert.Nil(t, err)
+		assert.False(t, updated.Completed)
+		assert.Nil(t, updated.CompletedAt)
+	})
+
+	t.Run("soft deletes tasks with their dependencies", func(t *testing.T) {
+		db := newTestDB(t)
+		u := createUser(t, db, "user@example.com")
+		blocked := createTask(t, db, "blocked", deadline, u.ID)
+		blocker := createTask(t, db, "blocker", deadline, u.ID)
+		assert.Nil(t, NewDependenciesRepository(db).Create(ctx, blocked.ID, blocker.ID))
+		repo := NewTasksRepository(db)
+
+		err := repo.DeleteById(ctx, blocker.ID)
+		assert.Nil(t, err)
+
+		_, err = repo.GetById(ctx, blocker.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		var deleted TaskModel
+		assert.Nil(t, db.Unscoped().First(&deleted, blocker.ID).Error)
+		assert.True(t, deleted.DeletedAt.Valid)
+
+		var dependencies int64
+		assert.Nil(t, db.Model(&TaskDependencyModel{}).Count(&dependencies).Error)
+		assert.Zero(t, dependencies)
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewTasksRepository(newTestDB(t))
+
+		_, err := repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		name := "renamed"
+		_, err = repo.UpdateById(ctx, 42, domain.UpdateTaskData{Name: &name})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
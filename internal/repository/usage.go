@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// APIUsageModel counts the API calls of a user on a day, in the "2006-01-02"
+// format.
+type APIUsageModel struct {
+	UserId int64  `gorm:"primaryKey;autoIncrement:false"`
+	Day    string `gorm:"primaryKey"`
+	Calls  int64  `gorm:"not null"`
+}
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageRepository(db *gorm.DB) *usageRepository {
+	return &usageRepository{db: db}
+}
+
+func (r *usageRepository) IncrementAPICalls(ctx context.Context, userId int64, day string) (int64, error) {
+	usage := APIUsageModel{UserId: userId, Day: day, Calls: 1}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]any{"calls": gorm.Expr("api_usage_models.calls + 1")}),
+		}).Create(&usage).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND day = ?", userId, day).First(&usage).Error
+	})
+	if err != nil {
+		return 0, translateError(r.db, err)
+	}
+
+	return usage.Calls, nil
+}
+
+func (r *usageRepository) GetAPICalls(ctx context.Context, userId int64, day string) (int64, error) {
+	var calls int64
+	result := r.db.WithContext(ctx).Model(&APIUsageModel{}).
+		Where("user_id = ? AND day = ?", userId, day).
+		Select("COALESCE(SUM(calls), 0)").
+		Scan(&calls)
+
+	return calls, translateError(r.db, result.Error)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/krau5/hyper-todo/domain"
 	"github.com/krau5/hyper-todo/internal/utils"
@@ -30,11 +32,11 @@ func (r *usersRepository) Create(ctx context.Context, name, email, password stri
 	}
 
 	user := UserModel{
-		User: domain.User{Name: name, Email: email, Password: hash},
+		User: domain.User{Name: name, Email: email, Password: hash, Role: domain.RoleUser},
 	}
 
 	result := r.db.WithContext(ctx).Create(&user)
-	return result.Error
+	return translateError(r.db, result.Error)
 }
 
 func (r *usersRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
@@ -42,7 +44,7 @@ func (r *usersRepository) GetByEmail(ctx context.Context, email string) (domain.
 
 	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
-		return domain.User{}, result.Error
+		return domain.User{}, translateError(r.db, result.Error)
 	}
 
 	return user.User, nil
@@ -53,8 +55,84 @@ func (r *usersRepository) GetById(ctx context.Context, id int64) (domain.User, e
 
 	result := r.db.WithContext(ctx).First(&user, id)
 	if result.Error != nil {
-		return domain.User{}, result.Error
+		return domain.User{}, translateError(r.db, result.Error)
 	}
 
 	return user.User, nil
 }
+
+func (r *usersRepository) List(ctx context.Context) ([]domain.User, error) {
+	rawUsers := []UserModel{}
+	result := r.db.WithContext(ctx).Order("id").Find(&rawUsers)
+	if result.Error != nil {
+		return []domain.User{}, translateError(r.db, result.Error)
+	}
+
+	users := make([]domain.User, len(rawUsers))
+	for i, userModel := range rawUsers {
+		users[i] = userModel.User
+	}
+
+	return users, nil
+}
+
+func (r *usersRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return r.update(ctx, id, "password", hash)
+}
+
+func (r *usersRepository) SetLocked(ctx context.Context, id int64, lockedAt *time.Time) error {
+	return r.update(ctx, id, "locked_at", lockedAt)
+}
+
+func (r *usersRepository) SetRole(ctx context.Context, id int64, role domain.Role) error {
+	return r.update(ctx, id, "role", role)
+}
+
+func (r *usersRepository) RevokeSessions(ctx context.Context, id int64, revokedAt time.Time) error {
+	return r.update(ctx, id, "sessions_revoked_at", revokedAt)
+}
+
+func (r *usersRepository) Search(ctx context.Context, query string, limit, offset int) ([]domain.User, error) {
+	pattern := "%" + escapeLike(strings.ToLower(query)) + "%"
+
+	rawUsers := []UserModel{}
+	result := r.db.WithContext(ctx).
+		Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern).
+		Order("id").Limit(limit).Offset(offset).
+		Find(&rawUsers)
+	if result.Error != nil {
+		return []domain.User{}, translateError(r.db, result.Error)
+	}
+
+	users := make([]domain.User, len(rawUsers))
+	for i, userModel := range rawUsers {
+		users[i] = userModel.User
+	}
+
+	return users, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// update sets a column of a user, failing with domain.ErrNotFound if there
+// is no such user.
+func (r *usersRepository) update(ctx context.Context, id int64, column string, value any) error {
+	result := r.db.WithContext(ctx).Model(&UserModel{}).Where("id = ?", id).Update(column, value)
+	if result.Error != nil {
+		return translateError(r.db, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
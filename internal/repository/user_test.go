@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersRepository(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("creates users with a hashed password", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewUserRepository(db)
+
+		err := repo.Create(ctx, "user", "user@example.com", "password")
+		assert.Nil(t, err)
+
+		byEmail, err := repo.GetByEmail(ctx, "user@example.com")
+		assert.Nil(t, err)
+		assert.NotZero(t, byEmail.ID)
+		assert.Equal(t, "user", byEmail.Name)
+		assert.NotEqual(t, "password", byEmail.Password)
+		assert.True(t, utils.VerifyPassword("password", byEmail.Password))
+
+		byId, err := repo.GetById(ctx, byEmail.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, byEmail, byId)
+	})
+
+	t.Run("maps unique violations to ErrDuplicate", func(t *testing.T) {
+		db := newTestDB(t)
+		createUser(t, db, "user@example.com")
+
+		err := NewUserRepository(db).Create(ctx, "other", "user@example.com", "password")
+		assert.ErrorIs(t, err, domain.ErrDuplicate)
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewUserRepository(newTestDB(t))
+
+		_, err := repo.GetByEmail(ctx, "missing@example.com")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
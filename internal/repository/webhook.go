@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookModel struct {
+	domain.Webhook
+	gorm.Model
+}
+
+type WebhookDeliveryModel struct {
+	domain.WebhookDelivery
+	gorm.Model
+}
+
+type webhooksRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhooksRepository(db *gorm.DB) *webhooksRepository {
+	return &webhooksRepository{db: db}
+}
+
+func (r *webhooksRepository) Create(ctx context.Context, url, secret string, eventTypes []domain.EventType, userId int64) (domain.Webhook, error) {
+	if eventTypes == nil {
+		eventTypes = []domain.EventType{}
+	}
+
+	webhookModel := WebhookModel{
+		Webhook: domain.Webhook{URL: url, Secret: secret, EventTypes: eventTypes, Active: true, UserId: userId},
+	}
+
+	result := r.db.WithContext(ctx).Create(&webhookModel)
+	if result.Error != nil {
+		return domain.Webhook{}, translateError(r.db, result.Error)
+	}
+
+	return webhookModel.Webhook, nil
+}
+
+func (r *webhooksRepository) GetById(ctx context.Context, id int64) (domain.Webhook, error) {
+	webhook := WebhookModel{}
+
+	result := r.db.WithContext(ctx).First(&webhook, id)
+	if result.Error != nil {
+		return domain.Webhook{}, translateError(r.db, result.Error)
+	}
+
+	return webhook.Webhook, nil
+}
+
+func (r *webhooksRepository) GetByUser(ctx context.Context, userId int64) ([]domain.Webhook, error) {
+	rawWebhooks := []WebhookModel{}
+	result := r.db.WithContext(ctx).Where("user_id = ?", userId).Order("id").Find(&rawWebhooks)
+	if result.Error != nil {
+		return []domain.Webhook{}, translateError(r.db, result.Error)
+	}
+
+	webhooks := make([]domain.Webhook, len(rawWebhooks))
+	for i, webhookModel := range rawWebhooks {
+		webhooks[i] = webhookModel.Webhook
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhooksRepository) UpdateById(ctx context.Context, id int64, data domain.UpdateWebhookData) (domain.Webhook, error) {
+	webhookModel := WebhookModel{}
+
+	result := r.db.WithContext(ctx).First(&webhookModel, id)
+	if result.Error != nil {
+		return domain.Webhook{}, translateError(r.db, result.Error)
+	}
+
+	if data.URL != nil && len(*data.URL) != 0 {
+		webhookModel.URL = *data.URL
+	}
+	if data.EventTypes != nil {
+		webhookModel.EventTypes = *data.EventTypes
+	}
+	if data.Active != nil {
+		webhookModel.Active = *data.Active
+	}
+
+	result = r.db.WithContext(ctx).Model(&webhookModel).
+		Select("url", "event_types", "active").
+		Updates(&webhookModel)
+	if result.Error != nil {
+		return domain.Webhook{}, translateError(r.db, result.Error)
+	}
+
+	return webhookModel.Webhook, nil
+}
+
+func (r *webhooksRepository) DeleteById(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&WebhookModel{}, id)
+	return translateError(r.db, result.Error)
+}
+
+type deliveriesRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveriesRepository(db *gorm.DB) *deliveriesRepository {
+	return &deliveriesRepository{db: db}
+}
+
+func (r *deliveriesRepository) Create(ctx context.Context, webhookId int64, eventType domain.EventType, payload []byte) (domain.WebhookDelivery, error) {
+	deliveryModel := WebhookDeliveryModel{
+		WebhookDelivery: domain.WebhookDelivery{
+			WebhookId:     webhookId,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		},
+	}
+
+	result := r.db.WithContext(ctx).Create(&deliveryModel)
+	if result.Error != nil {
+		return domain.WebhookDelivery{}, translateError(r.db, result.Error)
+	}
+
+	return deliveryModel.WebhookDelivery, nil
+}
+
+func (r *deliveriesRepository) GetById(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	delivery := WebhookDeliveryModel{}
+
+	result := r.db.WithContext(ctx).First(&delivery, id)
+	if result.Error != nil {
+		return domain.WebhookDelivery{}, translateError(r.db, result.Error)
+	}
+
+	return delivery.WebhookDelivery, nil
+}
+
+func (r *deliveriesRepository) GetByWebhook(ctx context.Context, webhookId int64, limit int) ([]domain.WebhookDelivery, error) {
+	rawDeliveries := []WebhookDeliveryModel{}
+	result := r.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookId).
+		Order("id DESC").
+		Limit(limit).
+		Find(&rawDeliveries)
+	if result.Error != nil {
+		return []domain.WebhookDelivery{}, translateError(r.db, result.Error)
+	}
+
+	return toDeliveries(rawDeliveries), nil
+}
+
+// ClaimDue locks a batch of due deliveries, skipping rows locked by other
+// dispatchers, and postpones them by the lease so they are not picked up
+// again while being sent.
+func (r *deliveriesRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	rawDeliveries := []WebhookDeliveryModel{}
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []domain.DeliveryStatus{domain.DeliveryPending, domain.DeliveryRetrying}, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&rawDeliveries)
+		if result.Error != nil || len(rawDeliveries) == 0 {
+			return result.Error
+		}
+
+		ids := make([]int64, len(rawDeliveries))
+		for i, d := range rawDeliveries {
+			ids[i] = d.WebhookDelivery.ID
+		}
+
+		return tx.Model(&WebhookDeliveryModel{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return []domain.WebhookDelivery{}, translateError(r.db, err)
+	}
+
+	return toDeliveries(rawDeliveries), nil
+}
+
+func (r *deliveriesRepository) Update(ctx context.Context, delivery domain.WebhookDelivery) error {
+	result := r.db.WithContext(ctx).Model(&WebhookDeliveryModel{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+			"response_status": delivery.ResponseStatus,
+			"delivered_at":    delivery.DeliveredAt,
+		})
+
+	return translateError(r.db, result.Error)
+}
+
+func toDeliveries(rawDeliveries []WebhookDeliveryModel) []domain.WebhookDelivery {
+	deliveries := make([]domain.WebhookDelivery, len(rawDeliveries))
+	for i, deliveryModel := range rawDeliveries {
+		deliveries[i] = deliveryModel.WebhookDelivery
+	}
+
+	return deliveries
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func deliveryId(d domain.WebhookDelivery) int64 { return d.ID }
+
+func TestWebhooksRepository(t *testing.T) {
+	ctx := context.TODO()
+	eventTypes := []domain.EventType{domain.EventTaskCreated}
+
+	t.Run("creates and gets webhooks", func(t *testing.T) {
+		repo := NewWebhooksRepository(newTestDB(t))
+
+		created, err := repo.Create(ctx, "https://example.com/hook", "secret", eventTypes, 1)
+		assert.Nil(t, err)
+		assert.NotZero(t, created.ID)
+		assert.True(t, created.Active)
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, created, found)
+
+		all, err := repo.Create(ctx, "https://example.com/all", "secret", nil, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.EventType{}, all.EventTypes)
+		repo.Create(ctx, "https://example.com/other", "secret", nil, 2)
+
+		webhooks, err := repo.GetByUser(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Webhook{created, all}, webhooks)
+	})
+
+	t.Run("updates the given fields", func(t *testing.T) {
+		repo := NewWebhooksRepository(newTestDB(t))
+		created, _ := repo.Create(ctx, "https://example.com/hook", "secret", eventTypes, 1)
+
+		active := false
+		updated, err := repo.UpdateById(ctx, created.ID, domain.UpdateWebhookData{Active: &active})
+		assert.Nil(t, err)
+		assert.False(t, updated.Active)
+		assert.Equal(t, created.URL, updated.URL)
+
+		url := "https://example.com/new"
+		newEventTypes := []domain.EventType{domain.EventTaskDeleted}
+		_, err = repo.UpdateById(ctx, created.ID, domain.UpdateWebhookData{URL: &url, EventTypes: &newEventTypes})
+		assert.Nil(t, err)
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Webhook{
+			ID:         created.ID,
+			URL:        url,
+			Secret:     "secret",
+			EventTypes: newEventTypes,
+			Active:     false,
+			UserId:     1,
+		}, found)
+	})
+
+	t.Run("deletes webhooks", func(t *testing.T) {
+		repo := NewWebhooksRepository(newTestDB(t))
+		created, _ := repo.Create(ctx, "https://example.com/hook", "secret", eventTypes, 1)
+
+		assert.Nil(t, repo.DeleteById(ctx, created.ID))
+
+		_, err := repo.GetById(ctx, created.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewWebhooksRepository(newTestDB(t))
+
+		_, err := repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		active := false
+		_, err = repo.UpdateById(ctx, 42, domain.UpdateWebhookData{Active: &active})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestDeliveriesRepository(t *testing.T) {
+	ctx := context.TODO()
+	payload := []byte(`{"type":"task.created"}`)
+
+	t.Run("creates pending deliveries", func(t *testing.T) {
+		repo := NewDeliveriesRepository(newTestDB(t))
+
+		created, err := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		assert.Nil(t, err)
+		assert.NotZero(t, created.ID)
+		assert.Equal(t, domain.DeliveryPending, created.Status)
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, int64(1), found.WebhookId)
+		assert.Equal(t, domain.EventTaskCreated, found.EventType)
+		assert.JSONEq(t, string(payload), string(found.Payload))
+		assert.Equal(t, domain.DeliveryPending, found.Status)
+	})
+
+	t.Run("gets the latest deliveries of a webhook", func(t *testing.T) {
+		repo := NewDeliveriesRepository(newTestDB(t))
+		repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		second, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		third, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		repo.Create(ctx, 2, domain.EventTaskCreated, payload)
+
+		deliveries, err := repo.GetByWebhook(ctx, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{third.ID, second.ID}, ids(deliveries, deliveryId))
+	})
+
+	t.Run("claims due deliveries for the lease", func(t *testing.T) {
+		repo := NewDeliveriesRepository(newTestDB(t))
+		pending, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		retrying, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		succeeded, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+		later, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+
+		retrying.Status = domain.DeliveryRetrying
+		retrying.Attempts = 1
+		assert.Nil(t, repo.Update(ctx, retrying))
+		succeeded.Status = domain.DeliverySucceeded
+		assert.Nil(t, repo.Update(ctx, succeeded))
+		later.NextAttemptAt = time.Now().Add(time.Hour)
+		assert.Nil(t, repo.Update(ctx, later))
+
+		claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []int64{pending.ID, retrying.ID}, ids(claimed, deliveryId))
+
+		claimed, err = repo.ClaimDue(ctx, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Empty(t, claimed)
+	})
+
+	t.Run("updates deliveries", func(t *testing.T) {
+		repo := NewDeliveriesRepository(newTestDB(t))
+		created, _ := repo.Create(ctx, 1, domain.EventTaskCreated, payload)
+
+		deliveredAt := time.Now().Truncate(time.Second)
+		created.Status = domain.DeliverySucceeded
+		created.Attempts = 2
+		created.LastError = "timeout"
+		created.ResponseStatus = 200
+		created.DeliveredAt = &deliveredAt
+		assert.Nil(t, repo.Update(ctx, created))
+
+		found, err := repo.GetById(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DeliverySucceeded, found.Status)
+		assert.Equal(t, 2, found.Attempts)
+		assert.Equal(t, "timeout", found.LastError)
+		assert.Equal(t, 200, found.ResponseStatus)
+		assert.True(t, deliveredAt.Equal(*found.DeliveredAt))
+	})
+
+	t.Run("maps missing rows to ErrNotFound", func(t *testing.T) {
+		repo := NewDeliveriesRepository(newTestDB(t))
+
+		_, err := repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,319 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/audit"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/user"
+)
+
+//go:generate mockery --name AdminUsersService
+type AdminUsersService interface {
+	GetById(context.Context, int64) (domain.User, error)
+	Search(ctx context.Context, query string, limit, offset int) ([]domain.User, error)
+	Lock(context.Context, int64) error
+	Unlock(context.Context, int64) error
+	RevokeSessions(context.Context, int64) error
+}
+
+//go:generate mockery --name AuditService
+type AuditService interface {
+	Record(context.Context, domain.AuditLog) error
+	List(context.Context, domain.AuditLogFilter) ([]domain.AuditLog, error)
+}
+
+// AdminHandler handles the requests of support staff and admins.
+type AdminHandler struct {
+	usersService AdminUsersService
+	tasksService TasksService
+	auditService AuditService
+}
+
+// AdminUser is a user as seen by support staff and admins.
+type AdminUser struct {
+	ID                int64       `json:"id" example:"1"`
+	Name              string      `json:"name" example:"John Doe"`
+	Email             string      `json:"email" example:"john@example.com"`
+	Role              domain.Role `json:"role" example:"user"`
+	Plan              string      `json:"plan,omitempty" example:"pro"` // Empty for the default plan
+	LockedAt          *time.Time  `json:"lockedAt,omitempty"`
+	SessionsRevokedAt *time.Time  `json:"sessionsRevokedAt,omitempty"`
+}
+
+func newAdminUser(u domain.User) AdminUser {
+	return AdminUser{
+		ID:                u.ID,
+		Name:              u.Name,
+		Email:             u.Email,
+		Role:              u.Role,
+		Plan:              u.Plan,
+		LockedAt:          u.LockedAt,
+		SessionsRevokedAt: u.SessionsRevokedAt,
+	}
+}
+
+var (
+	ErrInvalidUserId             = appErrors.NewResponseError(http.StatusBadRequest, "invalid_user_id", "user id is missing or invalid")
+	ErrInvalidPagination         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_pagination", "limit must be between 1 and 100 and offset must not be negative")
+	ErrInvalidAuditFilter        = appErrors.NewResponseError(http.StatusBadRequest, "invalid_audit_filter", "actorId and targetUserId must be ids and limit between 1 and 500")
+	ErrCannotDisableSelf         = appErrors.NewResponseError(http.StatusConflict, "cannot_disable_self", "admins can't disable their own account")
+	ErrCannotManageUser          = appErrors.NewResponseError(http.StatusForbidden, "cannot_manage_user", "staff can only act on users with a lower role")
+	ErrFailedToSearchUsers       = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_search_users", "failed to search users")
+	ErrFailedToUpdateUser        = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_user", "failed to update user")
+	ErrFailedToRetrieveAuditLogs = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_audit_logs", "failed to retrieve audit logs")
+)
+
+// Roles allowed on the admin routes.
+var (
+	staffRoles = []domain.Role{domain.RoleSupport, domain.RoleAdmin}
+	adminRoles = []domain.Role{domain.RoleAdmin}
+)
+
+// NewAdminHandler registers the admin handler with the Gin engine. Every
+// request is audited once authenticated, including those refused for lack
+// of a role.
+func NewAdminHandler(r gin.IRouter, auth gin.HandlerFunc, usersService AdminUsersService, tasksService TasksService, auditService AuditService) {
+	h := &AdminHandler{usersService: usersService, tasksService: tasksService, auditService: auditService}
+
+	staff := middleware.RequireRole(usersService, staffRoles...)
+	admin := middleware.RequireRole(usersService, adminRoles...)
+
+	g := r.Group("/admin", auth)
+	g.GET("/users", h.audit("users.search"), staff, h.handleSearchUsers)
+	g.GET("/users/:userId/tasks", h.audit("users.tasks.view"), staff, h.handleGetUserTasks)
+	g.POST("/users/:userId/logout", h.audit("users.logout"), staff, h.handleLogoutUser)
+	g.POST("/users/:userId/disable", h.audit("users.disable"), admin, h.handleDisableUser)
+	g.POST("/users/:userId/enable", h.audit("users.enable"), admin, h.handleEnableUser)
+	g.GET("/audit-logs", h.audit("audit_logs.view"), admin, h.handleGetAuditLogs)
+}
+
+// audit records the action once the request is handled, with the code of
+// the problem it failed with.
+func (h *AdminHandler) audit(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		log := domain.AuditLog{
+			ActorId:   c.GetInt64("user-id"),
+			Action:    action,
+			Details:   c.Request.URL.RawQuery,
+			Succeeded: len(c.Errors) == 0,
+			RequestId: c.GetString("request-id"),
+		}
+		log.TargetUserId, _ = strconv.ParseInt(c.Param("userId"), 10, 64)
+		if last := c.Errors.Last(); last != nil {
+			log.Error = appErrors.FromError(last.Err).Code
+		}
+
+		// The action happened even if the client went away meanwhile.
+		h.auditService.Record(context.WithoutCancel(c.Request.Context()), log)
+	}
+}
+
+// handleSearchUsers lists the users whose name or email contains q.
+func (h *AdminHandler) handleSearchUsers(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.Error(ErrInvalidPagination)
+		return
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		c.Error(ErrInvalidPagination)
+		return
+	}
+
+	users, err := h.usersService.Search(c.Request.Context(), c.Query("q"), int(limit), int(offset))
+	if errors.Is(err, user.ErrInvalidLimit) || errors.Is(err, user.ErrInvalidOffset) {
+		c.Error(ErrInvalidPagination)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToSearchUsers)
+		return
+	}
+
+	result := make([]AdminUser, 0, len(users))
+	for _, found := range users {
+		result = append(result, newAdminUser(found))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleGetUserTasks retrieves the tasks of a user, read-only.
+func (h *AdminHandler) handleGetUserTasks(c *gin.Context) {
+	targetId, ok := userIdParam(c)
+	if !ok || !h.canManage(c, targetId) {
+		return
+	}
+
+	tasks, err := h.tasksService.GetByUser(c.Request.Context(), targetId)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveTasks)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+// handleLogoutUser refuses the tokens issued to a user until now.
+func (h *AdminHandler) handleLogoutUser(c *gin.Context) {
+	targetId, ok := userIdParam(c)
+	if !ok || !h.canManage(c, targetId) {
+		return
+	}
+
+	h.updateUser(c, targetId, h.usersService.RevokeSessions)
+}
+
+// handleDisableUser locks the account of a user, refusing their tokens.
+func (h *AdminHandler) handleDisableUser(c *gin.Context) {
+	targetId, ok := userIdParam(c)
+	if !ok {
+		return
+	}
+
+	if targetId == c.GetInt64("user-id") {
+		c.Error(ErrCannotDisableSelf)
+		return
+	}
+
+	if !h.canManage(c, targetId) {
+		return
+	}
+
+	h.updateUser(c, targetId, h.usersService.Lock)
+}
+
+// handleEnableUser unlocks the account of a user.
+func (h *AdminHandler) handleEnableUser(c *gin.Context) {
+	targetId, ok := userIdParam(c)
+	if !ok || !h.canManage(c, targetId) {
+		return
+	}
+
+	h.updateUser(c, targetId, h.usersService.Unlock)
+}
+
+// canManage reports whether the authenticated user outranks the target user,
+// so that staff can't act on their peers or superiors. Admins act on support
+// staff and users, support staff on users only.
+func (h *AdminHandler) canManage(c *gin.Context, targetId int64) bool {
+	actor, err := h.usersService.GetById(c.Request.Context(), c.GetInt64("user-id"))
+	if err != nil {
+		c.Error(ErrFailedToRetrieveUser)
+		return false
+	}
+
+	target, err := h.usersService.GetById(c.Request.Context(), targetId)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
+		return false
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveUser)
+		return false
+	}
+
+	if !actor.Role.Outranks(target.Role) {
+		c.Error(ErrCannotManageUser)
+		return false
+	}
+
+	return true
+}
+
+// updateUser applies an update to a user and responds with the user.
+func (h *AdminHandler) updateUser(c *gin.Context, id int64, update func(context.Context, int64) error) {
+	err := update(c.Request.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToUpdateUser)
+		return
+	}
+
+	updated, err := h.usersService.GetById(c.Request.Context(), id)
+	if err != nil {
+		c.Error(ErrFailedToRetrieveUser)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUser(updated))
+}
+
+// handleGetAuditLogs lists the audit logs, newest first.
+func (h *AdminHandler) handleGetAuditLogs(c *gin.Context) {
+	var (
+		filter = domain.AuditLogFilter{Action: c.Query("action")}
+		err    error
+		limit  int64
+	)
+
+	if filter.ActorId, err = queryInt(c, "actorId"); err != nil {
+		c.Error(ErrInvalidAuditFilter)
+		return
+	}
+	if filter.TargetUserId, err = queryInt(c, "targetUserId"); err != nil {
+		c.Error(ErrInvalidAuditFilter)
+		return
+	}
+	if limit, err = queryInt(c, "limit"); err != nil {
+		c.Error(ErrInvalidAuditFilter)
+		return
+	}
+	filter.Limit = int(limit)
+
+	logs, err := h.auditService.List(c.Request.Context(), filter)
+	if errors.Is(err, audit.ErrInvalidLimit) {
+		c.Error(ErrInvalidAuditFilter)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveAuditLogs)
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// userIdParam parses the userId path parameter, reporting an invalid one.
+func userIdParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || id <= 0 {
+		c.Error(ErrInvalidUserId)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// queryInt parses an optional integer query parameter, which is 0 if absent.
+func queryInt(c *gin.Context, key string) (int64, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(raw, 10, 64)
+}
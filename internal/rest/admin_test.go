@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/krau5/hyper-todo/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const targetId int64 = 2
+
+func TestSearchUsersHandler(t *testing.T) {
+	r, usersService, _, auditService := setupAdminTest(t, domain.RoleSupport)
+	usersService.On("Search", mock.Anything, "john", 10, 20).Return([]domain.User{{ID: targetId, Name: "John Doe", Email: "john@example.com", Password: "hash", Role: domain.RoleUser}}, nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.search", Details: "q=john&limit=10&offset=20", Succeeded: true}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users?q=john&limit=10&offset=20", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":2,"name":"John Doe","email":"john@example.com","role":"user"}]`, w.Body.String())
+}
+
+func TestSearchUsersHandler_InvalidPagination(t *testing.T) {
+	r, usersService, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+	usersService.On("Search", mock.Anything, "", 1000, 0).Return([]domain.User{}, user.ErrInvalidLimit)
+	auditService.On("Record", mock.Anything, mock.MatchedBy(func(log domain.AuditLog) bool {
+		return !log.Succeeded && log.Error == "invalid_pagination"
+	})).Return(nil)
+
+	for _, query := range []string{"limit=ten", "offset=-", "limit=1000"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/users?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		expectedBody, _ := json.Marshal(ErrInvalidPagination)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, string(expectedBody), w.Body.String(), query)
+	}
+}
+
+func TestAdminHandler_Forbidden(t *testing.T) {
+	r, _, _, auditService := setupAdminTest(t, domain.RoleSupport)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: targetId, Error: "forbidden"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/2/disable", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGetUserTasksHandler(t *testing.T) {
+	r, usersService, tasksService, auditService := setupAdminTest(t, domain.RoleSupport)
+	mockTasks := []domain.Task{{ID: 1, Name: "eat", UserId: targetId}}
+	tasksService.On("GetByUser", mock.Anything, targetId).Return(mockTasks, nil)
+	usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleUser}, nil)
+	usersService.On("GetById", mock.Anything, int64(3)).Return(domain.User{}, domain.ErrNotFound)
+	auditService.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users/2/tasks", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(mockTasks)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/users/3/tasks", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ = json.Marshal(ErrUserNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/users/abc/tasks", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ = json.Marshal(ErrInvalidUserId)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestLogoutUserHandler(t *testing.T) {
+	r, usersService, _, auditService := setupAdminTest(t, domain.RoleSupport)
+	revokedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	usersService.On("RevokeSessions", mock.Anything, targetId).Return(nil)
+	usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleUser, SessionsRevokedAt: &revokedAt}, nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.logout", TargetUserId: targetId, Succeeded: true}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/2/logout", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":2,"name":"","email":"","role":"user","sessionsRevokedAt":"2025-01-01T00:00:00Z"}`, w.Body.String())
+}
+
+func TestDisableUserHandler(t *testing.T) {
+	r, usersService, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+	lockedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	usersService.On("Lock", mock.Anything, targetId).Return(nil)
+	usersService.On("GetById", mock.Anything, int64(3)).Return(domain.User{}, domain.ErrNotFound)
+	usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleUser, LockedAt: &lockedAt}, nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: targetId, Succeeded: true}).Return(nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: 3, Error: "user_not_found"}).Return(nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: userId, Error: "cannot_disable_self"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/2/disable", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":2,"name":"","email":"","role":"user","lockedAt":"2025-01-01T00:00:00Z"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/admin/users/3/disable", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/admin/users/1/disable", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrCannotDisableSelf)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestAdminHandler_CannotManagePeersOrSuperiors(t *testing.T) {
+	t.Run("support can't log admins out", func(t *testing.T) {
+		r, usersService, _, auditService := setupAdminTest(t, domain.RoleSupport)
+		usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleAdmin}, nil)
+		auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.logout", TargetUserId: targetId, Error: "cannot_manage_user"}).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/users/2/logout", nil)
+		r.ServeHTTP(w, req)
+
+		expectedBody, _ := json.Marshal(ErrCannotManageUser)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, string(expectedBody), w.Body.String())
+	})
+
+	t.Run("support can't view the tasks of support staff", func(t *testing.T) {
+		r, usersService, _, auditService := setupAdminTest(t, domain.RoleSupport)
+		usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleSupport}, nil)
+		auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.tasks.view", TargetUserId: targetId, Error: "cannot_manage_user"}).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/users/2/tasks", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("admins can't disable admins", func(t *testing.T) {
+		r, usersService, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+		usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleAdmin}, nil)
+		auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: targetId, Error: "cannot_manage_user"}).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/users/2/disable", nil)
+		r.ServeHTTP(w, req)
+
+		expectedBody, _ := json.Marshal(ErrCannotManageUser)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, string(expectedBody), w.Body.String())
+	})
+
+	t.Run("admins can disable support staff", func(t *testing.T) {
+		r, usersService, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+		usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleSupport}, nil)
+		usersService.On("Lock", mock.Anything, targetId).Return(nil)
+		auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.disable", TargetUserId: targetId, Succeeded: true}).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/users/2/disable", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestEnableUserHandler(t *testing.T) {
+	r, usersService, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+	usersService.On("Unlock", mock.Anything, targetId).Return(nil)
+	usersService.On("GetById", mock.Anything, targetId).Return(domain.User{ID: targetId, Role: domain.RoleUser}, nil)
+	auditService.On("Record", mock.Anything, domain.AuditLog{ActorId: userId, Action: "users.enable", TargetUserId: targetId, Succeeded: true}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/2/enable", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetAuditLogsHandler(t *testing.T) {
+	r, _, _, auditService := setupAdminTest(t, domain.RoleAdmin)
+	mockLogs := []domain.AuditLog{{ID: 1, ActorId: userId, Action: "users.disable", TargetUserId: targetId, Succeeded: true}}
+	auditService.On("List", mock.Anything, domain.AuditLogFilter{ActorId: userId, TargetUserId: targetId, Action: "users.disable", Limit: 10}).Return(mockLogs, nil)
+	auditService.On("Record", mock.Anything, mock.MatchedBy(func(log domain.AuditLog) bool {
+		return log.Action == "audit_logs.view"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/audit-logs?actorId=1&targetUserId=2&action=users.disable&limit=10", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(mockLogs)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/audit-logs?actorId=me", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// setupAdminTest authenticates requests as user 1, who has the role.
+func setupAdminTest(t *testing.T, role domain.Role) (*gin.Engine, *mocks.AdminUsersService, *mocks.TasksService, *mocks.AuditService) {
+	gin.SetMode(gin.TestMode)
+
+	usersService := mocks.NewAdminUsersService(t)
+	usersService.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, Role: role}, nil).Maybe()
+	tasksService := mocks.NewTasksService(t)
+	auditService := mocks.NewAuditService(t)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	auth := func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	}
+	NewAdminHandler(r, auth, usersService, tasksService, auditService)
+
+	return r, usersService, tasksService, auditService
+}
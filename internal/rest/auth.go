@@ -4,13 +4,15 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
 	"github.com/krau5/hyper-todo/config"
 	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/metrics"
 	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
 	"github.com/krau5/hyper-todo/internal/utils"
-	"gorm.io/gorm"
 )
 
 //go:generate mockery --name UsersService
@@ -23,66 +25,33 @@ type UsersService interface {
 // AuthHandler handles authentication requests.
 type AuthHandler struct {
 	usersService UsersService
-}
-
-// RegisterBody defines the request body for the /register endpoint.
-type RegisterBody struct {
-	Name     string `json:"name" binding:"required,min=4" example:"John Doe"`          // User's full name
-	Email    string `json:"email" binding:"required,email" example:"john@example.com"` // User's email
-	Password string `json:"password" binding:"required,min=8" example:"password123"`   // User's password
-}
-
-// LoginBody defines the request body for the /login endpoint.
-type LoginBody struct {
-	Email    string `json:"email" binding:"required,email" example:"john@example.com"` // User's email
-	Password string `json:"password" binding:"required,min=8" example:"password123"`   // User's password
+	config       config.AuthConfig
 }
 
 var (
-	ErrUserExists           = appErrors.NewResponseError(http.StatusConflict, "user with this email already exists")
-	ErrUserNotFound         = appErrors.NewResponseError(http.StatusNotFound, "user was not found")
-	ErrInvalidCredentials   = appErrors.NewResponseError(http.StatusBadRequest, "invalid email or password")
-	ErrFailedToRetrieveUser = appErrors.NewResponseError(http.StatusInternalServerError, "failed to retrieve user")
-	ErrFailedToCreateUser   = appErrors.NewResponseError(http.StatusInternalServerError, "failed to create user")
-	ErrFailedToCreateToken  = appErrors.NewResponseError(http.StatusInternalServerError, "failed to create jwt token")
+	ErrUserExists           = appErrors.NewResponseError(http.StatusConflict, "user_exists", "user with this email already exists")
+	ErrUserNotFound         = appErrors.NewResponseError(http.StatusNotFound, "user_not_found", "user was not found")
+	ErrInvalidCredentials   = appErrors.NewResponseError(http.StatusBadRequest, "invalid_credentials", "invalid email or password")
+	ErrFailedToRetrieveUser = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_user", "failed to retrieve user")
+	ErrFailedToCreateUser   = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_create_user", "failed to create user")
+	ErrFailedToCreateToken  = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_create_token", "failed to create jwt token")
 )
 
 // NewAuthHandler registers the auth handler with the Gin engine.
-// @Summary Register a new user
-// @Description Create a new user account
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param body body RegisterBody true "User registration details"
-// @Success 201 "User created successfully"
-// @Failure 400 {object} appErrors.ResponseError "Invalid request body"
-// @Failure 409 {object} appErrors.ResponseError "User with this email already exists"
-// @Failure 500 {object} appErrors.ResponseError "Failed to create user"
-// @Router /register [post]
-func NewAuthHandler(g *gin.Engine, usersService UsersService) {
-	h := &AuthHandler{usersService: usersService}
+func NewAuthHandler(g gin.IRouter, auth gin.HandlerFunc, usersService UsersService, config config.AuthConfig) {
+	h := &AuthHandler{usersService: usersService, config: config}
 
 	g.POST("/register", h.handleRegister)
 	g.POST("/login", h.handleLogin)
+	g.POST("/refresh", auth, h.handleRefresh)
 }
 
 // handleRegister processes user registration requests.
-// @Summary Register a new user
-// @Description Create a new user account
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param body body RegisterBody true "User registration details"
-// @Success 201 "User created successfully"
-// @Failure 400 {object} appErrors.ResponseError "Invalid request body"
-// @Failure 409 {object} appErrors.ResponseError "User with this email already exists"
-// @Failure 500 {object} appErrors.ResponseError "Failed to create user"
-// @Router /register [post]
 func (h *AuthHandler) handleRegister(c *gin.Context) {
-	var data RegisterBody
+	var data api.RegisterBody
 
 	if err := c.ShouldBindJSON(&data); err != nil {
-		c.JSON(appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody)
+		c.Error(err)
 		return
 	}
 
@@ -93,13 +62,13 @@ func (h *AuthHandler) handleRegister(c *gin.Context) {
 		data.Password,
 	)
 
-	if utils.IsErrDuplicatedKey(err) {
-		c.JSON(ErrUserExists.Status, ErrUserExists)
+	if errors.Is(err, domain.ErrDuplicate) {
+		c.Error(ErrUserExists)
 		return
 	}
 
 	if err != nil {
-		c.JSON(ErrFailedToCreateUser.Status, ErrFailedToCreateUser)
+		c.Error(ErrFailedToCreateUser)
 		return
 	}
 
@@ -107,49 +76,108 @@ func (h *AuthHandler) handleRegister(c *gin.Context) {
 }
 
 // handleLogin processes user login requests.
-// @Summary Login a user
-// @Description Authenticate a user and return a JWT token
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param body body LoginBody true "User login credentials"
-// @Success 200 "User logged in successfully"
-// @Failure 400 {object} appErrors.ResponseError "Invalid request body"
-// @Failure 404 {object} appErrors.ResponseError "User not found"
-// @Failure 400 {object} appErrors.ResponseError "Invalid credentials"
-// @Failure 500 {object} appErrors.ResponseError "Failed to retrieve user or create token"
-// @Router /login [post]
 func (h *AuthHandler) handleLogin(c *gin.Context) {
-	var data LoginBody
+	var data api.LoginBody
 
 	if err := c.ShouldBindJSON(&data); err != nil {
-		c.JSON(appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody)
+		metrics.LoginFailed(metrics.LoginInvalidBody)
+		c.Error(err)
 		return
 	}
 
 	user, err := h.usersService.GetByEmail(c.Request.Context(), data.Email)
 
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(ErrUserNotFound.Status, ErrUserNotFound)
+	if errors.Is(err, domain.ErrNotFound) {
+		metrics.LoginFailed(metrics.LoginUnknownEmail)
+		c.Error(ErrUserNotFound)
 		return
 	}
 
 	if err != nil {
-		c.JSON(ErrFailedToRetrieveUser.Status, ErrFailedToRetrieveUser)
+		metrics.LoginFailed(metrics.LoginError)
+		c.Error(ErrFailedToRetrieveUser)
 		return
 	}
 
 	if ok := utils.VerifyPassword(data.Password, user.Password); !ok {
-		c.JSON(ErrInvalidCredentials.Status, ErrInvalidCredentials)
+		metrics.LoginFailed(metrics.LoginWrongPassword)
+		c.Error(ErrInvalidCredentials)
+		return
+	}
+
+	// Locked accounts are only reported to who knows the password.
+	if user.Locked() {
+		metrics.LoginFailed(metrics.LoginLocked)
+		c.Error(appErrors.ErrAccountLocked)
+		return
+	}
+
+	if err := h.issueToken(c, user.ID); err != nil {
+		metrics.LoginFailed(metrics.LoginError)
+		c.Error(ErrFailedToCreateToken)
+		return
+	}
+
+	metrics.LoginSucceeded()
+}
+
+// handleRefresh issues a new token to the current user, before theirs
+// expires, unless their account was locked since.
+func (h *AuthHandler) handleRefresh(c *gin.Context) {
+	userId := c.GetInt64("user-id")
+	user, err := h.usersService.GetById(c.Request.Context(), userId)
+
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
 		return
 	}
 
-	token, err := utils.CreateJwt(user.ID)
 	if err != nil {
-		c.JSON(ErrFailedToCreateToken.Status, ErrFailedToCreateToken)
+		c.Error(ErrFailedToRetrieveUser)
 		return
 	}
 
-	c.SetCookie("token", token, 3600, "/", config.Envs.CookieDomain, false, true)
-	c.Status(http.StatusOK)
+	if user.Locked() {
+		c.Error(appErrors.ErrAccountLocked)
+		return
+	}
+
+	if err := h.issueToken(c, userId); err != nil {
+		c.Error(ErrFailedToCreateToken)
+	}
+}
+
+// issueToken responds with a new token of the user, also set in cookies
+// along with the CSRF token of the session.
+func (h *AuthHandler) issueToken(c *gin.Context, userId int64) error {
+	// Tokens expire at second precision.
+	expiresAt := time.Now().Add(h.config.TokenTTL).Truncate(time.Second)
+	token, err := utils.CreateJwt(userId, h.config.JwtSecretKey.Value(), h.config.TokenTTL)
+	if err != nil {
+		return err
+	}
+
+	h.setCookie(c, "token", token, true)
+	h.setCookie(c, api.CSRFCookie, utils.CSRFToken(token, h.config.JwtSecretKey.Value()), false)
+	c.JSON(http.StatusOK, api.TokenResponse{Token: token, ExpiresAt: expiresAt})
+
+	return nil
+}
+
+// setCookie sets a cookie expiring with the token. The CSRF cookie must be
+// readable by scripts, unlike the token one.
+func (h *AuthHandler) setCookie(c *gin.Context, name, value string, httpOnly bool) {
+	c.SetSameSite(sameSite(h.config.CookieSameSite))
+	c.SetCookie(name, value, int(h.config.TokenTTL.Seconds()), "/", h.config.CookieDomain, h.config.CookieSecure, httpOnly)
+}
+
+func sameSite(mode string) http.SameSite {
+	switch mode {
+	case config.SameSiteStrict:
+		return http.SameSiteStrictMode
+	case config.SameSiteNone:
+		return http.SameSiteNoneMode
+	}
+
+	return http.SameSiteLaxMode
 }
@@ -6,10 +6,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
 	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -17,12 +25,13 @@ import (
 const name = "user"
 const email = "user@example.com"
 const password = "password123"
+const authSecret = "0123456789abcdef0123456789abcdef"
 
 func TestRegisterHandler(t *testing.T) {
 	r, usersService := setupAuthTest(t)
 	usersService.On("Create", mock.Anything, name, email, password).Return(nil)
 
-	body := RegisterBody{
+	body := api.RegisterBody{
 		Name:     name,
 		Email:    email,
 		Password: password,
@@ -41,9 +50,9 @@ func TestRegisterHandler(t *testing.T) {
 
 func TestRegisterHandler_UserExists(t *testing.T) {
 	r, usersService := setupAuthTest(t)
-	usersService.On("Create", mock.Anything, name, email, password).Return(mockDuplicatedError())
+	usersService.On("Create", mock.Anything, name, email, password).Return(domain.ErrDuplicate)
 
-	body := RegisterBody{
+	body := api.RegisterBody{
 		Name:     name,
 		Email:    email,
 		Password: password,
@@ -62,11 +71,213 @@ func TestRegisterHandler_UserExists(t *testing.T) {
 	assert.Equal(t, string(expectedBody), w.Body.String())
 }
 
-func mockDuplicatedError() *pgconn.PgError {
-	return &pgconn.PgError{
-		Code:    "23505",
-		Message: "duplicate key violates unique constraint",
+func TestRegisterHandler_InvalidFields(t *testing.T) {
+	r, _ := setupAuthTest(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBufferString(`{"name":"bob","email":"bob"}`))
+	r.ServeHTTP(w, req)
+
+	var problem appErrors.ResponseError
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, appErrors.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, appErrors.ErrValidation.Code, problem.Code)
+	assert.Equal(t, []appErrors.FieldError{
+		{Field: "name", Code: "min", Message: "must be at least 4 characters long"},
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+		{Field: "password", Code: "required", Message: "is required"},
+	}, problem.Errors)
+}
+
+func TestLoginHandler_UnknownEmail(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	usersService.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrNotFound)
+
+	body := api.LoginBody{
+		Email:    email,
+		Password: password,
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		t.Error(err)
+	}
+
+	before := loginCount(t, "failure", metrics.LoginUnknownEmail)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrUserNotFound)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Equal(t, before+1, loginCount(t, "failure", metrics.LoginUnknownEmail))
+}
+
+func TestLoginHandler_SetsCookies(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usersService.On("GetByEmail", mock.Anything, email).Return(domain.User{ID: 1, Email: email, Password: hash}, nil)
+
+	body, _ := json.Marshal(api.LoginBody{Email: email, Password: password})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response api.TokenResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), response.ExpiresAt, 2*time.Second)
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	token, csrf := cookies["token"], cookies[api.CSRFCookie]
+	if assert.NotNil(t, token) && assert.NotNil(t, csrf) {
+		assert.Equal(t, 7200, token.MaxAge)
+		assert.True(t, token.Secure)
+		assert.True(t, token.HttpOnly)
+		assert.Equal(t, http.SameSiteStrictMode, token.SameSite)
+		assert.Equal(t, "localhost", token.Domain)
+
+		assert.Equal(t, response.Token, token.Value)
+		assert.Equal(t, utils.CSRFToken(token.Value, authSecret), csrf.Value)
+		assert.Equal(t, 7200, csrf.MaxAge)
+		assert.True(t, csrf.Secure)
+		assert.False(t, csrf.HttpOnly)
+		assert.Equal(t, http.SameSiteStrictMode, csrf.SameSite)
+	}
+}
+
+func TestLoginHandler_Locked(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockedAt := time.Now()
+	usersService.On("GetByEmail", mock.Anything, email).Return(domain.User{ID: 1, Email: email, Password: hash, LockedAt: &lockedAt}, nil)
+
+	before := loginCount(t, "failure", metrics.LoginLocked)
+
+	body, _ := json.Marshal(api.LoginBody{Email: email, Password: password})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(appErrors.ErrAccountLocked)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Empty(t, w.Result().Cookies())
+	assert.Equal(t, before+1, loginCount(t, "failure", metrics.LoginLocked))
+}
+
+func TestRefreshHandler(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	usersService.On("GetById", mock.Anything, int64(1)).Return(domain.User{ID: 1, Email: email}, nil)
+	token, err := utils.CreateJwt(1, authSecret, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response api.TokenResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), response.ExpiresAt, 2*time.Second)
+	refreshed, err := utils.VerifyJwt(response.Token, authSecret)
+	if assert.Nil(t, err) {
+		sub, _ := refreshed.Claims.GetSubject()
+		assert.Equal(t, "1", sub)
+	}
+}
+
+func TestRefreshHandler_UserNotFound(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	usersService.On("GetById", mock.Anything, int64(1)).Return(domain.User{}, domain.ErrNotFound)
+	token, err := utils.CreateJwt(1, authSecret, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "token", Value: token})
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrUserNotFound)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestRefreshHandler_Locked(t *testing.T) {
+	r, usersService := setupAuthTest(t)
+	lockedAt := time.Now()
+	usersService.On("GetById", mock.Anything, int64(1)).Return(domain.User{ID: 1, Email: email, LockedAt: &lockedAt}, nil)
+	token, err := utils.CreateJwt(1, authSecret, time.Minute)
+	if err != nil {
+		t.Fatal(err)
 	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(appErrors.ErrAccountLocked)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestRefreshHandler_Unauthenticated(t *testing.T) {
+	r, _ := setupAuthTest(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+}
+
+// loginCount reads the login counter with the given labels from the
+// default registry.
+func loginCount(t *testing.T, result, reason string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "hyper_todo_logins_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["result"] == result && labels["reason"] == reason {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
 }
 
 func setupAuthTest(t *testing.T) (*gin.Engine, *mocks.UsersService) {
@@ -74,7 +285,14 @@ func setupAuthTest(t *testing.T) (*gin.Engine, *mocks.UsersService) {
 
 	usersService := mocks.NewUsersService(t)
 	r := gin.New()
-	NewAuthHandler(r, usersService)
+	r.Use(middleware.ErrorHandler())
+	NewAuthHandler(r, middleware.AuthMiddleware(authSecret, nil), usersService, config.AuthConfig{
+		JwtSecretKey:   authSecret,
+		TokenTTL:       2 * time.Hour,
+		CookieDomain:   "localhost",
+		CookieSecure:   true,
+		CookieSameSite: config.SameSiteStrict,
+	})
 
 	return r, usersService
 }
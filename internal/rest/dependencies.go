@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/task"
+)
+
+//go:generate mockery --name DependenciesService
+type DependenciesService interface {
+	GetDependencies(context.Context, int64) (domain.TaskDependencies, error)
+	AddBlocker(ctx context.Context, taskId, blockerId int64) error
+	RemoveBlocker(ctx context.Context, taskId, blockerId int64) error
+}
+
+// DependenciesHandler handles task dependency requests.
+type DependenciesHandler struct {
+	tasksService        TasksService
+	dependenciesService DependenciesService
+}
+
+var (
+	ErrInvalidBlockerId             = appErrors.NewResponseError(http.StatusBadRequest, "invalid_blocker_id", "blocker id is missing or invalid")
+	ErrSelfDependency               = appErrors.NewResponseError(http.StatusBadRequest, "self_dependency", task.ErrSelfDependency.Error())
+	ErrBlockerNotFound              = appErrors.NewResponseError(http.StatusNotFound, "blocker_not_found", "blocker task was not found")
+	ErrDependencyCycle              = appErrors.NewResponseError(http.StatusConflict, "dependency_cycle", task.ErrDependencyCycle.Error())
+	ErrFailedToRetrieveDependencies = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_dependencies", "failed to retrieve dependencies")
+	ErrFailedToUpdateDependencies   = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_dependencies", "failed to update dependencies")
+)
+
+// NewDependenciesHandler registers the dependencies handler with the Gin engine.
+func NewDependenciesHandler(r gin.IRouter, auth gin.HandlerFunc, tasksService TasksService, dependenciesService DependenciesService) {
+	h := &DependenciesHandler{
+		tasksService:        tasksService,
+		dependenciesService: dependenciesService,
+	}
+
+	r.GET("/tasks/:taskId/dependencies", auth, h.handleGetDependencies)
+	r.PUT("/tasks/:taskId/blockers/:blockerId", auth, h.handleAddBlocker)
+	r.DELETE("/tasks/:taskId/blockers/:blockerId", auth, h.handleRemoveBlocker)
+}
+
+// handleGetDependencies retrieves the tasks blocking a task and the tasks it blocks.
+func (h *DependenciesHandler) handleGetDependencies(c *gin.Context) {
+	taskId, ok := h.getOwnTaskId(c)
+	if !ok {
+		return
+	}
+
+	h.respondWithDependencies(c, taskId)
+}
+
+// handleAddBlocker marks a task as blocked by another task.
+func (h *DependenciesHandler) handleAddBlocker(c *gin.Context) {
+	taskId, ok := h.getOwnTaskId(c)
+	if !ok {
+		return
+	}
+
+	blockerId, err := strconv.ParseInt(c.Param("blockerId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidBlockerId)
+		return
+	}
+
+	err = h.dependenciesService.AddBlocker(c.Request.Context(), taskId, blockerId)
+	switch {
+	case errors.Is(err, task.ErrSelfDependency):
+		c.Error(ErrSelfDependency)
+		return
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, task.ErrForeignDependency):
+		c.Error(ErrBlockerNotFound)
+		return
+	case errors.Is(err, task.ErrDependencyCycle):
+		c.Error(ErrDependencyCycle)
+		return
+	case err != nil:
+		c.Error(ErrFailedToUpdateDependencies)
+		return
+	}
+
+	h.respondWithDependencies(c, taskId)
+}
+
+// handleRemoveBlocker removes a blocker from a task.
+func (h *DependenciesHandler) handleRemoveBlocker(c *gin.Context) {
+	taskId, ok := h.getOwnTaskId(c)
+	if !ok {
+		return
+	}
+
+	blockerId, err := strconv.ParseInt(c.Param("blockerId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidBlockerId)
+		return
+	}
+
+	if err := h.dependenciesService.RemoveBlocker(c.Request.Context(), taskId, blockerId); err != nil {
+		c.Error(ErrFailedToUpdateDependencies)
+		return
+	}
+
+	h.respondWithDependencies(c, taskId)
+}
+
+func (h *DependenciesHandler) respondWithDependencies(c *gin.Context, taskId int64) {
+	dependencies, err := h.dependenciesService.GetDependencies(c.Request.Context(), taskId)
+	if err != nil {
+		c.Error(ErrFailedToRetrieveDependencies)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// getOwnTaskId parses the task ID from the path and writes an error
+// response unless the task belongs to the authenticated user.
+func (h *DependenciesHandler) getOwnTaskId(c *gin.Context) (int64, bool) {
+	taskId, err := strconv.ParseInt(c.Param("taskId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidTaskId)
+		return 0, false
+	}
+
+	t, err := h.tasksService.GetById(c.Request.Context(), taskId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && t.UserId != c.GetInt64("user-id")) {
+		c.Error(ErrTaskNotFound)
+		return 0, false
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveTask)
+		return 0, false
+	}
+
+	return taskId, true
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/krau5/hyper-todo/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddBlockerHandler(t *testing.T) {
+	var blockerId int64 = 2
+	dependencies := domain.TaskDependencies{
+		BlockedBy: []domain.Task{{ID: blockerId, Name: "buy", Description: "buy the pizza"}},
+		Blocks:    []domain.Task{},
+	}
+
+	r, tasksService, dependenciesService := setupDependenciesTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId}, nil)
+	dependenciesService.On("AddBlocker", mock.Anything, taskId, blockerId).Return(nil)
+	dependenciesService.On("GetDependencies", mock.Anything, taskId).Return(dependencies, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/tasks/%v/blockers/%v", taskId, blockerId), nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(dependencies)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestAddBlockerHandler_Cycle(t *testing.T) {
+	var blockerId int64 = 2
+
+	r, tasksService, dependenciesService := setupDependenciesTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId}, nil)
+	dependenciesService.On("AddBlocker", mock.Anything, taskId, blockerId).Return(task.ErrDependencyCycle)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/tasks/%v/blockers/%v", taskId, blockerId), nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrDependencyCycle)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestAddBlockerHandler_ForeignBlocker(t *testing.T) {
+	var blockerId int64 = 2
+
+	r, tasksService, dependenciesService := setupDependenciesTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId}, nil)
+	dependenciesService.On("AddBlocker", mock.Anything, taskId, blockerId).Return(task.ErrForeignDependency)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/tasks/%v/blockers/%v", taskId, blockerId), nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrBlockerNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestGetDependenciesHandler_ForeignTask(t *testing.T) {
+	r, tasksService, _ := setupDependenciesTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId + 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/tasks/%v/dependencies", taskId), nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrTaskNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func setupDependenciesTest(t *testing.T) (*gin.Engine, *mocks.TasksService, *mocks.DependenciesService) {
+	gin.SetMode(gin.TestMode)
+
+	tasksService := mocks.NewTasksService(t)
+	dependenciesService := mocks.NewDependenciesService(t)
+	h := &DependenciesHandler{tasksService: tasksService, dependenciesService: dependenciesService}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/tasks/:taskId/dependencies", h.handleGetDependencies)
+	r.PUT("/tasks/:taskId/blockers/:blockerId", h.handleAddBlocker)
+	r.DELETE("/tasks/:taskId/blockers/:blockerId", h.handleRemoveBlocker)
+
+	return r, tasksService, dependenciesService
+}
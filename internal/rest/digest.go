@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/digest"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+)
+
+//go:generate mockery --name DigestService
+type DigestService interface {
+	Preview(context.Context, int64) (digest.Rendered, error)
+}
+
+// DigestHandler handles daily digest requests.
+type DigestHandler struct {
+	digestService DigestService
+}
+
+var (
+	ErrInvalidDigestFormat = appErrors.NewResponseError(http.StatusBadRequest, "invalid_digest_format", "format must be either html or text")
+	ErrFailedToBuildDigest = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_build_digest", "failed to build digest")
+)
+
+// NewDigestHandler registers the digest handler with the Gin engine.
+func NewDigestHandler(r gin.IRouter, auth gin.HandlerFunc, digestService DigestService) {
+	h := &DigestHandler{digestService: digestService}
+
+	r.GET("/me/digest/preview", auth, h.handlePreviewDigest)
+}
+
+// handlePreviewDigest renders the daily digest the authenticated user would receive now.
+func (h *DigestHandler) handlePreviewDigest(c *gin.Context) {
+	format := c.DefaultQuery("format", "html")
+	if format != "html" && format != "text" {
+		c.Error(ErrInvalidDigestFormat)
+		return
+	}
+
+	rendered, err := h.digestService.Preview(c.Request.Context(), c.GetInt64("user-id"))
+	if err != nil {
+		c.Error(ErrFailedToBuildDigest)
+		return
+	}
+
+	if format == "text" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(rendered.Text))
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/digest"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPreviewDigestHandler(t *testing.T) {
+	rendered := digest.Rendered{Subject: "subject", Text: "text digest", HTML: "<p>html digest</p>"}
+
+	t.Run("html", func(t *testing.T) {
+		r, digestService := setupDigestTest(t)
+		digestService.On("Preview", mock.Anything, userId).Return(rendered, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me/digest/preview", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, rendered.HTML, w.Body.String())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		r, digestService := setupDigestTest(t)
+		digestService.On("Preview", mock.Anything, userId).Return(rendered, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me/digest/preview?format=text", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, rendered.Text, w.Body.String())
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		r, _ := setupDigestTest(t)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me/digest/preview?format=pdf", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		r, digestService := setupDigestTest(t)
+		digestService.On("Preview", mock.Anything, userId).Return(digest.Rendered{}, errors.New("boom"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me/digest/preview", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func setupDigestTest(t *testing.T) (*gin.Engine, *mocks.DigestService) {
+	gin.SetMode(gin.TestMode)
+
+	digestService := mocks.NewDigestService(t)
+	h := &DigestHandler{digestService: digestService}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/me/digest/preview", h.handlePreviewDigest)
+
+	return r, digestService
+}
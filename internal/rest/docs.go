@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion is the major version of Swagger UI, the first rendering
+// OpenAPI 3.1 documents.
+const swaggerUIVersion = "5"
+
+// swaggerPage renders the document with Swagger UI, loaded from a CDN.
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Hyper Todo API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "/openapi.yaml", dom_id: "#swagger-ui", defaultModelsExpandDepth: -1});
+    };
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves the OpenAPI document of the API and its Swagger UI.
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler registers the docs handler with the Gin engine.
+func NewDocsHandler(r gin.IRouter, spec []byte) {
+	h := &DocsHandler{spec: spec}
+
+	r.GET("/openapi.yaml", h.handleSpec)
+	r.GET("/swagger", h.handleSwaggerUI)
+}
+
+// handleSpec responds with the OpenAPI document.
+func (h *DocsHandler) handleSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml", h.spec)
+}
+
+// handleSwaggerUI responds with a page browsing the OpenAPI document.
+func (h *DocsHandler) handleSwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+}
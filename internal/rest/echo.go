@@ -15,20 +15,13 @@ type PingResponse struct {
 }
 
 // NewPingHandler registers the ping handler with the Gin engine.
-func NewPingHandler(g *gin.Engine) {
+func NewPingHandler(g gin.IRouter) {
 	h := &PingHandler{}
 
 	g.GET("/ping", h.handlePing)
 }
 
 // handlePing responds with a "pong" message.
-// @Summary Ping the server
-// @Description Get a "pong" response from the server
-// @Tags ping
-// @Accept json
-// @Produce json
-// @Success 200 {object} PingResponse "Returns a pong message"
-// @Router /ping [get]
 func (h *PingHandler) handlePing(c *gin.Context) {
 	c.JSON(http.StatusOK, PingResponse{Message: "pong"})
 }
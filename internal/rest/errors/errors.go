@@ -1,25 +1,171 @@
 package errors
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
 )
 
-// ResponseError defines a standard error response.
-// @name ResponseError
-type ResponseError struct {
-	Status  int    `json:"status"`
-	Message string `json:"message"`
-}
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = api.ProblemContentType
 
-func (e *ResponseError) Error() string {
-	return fmt.Sprintf("[%d] %s", e.Status, e.Message)
-}
+// ResponseError and FieldError are the problem details the API responds
+// with, shared with its clients.
+type (
+	ResponseError = api.ResponseError
+	FieldError    = api.FieldError
+)
 
-func NewResponseError(status int, message string) *ResponseError {
-	return &ResponseError{Status: status, Message: message}
+func NewResponseError(status int, code, detail string) *ResponseError {
+	return api.NewResponseError(status, code, detail)
 }
 
 var (
-	ErrInvalidBody = NewResponseError(http.StatusBadRequest, "invalid request body")
+	ErrInvalidBody      = NewResponseError(http.StatusBadRequest, "invalid_body", "invalid request body")
+	ErrValidation       = NewResponseError(http.StatusBadRequest, "validation_failed", "request body failed validation")
+	ErrForbidden        = NewResponseError(http.StatusForbidden, "forbidden", "access to the resource is forbidden")
+	ErrNotFound         = NewResponseError(http.StatusNotFound, "not_found", "resource was not found")
+	ErrRouteNotFound    = NewResponseError(http.StatusNotFound, "route_not_found", "no route matches the request")
+	ErrMethodNotAllowed = NewResponseError(http.StatusMethodNotAllowed, "method_not_allowed", "method is not allowed on this route")
+	ErrConflict         = NewResponseError(http.StatusConflict, "conflict", "resource already exists")
+	ErrAccountLocked    = NewResponseError(http.StatusForbidden, "account_locked", domain.ErrAccountLocked.Error())
+	ErrSessionRevoked   = NewResponseError(http.StatusUnauthorized, "session_revoked", domain.ErrSessionRevoked.Error())
+	ErrInternal         = NewResponseError(http.StatusInternalServerError, "internal_error", "internal server error")
 )
+
+func init() {
+	// Report fields by their JSON names rather than Go struct field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonName)
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+
+	return name
+}
+
+// FromError maps an error to the response describing it. Errors not known
+// to the API are reported as internal errors without leaking their message.
+func FromError(err error) *ResponseError {
+	var (
+		respErr        *ResponseError
+		validationErrs validator.ValidationErrors
+		syntaxErr      *json.SyntaxError
+		typeErr        *json.UnmarshalTypeError
+		quotaErr       *domain.QuotaError
+	)
+
+	switch {
+	case errors.As(err, &respErr):
+		return respErr
+	case errors.As(err, &validationErrs):
+		return withFieldErrors(validationErrs)
+	case errors.As(err, &typeErr):
+		e := *ErrValidation
+		e.Errors = []FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonType(typeErr.Type)),
+		}}
+		return &e
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrInvalidBody
+	case errors.Is(err, domain.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, domain.ErrDuplicate):
+		return ErrConflict
+	case errors.Is(err, domain.ErrAccountLocked):
+		return ErrAccountLocked
+	case errors.Is(err, domain.ErrSessionRevoked):
+		return ErrSessionRevoked
+	case errors.As(err, &quotaErr):
+		return quotaError(quotaErr)
+	}
+
+	return ErrInternal
+}
+
+// quotaError reports quotas which are renewed, such as daily API calls, as
+// 429 and the others as 403 since retrying won't help.
+func quotaError(err *domain.QuotaError) *ResponseError {
+	status := http.StatusForbidden
+	if !err.ResetsAt.IsZero() {
+		status = http.StatusTooManyRequests
+	}
+
+	return NewResponseError(status, "quota_exceeded", err.Error())
+}
+
+func withFieldErrors(errs validator.ValidationErrors) *ResponseError {
+	e := *ErrValidation
+	e.Errors = make([]FieldError, len(errs))
+	for i, fe := range errs {
+		field := fe.Namespace()
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest
+		}
+
+		e.Errors[i] = FieldError{Field: field, Code: fe.Tag(), Message: fieldMessage(fe)}
+	}
+
+	return &e
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	}
+
+	return fmt.Sprintf("failed on the %q rule", fe.Tag())
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+
+	return "object"
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type body struct {
+	Name  string `json:"name" binding:"required,min=4"`
+	Email string `json:"email" binding:"required,email"`
+	Age   int    `json:"age" binding:"max=150"`
+}
+
+func bind(raw string) error {
+	var b body
+	return binding.JSON.BindBody([]byte(raw), &b)
+}
+
+func TestNewResponseError(t *testing.T) {
+	err := NewResponseError(http.StatusNotFound, "task_not_found", "task was not found")
+
+	data, _ := json.Marshal(err)
+	assert.JSONEq(t, `{
+		"type": "urn:hyper-todo:problem:task_not_found",
+		"title": "Not Found",
+		"status": 404,
+		"code": "task_not_found",
+		"detail": "task was not found"
+	}`, string(data))
+
+	withInstance := err.WithInstance("urn:hyper-todo:request:1")
+	assert.Equal(t, "urn:hyper-todo:request:1", withInstance.Instance)
+	assert.Empty(t, err.Instance)
+}
+
+func TestFromError(t *testing.T) {
+	custom := NewResponseError(http.StatusConflict, "task_blocked", "task is blocked")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected *ResponseError
+	}{
+		{"response error", fmt.Errorf("wrapped: %w", custom), custom},
+		{"not found", fmt.Errorf("get: %w", domain.ErrNotFound), ErrNotFound},
+		{"duplicate", domain.ErrDuplicate, ErrConflict},
+		{"locked account", domain.ErrAccountLocked, ErrAccountLocked},
+		{"revoked session", domain.ErrSessionRevoked, ErrSessionRevoked},
+		{
+			"exceeded quota",
+			&domain.QuotaError{Resource: domain.QuotaTasks, Limit: 10},
+			NewResponseError(http.StatusForbidden, "quota_exceeded", "tasks quota of 10 exceeded"),
+		},
+		{
+			"exceeded daily quota",
+			fmt.Errorf("call: %w", &domain.QuotaError{Resource: domain.QuotaAPICalls, Limit: 10, ResetsAt: time.Now()}),
+			NewResponseError(http.StatusTooManyRequests, "quota_exceeded", "api_calls quota of 10 exceeded"),
+		},
+		{"empty body", io.EOF, ErrInvalidBody},
+		{"malformed body", bind(`{"name":`), ErrInvalidBody},
+		{"unknown error", fmt.Errorf("connection refused"), ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FromError(tt.err))
+		})
+	}
+}
+
+func TestFromError_Validation(t *testing.T) {
+	t.Run("reports every invalid field by its JSON name", func(t *testing.T) {
+		err := FromError(bind(`{"name":"bob","email":"bob","age":200}`))
+
+		assert.Equal(t, http.StatusBadRequest, err.Status)
+		assert.Equal(t, "validation_failed", err.Code)
+		assert.Equal(t, []FieldError{
+			{Field: "name", Code: "min", Message: "must be at least 4 characters long"},
+			{Field: "email", Code: "email", Message: "must be a valid email address"},
+			{Field: "age", Code: "max", Message: "must be at most 150"},
+		}, err.Errors)
+	})
+
+	t.Run("reports fields of the wrong type", func(t *testing.T) {
+		err := FromError(bind(`{"name":"alice","email":"alice@example.com","age":"old"}`))
+
+		assert.Equal(t, "validation_failed", err.Code)
+		assert.Equal(t, []FieldError{{Field: "age", Code: "type", Message: "must be of type number"}}, err.Errors)
+	})
+
+	t.Run("does not modify the shared error", func(t *testing.T) {
+		FromError(bind(`{}`))
+		assert.Empty(t, ErrValidation.Errors)
+	})
+}
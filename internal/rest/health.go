@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/health"
+)
+
+//go:generate mockery --name ReadinessChecker
+type ReadinessChecker interface {
+	Check(context.Context) health.Report
+}
+
+// HealthHandler handles liveness and readiness probes.
+type HealthHandler struct {
+	readiness ReadinessChecker
+}
+
+// NewHealthHandler registers the health handler with the Gin engine.
+func NewHealthHandler(r gin.IRouter, readiness ReadinessChecker) {
+	h := &HealthHandler{readiness: readiness}
+
+	r.GET("/healthz", h.handleLiveness)
+	r.GET("/readyz", h.handleReadiness)
+}
+
+// handleLiveness reports that the process is running.
+func (h *HealthHandler) handleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusOK, Checks: map[string]health.Result{}})
+}
+
+// handleReadiness reports whether the instance can serve traffic.
+func (h *HealthHandler) handleReadiness(c *gin.Context) {
+	report := h.readiness.Check(c.Request.Context())
+	if !report.OK() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
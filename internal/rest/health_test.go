@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/health"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	r, _ := setupHealthTest(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"status":"ok","checks":{}}`, w.Body.String())
+}
+
+func TestReadinessHandler(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		report := health.Report{
+			Status: health.StatusOK,
+			Checks: map[string]health.Result{"database": {Status: health.StatusOK, DurationMs: 2}},
+		}
+
+		r, readiness := setupHealthTest(t)
+		readiness.On("Check", mock.Anything).Return(report)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		r.ServeHTTP(w, req)
+
+		expectedBody, _ := json.Marshal(report)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, string(expectedBody), w.Body.String())
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		report := health.Report{
+			Status: health.StatusFail,
+			Checks: map[string]health.Result{
+				"database": {Status: health.StatusOK, DurationMs: 2},
+				"shutdown": {Status: health.StatusFail, Error: health.ErrShuttingDown.Error()},
+			},
+		}
+
+		r, readiness := setupHealthTest(t)
+		readiness.On("Check", mock.Anything).Return(report)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		r.ServeHTTP(w, req)
+
+		expectedBody, _ := json.Marshal(report)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, string(expectedBody), w.Body.String())
+	})
+}
+
+func setupHealthTest(t *testing.T) (*gin.Engine, *mocks.ReadinessChecker) {
+	gin.SetMode(gin.TestMode)
+
+	readiness := mocks.NewReadinessChecker(t)
+	r := gin.New()
+	NewHealthHandler(r, readiness)
+
+	return r, readiness
+}
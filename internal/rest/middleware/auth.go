@@ -1,53 +1,149 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/logging"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
 	"github.com/krau5/hyper-todo/internal/utils"
+	"go.uber.org/zap"
 )
 
 var (
-	errMissingToken   = errors.NewResponseError(http.StatusUnauthorized, "missing or invalid token")
-	errInvalidToken   = errors.NewResponseError(http.StatusUnauthorized, "invalid token")
-	errExtractSubject = errors.NewResponseError(http.StatusBadRequest, "failed to extract subject from token")
-	errParseUserID    = errors.NewResponseError(http.StatusBadRequest, "failed to parse user ID from token")
+	errMissingToken   = appErrors.NewResponseError(http.StatusUnauthorized, "missing_token", "missing or invalid token")
+	errInvalidToken   = appErrors.NewResponseError(http.StatusUnauthorized, "invalid_token", "invalid token")
+	errExtractSubject = appErrors.NewResponseError(http.StatusBadRequest, "invalid_token_subject", "failed to extract subject from token")
+	errParseUserID    = appErrors.NewResponseError(http.StatusBadRequest, "invalid_token_user_id", "failed to parse user ID from token")
 )
 
-func validateToken(c *gin.Context) (int64, *errors.ResponseError) {
-	tokenString, err := c.Cookie("token")
-	if err != nil {
-		return 0, errMissingToken
+// bearerToken returns the token of the Authorization header, if any.
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || len(token) == 0 {
+		return "", false
+	}
+
+	return token, true
+}
+
+// SessionChecker checks the session of a token is still valid, failing with
+// domain.ErrAccountLocked or domain.ErrSessionRevoked otherwise.
+type SessionChecker interface {
+	CheckSession(ctx context.Context, userId int64, issuedAt time.Time) error
+}
+
+// RoleChecker finds the user whose role is checked.
+type RoleChecker interface {
+	GetById(context.Context, int64) (domain.User, error)
+}
+
+// session is the user authenticated by a valid token.
+type session struct {
+	userId   int64
+	issuedAt time.Time
+}
+
+// parseToken returns the session of the bearer token or, without one, of the
+// token cookie.
+func parseToken(c *gin.Context, secret string) (session, *appErrors.ResponseError) {
+	tokenString, ok := bearerToken(c)
+	if !ok {
+		var err error
+		if tokenString, err = c.Cookie("token"); err != nil {
+			return session{}, errMissingToken
+		}
 	}
 
-	token, err := utils.VerifyJwt(tokenString)
+	token, err := utils.VerifyJwt(tokenString, secret)
 	if err != nil {
-		return 0, errInvalidToken
+		return session{}, errInvalidToken
 	}
 
 	sub, err := token.Claims.GetSubject()
 	if err != nil {
-		return 0, errExtractSubject
+		return session{}, errExtractSubject
 	}
 
 	userId, err := strconv.ParseInt(sub, 10, 64)
 	if err != nil {
-		return 0, errParseUserID
+		return session{}, errParseUserID
 	}
 
-	return userId, nil
+	var issuedAt time.Time
+	if iat, err := token.Claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+
+	return session{userId: userId, issuedAt: issuedAt}, nil
 }
 
-func AuthMiddleware(c *gin.Context) {
-	userId, err := validateToken(c)
-	if err != nil {
-		c.JSON(err.Status, err)
-		c.Abort()
-		return
+// validateToken returns the ID of the user authenticated by the bearer token
+// or, without one, by the token cookie.
+func validateToken(c *gin.Context, secret string) (int64, *appErrors.ResponseError) {
+	s, err := parseToken(c, secret)
+	return s.userId, err
+}
+
+// AuthMiddleware returns a middleware rejecting requests without a valid
+// bearer token or token cookie signed with the secret, and, unless sessions
+// is nil, those of locked accounts or of revoked sessions. The ID of the
+// authenticated user is stored in the "user-id" context key and added to the
+// request logger, and the time the token was issued at is stored in the
+// "token-issued-at" key.
+func AuthMiddleware(secret string, sessions SessionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, err := parseToken(c, secret)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		ctx := logging.With(c.Request.Context(), zap.Int64("user_id", s.userId))
+		if sessions != nil {
+			// Users missing despite a valid token are reported by the
+			// handlers.
+			err := sessions.CheckSession(ctx, s.userId, s.issuedAt)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("user-id", s.userId)
+		c.Set("token-issued-at", s.issuedAt)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
 	}
+}
+
+// RequireRole returns a middleware rejecting requests of authenticated users
+// without one of the roles. It must follow AuthMiddleware.
+func RequireRole(users RoleChecker, roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := users.GetById(c.Request.Context(), c.GetInt64("user-id"))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
 
-	c.Set("user-id", userId)
-	c.Next()
+		if !slices.Contains(roles, user.Role) {
+			logging.FromContext(c.Request.Context()).Info("Role is not allowed", zap.String("role", string(user.Role)))
+			c.Error(appErrors.ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
 }
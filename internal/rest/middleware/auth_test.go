@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	token, err := utils.CreateJwt(42, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := utils.CreateJwt(7, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/me", AuthMiddleware(secret, nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, c.GetInt64("user-id"))
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		cookie        string
+		status        int
+		body          string
+		code          string
+	}{
+		{name: "cookie", cookie: token, status: http.StatusOK, body: "42"},
+		{name: "bearer", authorization: "Bearer " + token, status: http.StatusOK, body: "42"},
+		{name: "lowercase scheme", authorization: "bearer " + token, status: http.StatusOK, body: "42"},
+		{name: "bearer over cookie", authorization: "Bearer " + token, cookie: other, status: http.StatusOK, body: "42"},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", cookie: token, status: http.StatusOK, body: "42"},
+		{name: "missing", status: http.StatusUnauthorized, code: "missing_token"},
+		{name: "invalid bearer", authorization: "Bearer invalid", cookie: token, status: http.StatusUnauthorized, code: "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/me", nil)
+			if len(tt.authorization) != 0 {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if len(tt.cookie) != 0 {
+				req.AddCookie(&http.Cookie{Name: "token", Value: tt.cookie})
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if len(tt.code) == 0 {
+				assert.Equal(t, tt.body, w.Body.String())
+				return
+			}
+
+			var problem errors.ResponseError
+			assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.code, problem.Code)
+		})
+	}
+}
+
+// fakeUsers holds users by ID, checking sessions as user.Service does for
+// locked accounts.
+type fakeUsers map[int64]domain.User
+
+func (u fakeUsers) GetById(_ context.Context, id int64) (domain.User, error) {
+	user, ok := u[id]
+	if !ok {
+		return domain.User{}, domain.ErrNotFound
+	}
+
+	return user, nil
+}
+
+func (u fakeUsers) CheckSession(ctx context.Context, userId int64, _ time.Time) error {
+	user, err := u.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.Locked() {
+		return domain.ErrAccountLocked
+	}
+
+	return nil
+}
+
+func TestAuthMiddleware_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	lockedAt := time.Now()
+	users := fakeUsers{
+		1: {ID: 1},
+		2: {ID: 2, LockedAt: &lockedAt},
+	}
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/me", AuthMiddleware(secret, users), func(c *gin.Context) {
+		c.JSON(http.StatusOK, c.GetInt64("user-id"))
+	})
+
+	tests := []struct {
+		name   string
+		userId int64
+		status int
+		code   string
+	}{
+		{"active user", 1, http.StatusOK, ""},
+		{"locked user", 2, http.StatusForbidden, "account_locked"},
+		{"missing user", 3, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.CreateJwt(tt.userId, secret, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if len(tt.code) != 0 {
+				var problem errors.ResponseError
+				assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.code, problem.Code)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	users := fakeUsers{
+		1: {ID: 1, Role: domain.RoleUser},
+		2: {ID: 2, Role: domain.RoleSupport},
+		3: {ID: 3, Role: domain.RoleAdmin},
+	}
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/admin", AuthMiddleware(secret, users), RequireRole(users, domain.RoleSupport, domain.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		userId int64
+		status int
+	}{
+		{"user", 1, http.StatusForbidden},
+		{"support", 2, http.StatusNoContent},
+		{"admin", 3, http.StatusNoContent},
+		{"missing user", 4, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.CreateJwt(tt.userId, secret, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+)
+
+var (
+	corsMethods = strings.Join([]string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}, ", ")
+	corsHeaders = strings.Join([]string{
+		"Content-Type", api.CSRFHeader, RequestIDHeader, "traceparent", "tracestate",
+	}, ", ")
+	corsExposedHeaders = strings.Join([]string{
+		RequestIDHeader, "Retry-After",
+		RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, RateLimitPolicyHeader,
+	}, ", ")
+)
+
+// CORS returns a middleware letting browsers call the API from the allowed
+// origins, or any origin if they include "*". Preflight requests from these
+// origins are answered right away and may be cached for maxAge. Requests
+// from other origins get no CORS headers, so browsers don't expose the
+// responses to their scripts.
+func CORS(origins []string, allowCredentials bool, maxAge time.Duration) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if len(origin) == 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !allowed[origin] && !allowed["*"] {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", origin)
+		if allowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if c.Request.Method == http.MethodOptions && len(c.GetHeader("Access-Control-Request-Method")) != 0 {
+			c.Header("Access-Control-Allow-Methods", corsMethods)
+			c.Header("Access-Control-Allow-Headers", corsHeaders)
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Header("Access-Control-Expose-Headers", corsExposedHeaders)
+		c.Next()
+	}
+}
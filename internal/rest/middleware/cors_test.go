@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.Use(CORS([]string{"https://app.example.com"}, true, 10*time.Minute))
+	r.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/tasks", nil)
+		if len(origin) != 0 {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", "POST")
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("answers preflight requests", func(t *testing.T) {
+		w := serve("OPTIONS", "https://app.example.com", true)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), api.CSRFHeader)
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("exposes headers of allowed origins", func(t *testing.T) {
+		w := serve("GET", "https://app.example.com", false)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), RequestIDHeader)
+	})
+
+	t.Run("ignores other origins", func(t *testing.T) {
+		w := serve("OPTIONS", "https://evil.example.com", true)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+		w = serve("GET", "https://evil.example.com", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("ignores same-origin requests", func(t *testing.T) {
+		w := serve("GET", "", false)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+}
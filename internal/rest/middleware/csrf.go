@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/utils"
+)
+
+var errCSRFTokenMismatch = errors.NewResponseError(http.StatusForbidden, "csrf_token_mismatch", "missing or invalid CSRF token")
+
+// CSRF returns a middleware protecting requests authenticated with a valid
+// token cookie signed with the secret against cross-site request forgery.
+// State-changing requests must send the CSRF token of the session, set in the
+// api.CSRFCookie at login, in the api.CSRFHeader: other sites can make
+// browsers send the cookies but can't read them. Requests without a valid
+// token don't act on behalf of a user and are let through, as are those
+// authenticated with a bearer token, which browsers don't send by themselves.
+func CSRF(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, bearer := bearerToken(c); bearer || safeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		if _, err := validateToken(c, secret); err != nil {
+			c.Next()
+			return
+		}
+
+		token, _ := c.Cookie("token")
+		expected := utils.CSRFToken(token, secret)
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(api.CSRFHeader)), []byte(expected)) != 1 {
+			c.Error(errCSRFTokenMismatch)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	token, err := utils.CreateJwt(42, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrf := utils.CSRFToken(token, secret)
+
+	r := gin.New()
+	r.Use(ErrorHandler(), CSRF(secret))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/tasks", ok)
+	r.POST("/tasks", ok)
+
+	serve := func(method, cookie, header string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/tasks", nil)
+		if len(cookie) != 0 {
+			req.AddCookie(&http.Cookie{Name: "token", Value: cookie})
+		}
+		if len(header) != 0 {
+			req.Header.Set(api.CSRFHeader, header)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("GET", token, "").Code)
+	assert.Equal(t, http.StatusOK, serve("POST", token, csrf).Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "", "").Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "expired", "").Code)
+
+	// Browsers don't send bearer tokens by themselves.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.AddCookie(&http.Cookie{Name: "token", Value: token})
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, header := range []string{"", utils.CSRFToken("other", secret)} {
+		w := serve("POST", token, header)
+		var problem errors.ResponseError
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "csrf_token_mismatch", problem.Code)
+	}
+}
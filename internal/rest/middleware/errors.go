@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+)
+
+// InstancePrefix prefixes the request ID to build the instance URI of a
+// problem.
+const InstancePrefix = "urn:hyper-todo:request:"
+
+// ErrorHandler returns a middleware rendering the last error attached to the
+// context with c.Error as an RFC 7807 problem, unless a response has
+// already been written.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		AbortWithProblem(c, errors.FromError(c.Errors.Last().Err))
+	}
+}
+
+// AbortWithProblem writes err as an RFC 7807 problem tied to the current
+// request and aborts the chain.
+func AbortWithProblem(c *gin.Context, err *errors.ResponseError) {
+	if id := c.GetString("request-id"); id != "" {
+		err = err.WithInstance(InstancePrefix + id)
+	}
+
+	body, _ := json.Marshal(err)
+	c.Abort()
+	c.Data(err.Status, errors.ProblemContentType, body)
+}
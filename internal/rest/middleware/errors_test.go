@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupErrorsTest() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RequestID(), ErrorHandler())
+	r.GET("/missing", func(c *gin.Context) { c.Error(domain.ErrNotFound) })
+	r.GET("/written", func(c *gin.Context) {
+		c.Error(domain.ErrNotFound)
+		c.String(http.StatusTeapot, "already written")
+	})
+	r.GET("/protected", AuthMiddleware("0123456789abcdef0123456789abcdef", nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return r
+}
+
+func TestErrorHandler(t *testing.T) {
+	r := setupErrorsTest()
+
+	t.Run("renders errors as problems tied to the request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/missing", nil)
+		r.ServeHTTP(w, req)
+
+		var problem errors.ResponseError
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.Len(t, id, 32)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, errors.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, errors.ErrNotFound.WithInstance(InstancePrefix+id), &problem)
+	})
+
+	t.Run("keeps valid request IDs sent by the client", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		req.Header.Set(RequestIDHeader, "client-id")
+		r.ServeHTTP(w, req)
+
+		var problem errors.ResponseError
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "client-id", w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "missing_token", problem.Code)
+		assert.Equal(t, InstancePrefix+"client-id", problem.Instance)
+	})
+
+	t.Run("replaces invalid request IDs", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/missing", nil)
+		req.Header.Set(RequestIDHeader, strings.Repeat("a", maxRequestIDLength+1))
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+	})
+
+	t.Run("leaves written responses alone", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/written", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Equal(t, "already written", w.Body.String())
+	})
+}
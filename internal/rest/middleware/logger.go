@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Logger returns a middleware attaching logger to the request context,
+// annotated with the request ID, the matched route and the trace ID. It must
+// run after RequestID and Tracing. Services and repositories retrieve it
+// with logging.FromContext.
+func Logger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		requestLogger := logger.With(
+			zap.String("request_id", logging.RequestID(ctx)),
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+		)
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			requestLogger = requestLogger.With(zap.String("trace_id", sc.TraceID().String()))
+		}
+
+		c.Request = c.Request.WithContext(logging.NewContext(ctx, requestLogger))
+		c.Next()
+	}
+}
+
+// AccessLogFields returns the fields correlating an access log entry with
+// the entries logged while serving the request.
+func AccessLogFields(c *gin.Context) []zap.Field {
+	fields := []zap.Field{zap.String("request_id", c.GetString("request-id"))}
+	if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+		fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+	}
+	if _, ok := c.Get("user-id"); ok {
+		fields = append(fields, zap.Int64("user_id", c.GetInt64("user-id")))
+	}
+
+	return fields
+}
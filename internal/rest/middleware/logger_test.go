@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	var accessFields []zap.Field
+	r := gin.New()
+	r.Use(RequestID(), Logger(zap.New(core)))
+	r.GET("/tasks/:taskId", AuthMiddleware(secret, nil), func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("message")
+		accessFields = AccessLogFields(c)
+		c.Status(http.StatusOK)
+	})
+
+	token, err := utils.CreateJwt(42, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	req.Header.Set(RequestIDHeader, "request-1")
+	req.AddCookie(&http.Cookie{Name: "token", Value: token})
+	r.ServeHTTP(w, req)
+
+	entries := logs.All()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		"request_id": "request-1",
+		"method":     "GET",
+		"route":      "/tasks/:taskId",
+		"user_id":    int64(42),
+	}, entries[0].ContextMap())
+	assert.Equal(t, []zap.Field{zap.String("request_id", "request-1"), zap.Int64("user_id", 42)}, accessFields)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/openapi"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+)
+
+// ValidateRequests returns a middleware refusing requests whose parameters
+// or body don't match their operation in the OpenAPI document, before they
+// reach handlers. Routes missing from the document are let through.
+func ValidateRequests(spec *openapi.Spec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := spec.Operation(c.Request.Method, openapi.RoutePath(apiRoute(c)))
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+
+		err := op.ValidateRequest(c.Request, params)
+
+		var validationErr *openapi.ValidationError
+		switch {
+		case err == nil:
+			c.Next()
+			return
+		case errors.As(err, &validationErr):
+			c.Error(requestViolations(validationErr))
+		case errors.Is(err, openapi.ErrMalformedBody):
+			c.Error(appErrors.ErrInvalidBody)
+		default:
+			c.Error(err)
+		}
+		c.Abort()
+	}
+}
+
+func requestViolations(err *openapi.ValidationError) *appErrors.ResponseError {
+	e := *appErrors.ErrValidation
+	e.Detail = "request failed validation"
+	e.Errors = make([]appErrors.FieldError, len(err.Violations))
+	for i, v := range err.Violations {
+		e.Errors[i] = appErrors.FieldError{Field: v.Field, Code: v.Keyword, Message: v.Message}
+	}
+
+	return &e
+}
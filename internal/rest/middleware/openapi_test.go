@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/openapi"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `
+openapi: 3.1.0
+info: {title: test, version: "1"}
+paths:
+  /tasks/{taskId}:
+    patch:
+      parameters:
+        - {name: taskId, in: path, required: true, schema: {type: integer, minimum: 1}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string, minLength: 1}
+      responses:
+        "200": {description: Updated task}
+`
+
+func TestValidateRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec, err := openapi.Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	var body string
+	r := gin.New()
+	r.Use(ErrorHandler(), ValidateRequests(spec))
+	handler := func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		body = string(data)
+		c.Status(http.StatusOK)
+	}
+	r.PATCH("/api/v1/tasks/:taskId", handler)
+	r.PATCH("/tasks/:taskId", handler)
+	r.PATCH("/undocumented", handler)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+		errors []errors.FieldError
+	}{
+		{"valid", "/api/v1/tasks/1", `{"name":"Task"}`, http.StatusOK, "", nil},
+		{"legacy route", "/tasks/1", `{"name":"Task"}`, http.StatusOK, "", nil},
+		{"undocumented route", "/undocumented", `{"name":""}`, http.StatusOK, "", nil},
+		{
+			"invalid", "/api/v1/tasks/0", `{"name":""}`, http.StatusBadRequest, "validation_failed",
+			[]errors.FieldError{
+				{Field: "taskId", Code: "minimum", Message: "must be at least 1"},
+				{Field: "name", Code: "minLength", Message: "must be at least 1 characters long"},
+			},
+		},
+		{"malformed", "/api/v1/tasks/1", `{"name"`, http.StatusBadRequest, "invalid_body", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = ""
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, body)
+				return
+			}
+
+			var problem errors.ResponseError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, tt.errors, problem.Errors)
+			assert.Empty(t, body)
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package middleware
 
 import (
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -37,7 +39,26 @@ func PrometheusMiddleware() gin.HandlerFunc {
 		duration := time.Since(start).Seconds()
 		status := c.Writer.Status()
 
-		httpRequestsTotal.WithLabelValues(c.Request.Method, c.FullPath(), strconv.Itoa(status)).Inc()
-		httpRequestDuration.WithLabelValues(c.Request.Method, c.FullPath(), strconv.Itoa(status)).Observe(duration)
+		labels := []string{c.Request.Method, c.FullPath(), strconv.Itoa(status)}
+		exemplar := traceExemplar(c.Request.Context())
+		if exemplar == nil {
+			httpRequestsTotal.WithLabelValues(labels...).Inc()
+			httpRequestDuration.WithLabelValues(labels...).Observe(duration)
+			return
+		}
+
+		httpRequestsTotal.WithLabelValues(labels...).(prometheus.ExemplarAdder).AddWithExemplar(1, exemplar)
+		httpRequestDuration.WithLabelValues(labels...).(prometheus.ExemplarObserver).ObserveWithExemplar(duration, exemplar)
 	}
 }
+
+// traceExemplar links a sample to the trace of the request, if it is
+// sampled. Exemplars are only exposed in the OpenMetrics format.
+func traceExemplar(ctx context.Context) prometheus.Labels {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsSampled() {
+		return nil
+	}
+
+	return prometheus.Labels{"trace_id": sc.TraceID().String()}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"go.uber.org/zap"
+)
+
+// APICallCounter counts the API calls of users, returning a
+// domain.QuotaError for calls beyond their quota.
+type APICallCounter interface {
+	UseAPICall(ctx context.Context, userId int64, now time.Time) error
+}
+
+// APIQuota returns a middleware counting the calls of users identified by a
+// valid token signed with the secret, and refusing those beyond their quota.
+// Calls to the exempt routes, such as the one reporting usage, are neither
+// counted nor refused, whatever the version of the API. Calls are let through
+// when counting fails.
+func APIQuota(counter APICallCounter, secret string, exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, route := range exempt {
+		skip[route] = true
+	}
+
+	return func(c *gin.Context) {
+		userId, tokenErr := validateToken(c, secret)
+		if tokenErr != nil || skip[apiRoute(c)] {
+			c.Next()
+			return
+		}
+
+		err := counter.UseAPICall(c.Request.Context(), userId, time.Now())
+
+		var quotaErr *domain.QuotaError
+		if errors.As(err, &quotaErr) {
+			if !quotaErr.ResetsAt.IsZero() {
+				c.Header("Retry-After", seconds(time.Until(quotaErr.ResetsAt)))
+			}
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Warn("API call quota not enforced", zap.Error(err))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingQuota struct {
+	limit int64
+	calls map[int64]int64
+}
+
+func (q *countingQuota) UseAPICall(_ context.Context, userId int64, now time.Time) error {
+	q.calls[userId]++
+	if q.calls[userId] > q.limit {
+		return &domain.QuotaError{Resource: domain.QuotaAPICalls, Limit: q.limit, ResetsAt: now.Add(time.Hour)}
+	}
+
+	return nil
+}
+
+func TestAPIQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "0123456789abcdef0123456789abcdef"
+	token, err := utils.CreateJwt(42, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quota := &countingQuota{limit: 1, calls: map[int64]int64{}}
+	r := gin.New()
+	r.Use(ErrorHandler(), APIQuota(quota, secret, "/me/usage"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/tasks", ok)
+	r.GET("/me/usage", ok)
+
+	serve := func(path string, authenticated bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if authenticated {
+			req.AddCookie(&http.Cookie{Name: "token", Value: token})
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/tasks", true).Code)
+
+	w := serve("/tasks", true)
+	var problem errors.ResponseError
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "quota_exceeded", problem.Code)
+	assert.Equal(t, "api_calls quota of 1 exceeded", problem.Detail)
+	assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serve("/me/usage", true).Code)
+	assert.Equal(t, http.StatusOK, serve("/tasks", false).Code)
+	assert.Equal(t, int64(2), quota.calls[42])
+}
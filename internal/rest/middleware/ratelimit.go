@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"go.uber.org/zap"
+)
+
+// Headers describing the rate limit of the request, following the IETF
+// RateLimit header fields draft.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+var errRateLimited = errors.NewResponseError(http.StatusTooManyRequests, "rate_limited", "too many requests, retry later")
+
+// RateLimit returns a middleware limiting requests according to the policies.
+// Requests with a valid token signed with the secret are limited per user,
+// others per client IP. Requests are let through when the store fails.
+func RateLimit(store ratelimit.Store, policies ratelimit.Policies, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, tokenErr := validateToken(c, secret)
+		authenticated := tokenErr == nil
+
+		policy, rate := policies.For(c.Request.Method, apiRoute(c), authenticated)
+		if rate.Unlimited() {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if authenticated {
+			client = "user:" + strconv.FormatInt(userId, 10)
+		}
+
+		result, err := store.Take(c.Request.Context(), policy+"|"+client, rate, time.Now())
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Warn("Rate limit not enforced", zap.String("policy", policy), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set(RateLimitLimitHeader, strconv.Itoa(rate.Requests))
+		header.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		header.Set(RateLimitResetHeader, seconds(result.Reset))
+		header.Set(RateLimitPolicyHeader, strconv.Itoa(rate.Requests)+";w="+seconds(rate.Period))
+
+		if !result.Allowed {
+			metrics.RateLimited(policy)
+			header.Set("Retry-After", seconds(result.RetryAfter))
+			c.Error(errRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// seconds formats d as whole seconds, rounded up so clients don't retry early.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Rate, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, fmt.Errorf("database is down")
+}
+
+func setupRateLimitTest(store ratelimit.Store, secret string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler(), RateLimit(store, ratelimit.Policies{
+		Anonymous:     ratelimit.Rate{Requests: 2, Period: time.Minute},
+		Authenticated: ratelimit.Rate{Requests: 3, Period: time.Minute},
+		Routes: map[string]ratelimit.Rate{
+			"POST /login": {Requests: 1, Period: time.Hour},
+			"/ping":       {},
+		},
+	}, secret))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/tasks", ok)
+	r.POST("/login", ok)
+	r.GET("/ping", ok)
+
+	return r
+}
+
+func TestRateLimit(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	token, err := utils.CreateJwt(42, secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(r *gin.Engine, method, path, ip string, authenticated bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		if authenticated {
+			req.AddCookie(&http.Cookie{Name: "token", Value: token})
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("limits anonymous requests per IP", func(t *testing.T) {
+		r := setupRateLimitTest(ratelimit.NewMemoryStore(), secret)
+
+		w := serve(r, "GET", "/tasks", "10.0.0.1", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "30", w.Header().Get(RateLimitResetHeader))
+		assert.Equal(t, "2;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+		serve(r, "GET", "/tasks", "10.0.0.1", false)
+		w = serve(r, "GET", "/tasks", "10.0.0.1", false)
+
+		var problem errors.ResponseError
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, errRateLimited.Code, problem.Code)
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+		w = serve(r, "GET", "/tasks", "10.0.0.2", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("limits authenticated requests per user", func(t *testing.T) {
+		r := setupRateLimitTest(ratelimit.NewMemoryStore(), secret)
+
+		for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+			w := serve(r, "GET", "/tasks", ip, true)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "3", w.Header().Get(RateLimitLimitHeader))
+		}
+
+		w := serve(r, "GET", "/tasks", "10.0.0.4", true)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("applies route policies in their own buckets", func(t *testing.T) {
+		r := setupRateLimitTest(ratelimit.NewMemoryStore(), secret)
+
+		assert.Equal(t, http.StatusOK, serve(r, "POST", "/login", "10.0.0.1", false).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(r, "POST", "/login", "10.0.0.1", false).Code)
+		assert.Equal(t, http.StatusOK, serve(r, "GET", "/tasks", "10.0.0.1", false).Code)
+
+		for range 5 {
+			w := serve(r, "GET", "/ping", "10.0.0.1", false)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+		}
+	})
+
+	t.Run("lets requests through when the store fails", func(t *testing.T) {
+		r := setupRateLimitTest(failingStore{}, secret)
+
+		w := serve(r, "GET", "/tasks", "10.0.0.1", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	})
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/internal/tracing"
+	"github.com/krau5/hyper-todo/internal/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	spans := tracingtest.New(t)
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/tasks/:taskId", func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "child")
+		span.End()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(w, req)
+
+	ended := spans.GetSpans()
+	require.Equal(t, []string{"child", "GET /tasks/:taskId"}, tracingtest.Names(ended))
+
+	child, server := ended[0], ended[1]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Contains(t, server.Attributes, semconv.HTTPRoute("/tasks/:taskId"))
+	assert.Contains(t, server.Attributes, semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, server.Status.Code)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+server.SpanContext.SpanID().String()+"-01", w.Header().Get("traceparent"))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceExemplar(trace.ContextWithSpanContext(req.Context(), server.SpanContext))["trace_id"])
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionPrefix matches the prefix of versioned routes, such as "/api/v1".
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+(/|$)`)
+
+// apiRoute returns the route of the request without its version prefix, so
+// that settings keyed by route, such as rate limits, apply to every version
+// of the API and to the unversioned aliases alike.
+func apiRoute(c *gin.Context) string {
+	route := c.FullPath()
+	if loc := versionPrefix.FindStringIndex(route); loc != nil {
+		return "/" + route[loc[1]:]
+	}
+
+	return route
+}
+
+// Deprecated returns a middleware marking the routes it applies to as
+// deprecated since deprecatedAt and, unless sunset is zero, removed at
+// sunset, with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers. The
+// Link header points to the same path under the successor prefix.
+func Deprecated(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	var sunsetHeader string
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if len(sunsetHeader) != 0 {
+			c.Header("Sunset", sunsetHeader)
+		}
+		c.Header("Link", "<"+successor+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
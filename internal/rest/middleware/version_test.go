@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+
+	r := gin.New()
+	r.Group("/", Deprecated(deprecatedAt, sunset, "/api/v1")).GET("/tasks/:taskId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/42", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/tasks/42>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestAPIRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var routes []string
+	r := gin.New()
+	r.Use(func(c *gin.Context) { routes = append(routes, apiRoute(c)) })
+	handler := func(c *gin.Context) {}
+	r.GET("/tasks/:taskId", handler)
+	r.GET("/api/v1/tasks/:taskId", handler)
+	r.GET("/api/v12/me", handler)
+	r.GET("/api/version", handler)
+
+	for _, path := range []string{"/tasks/1", "/api/v1/tasks/1", "/api/v12/me", "/api/version"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []string{"/tasks/:taskId", "/tasks/:taskId", "/me", "/api/version"}, routes)
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+)
+
+//go:generate mockery --name NotificationsService
+type NotificationsService interface {
+	GetById(context.Context, int64) (domain.Notification, error)
+	GetByUser(ctx context.Context, userId int64, unreadOnly bool) ([]domain.Notification, error)
+	SetRead(ctx context.Context, id int64, read bool) (domain.Notification, error)
+	SetAllRead(ctx context.Context, userId int64) error
+}
+
+// NotificationsHandler handles in-app notification requests.
+type NotificationsHandler struct {
+	notificationsService NotificationsService
+}
+
+var (
+	ErrInvalidNotificationId         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_notification_id", "notification id is missing or invalid")
+	ErrNotificationNotFound          = appErrors.NewResponseError(http.StatusNotFound, "notification_not_found", "notification was not found")
+	ErrFailedToRetrieveNotifications = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_notifications", "failed to retrieve notifications")
+	ErrFailedToUpdateNotification    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_notification", "failed to update notification")
+)
+
+// NewNotificationsHandler registers the notifications handler with the Gin engine.
+func NewNotificationsHandler(r gin.IRouter, auth gin.HandlerFunc, notificationsService NotificationsService) {
+	h := &NotificationsHandler{notificationsService: notificationsService}
+
+	r.GET("/notifications", auth, h.handleGetNotifications)
+	r.PATCH("/notifications/:notificationId", auth, h.handleUpdateNotification)
+	r.POST("/notifications/read-all", auth, h.handleReadAll)
+}
+
+// handleGetNotifications retrieves the inbox of the authenticated user.
+func (h *NotificationsHandler) handleGetNotifications(c *gin.Context) {
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, err := h.notificationsService.GetByUser(c.Request.Context(), c.GetInt64("user-id"), unreadOnly)
+	if err != nil {
+		c.Error(ErrFailedToRetrieveNotifications)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// handleUpdateNotification marks a notification as read or unread.
+func (h *NotificationsHandler) handleUpdateNotification(c *gin.Context) {
+	var data api.UpdateNotificationBody
+
+	notificationId, err := strconv.ParseInt(c.Param("notificationId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidNotificationId)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	notification, err := h.notificationsService.GetById(c.Request.Context(), notificationId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && notification.UserId != c.GetInt64("user-id")) {
+		c.Error(ErrNotificationNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveNotifications)
+		return
+	}
+
+	notification, err = h.notificationsService.SetRead(c.Request.Context(), notificationId, data.Read)
+	if err != nil {
+		c.Error(ErrFailedToUpdateNotification)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// handleReadAll marks every notification of the authenticated user as read.
+func (h *NotificationsHandler) handleReadAll(c *gin.Context) {
+	if err := h.notificationsService.SetAllRead(c.Request.Context(), c.GetInt64("user-id")); err != nil {
+		c.Error(ErrFailedToUpdateNotification)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetNotificationsHandler_UnreadOnly(t *testing.T) {
+	mockNotifications := []domain.Notification{{ID: 1, Title: "title", Body: "body"}}
+
+	r, notificationsService := setupNotificationsTest(t)
+	notificationsService.On("GetByUser", mock.Anything, userId, true).Return(mockNotifications, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/notifications?unread=true", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(mockNotifications)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestUpdateNotificationHandler_ForeignNotification(t *testing.T) {
+	r, notificationsService := setupNotificationsTest(t)
+	notificationsService.On("GetById", mock.Anything, int64(1)).Return(domain.Notification{ID: 1, UserId: userId + 1}, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.UpdateNotificationBody{Read: true})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/notifications/1", &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrNotificationNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestUpdateNotificationHandler(t *testing.T) {
+	r, notificationsService := setupNotificationsTest(t)
+	notificationsService.On("GetById", mock.Anything, int64(1)).Return(domain.Notification{ID: 1, UserId: userId}, nil)
+	notificationsService.On("SetRead", mock.Anything, int64(1), true).Return(domain.Notification{ID: 1, Read: true}, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.UpdateNotificationBody{Read: true})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/notifications/1", &buf)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func setupNotificationsTest(t *testing.T) (*gin.Engine, *mocks.NotificationsService) {
+	gin.SetMode(gin.TestMode)
+
+	notificationsService := mocks.NewNotificationsService(t)
+	h := &NotificationsHandler{notificationsService: notificationsService}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/notifications", h.handleGetNotifications)
+	r.PATCH("/notifications/:notificationId", h.handleUpdateNotification)
+
+	return r, notificationsService
+}
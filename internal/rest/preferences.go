@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/preference"
+)
+
+//go:generate mockery --name PreferencesService
+type PreferencesService interface {
+	GetByUser(context.Context, int64) (domain.Preferences, error)
+	Update(context.Context, int64, domain.UpdatePreferencesData) (domain.Preferences, error)
+}
+
+// PreferencesHandler handles user preference requests.
+type PreferencesHandler struct {
+	preferencesService PreferencesService
+}
+
+var (
+	ErrInvalidTimezone             = appErrors.NewResponseError(http.StatusBadRequest, "invalid_timezone", preference.ErrInvalidTimezone.Error())
+	ErrInvalidQuietHours           = appErrors.NewResponseError(http.StatusBadRequest, "invalid_quiet_hours", preference.ErrInvalidQuietHours.Error())
+	ErrInvalidNotificationChannel  = appErrors.NewResponseError(http.StatusBadRequest, "invalid_notification_channel", preference.ErrInvalidChannel.Error())
+	ErrInvalidDigestTime           = appErrors.NewResponseError(http.StatusBadRequest, "invalid_digest_time", preference.ErrInvalidDigestTime.Error())
+	ErrFailedToRetrievePreferences = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_preferences", "failed to retrieve preferences")
+	ErrFailedToUpdatePreferences   = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_preferences", "failed to update preferences")
+)
+
+// NewPreferencesHandler registers the preferences handler with the Gin engine.
+func NewPreferencesHandler(r gin.IRouter, auth gin.HandlerFunc, preferencesService PreferencesService) {
+	h := &PreferencesHandler{preferencesService: preferencesService}
+
+	r.GET("/me/preferences", auth, h.handleGetPreferences)
+	r.PATCH("/me/preferences", auth, h.handleUpdatePreferences)
+}
+
+// handleGetPreferences retrieves the preferences of the authenticated user.
+func (h *PreferencesHandler) handleGetPreferences(c *gin.Context) {
+	preferences, err := h.preferencesService.GetByUser(c.Request.Context(), c.GetInt64("user-id"))
+	if err != nil {
+		c.Error(ErrFailedToRetrievePreferences)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// handleUpdatePreferences updates the preferences of the authenticated user.
+func (h *PreferencesHandler) handleUpdatePreferences(c *gin.Context) {
+	var data domain.UpdatePreferencesData
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	preferences, err := h.preferencesService.Update(c.Request.Context(), c.GetInt64("user-id"), data)
+	if respErr := preferencesValidationError(err); respErr != nil {
+		c.Error(respErr)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToUpdatePreferences)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func preferencesValidationError(err error) *appErrors.ResponseError {
+	switch {
+	case errors.Is(err, preference.ErrInvalidTimezone):
+		return ErrInvalidTimezone
+	case errors.Is(err, preference.ErrInvalidQuietHours):
+		return ErrInvalidQuietHours
+	case errors.Is(err, preference.ErrInvalidChannel):
+		return ErrInvalidNotificationChannel
+	case errors.Is(err, preference.ErrInvalidDigestTime):
+		return ErrInvalidDigestTime
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/reminder"
+)
+
+//go:generate mockery --name RemindersService
+type RemindersService interface {
+	GetByTask(context.Context, int64) ([]domain.Reminder, error)
+	SetForTask(ctx context.Context, taskId int64, offsets []time.Duration) ([]domain.Reminder, error)
+}
+
+// RemindersHandler handles task reminder requests.
+type RemindersHandler struct {
+	tasksService     TasksService
+	remindersService RemindersService
+}
+
+var (
+	ErrInvalidReminderOffset     = appErrors.NewResponseError(http.StatusBadRequest, "invalid_reminder_offset", reminder.ErrInvalidOffset.Error())
+	ErrTooManyReminders          = appErrors.NewResponseError(http.StatusBadRequest, "too_many_reminders", reminder.ErrTooManyReminders.Error())
+	ErrFailedToRetrieveReminders = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_reminders", "failed to retrieve reminders")
+	ErrFailedToUpdateReminders   = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_reminders", "failed to update reminders")
+)
+
+// NewRemindersHandler registers the reminders handler with the Gin engine.
+func NewRemindersHandler(r gin.IRouter, auth gin.HandlerFunc, tasksService TasksService, remindersService RemindersService) {
+	h := &RemindersHandler{
+		tasksService:     tasksService,
+		remindersService: remindersService,
+	}
+
+	r.GET("/tasks/:taskId/reminders", auth, h.handleGetReminders)
+	r.PUT("/tasks/:taskId/reminders", auth, h.handleSetReminders)
+}
+
+// handleGetReminders retrieves the reminder offsets of a task.
+func (h *RemindersHandler) handleGetReminders(c *gin.Context) {
+	taskId, ok := h.getOwnTaskId(c)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.remindersService.GetByTask(c.Request.Context(), taskId)
+	if err != nil {
+		c.Error(ErrFailedToRetrieveReminders)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRemindersBody(reminders))
+}
+
+// handleSetReminders replaces the reminder offsets of a task.
+func (h *RemindersHandler) handleSetReminders(c *gin.Context) {
+	var data api.RemindersBody
+
+	taskId, ok := h.getOwnTaskId(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	offsets := make([]time.Duration, len(data.Offsets))
+	for i, rawOffset := range data.Offsets {
+		offset, err := time.ParseDuration(rawOffset)
+		if err != nil {
+			c.Error(ErrInvalidReminderOffset)
+			return
+		}
+		offsets[i] = offset
+	}
+
+	reminders, err := h.remindersService.SetForTask(c.Request.Context(), taskId, offsets)
+	if errors.Is(err, reminder.ErrInvalidOffset) {
+		c.Error(ErrInvalidReminderOffset)
+		return
+	}
+
+	if errors.Is(err, reminder.ErrTooManyReminders) {
+		c.Error(ErrTooManyReminders)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToUpdateReminders)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRemindersBody(reminders))
+}
+
+// getOwnTaskId parses the task ID from the path and writes an error
+// response unless the task belongs to the authenticated user.
+func (h *RemindersHandler) getOwnTaskId(c *gin.Context) (int64, bool) {
+	taskId, err := strconv.ParseInt(c.Param("taskId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidTaskId)
+		return 0, false
+	}
+
+	task, err := h.tasksService.GetById(c.Request.Context(), taskId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && task.UserId != c.GetInt64("user-id")) {
+		c.Error(ErrTaskNotFound)
+		return 0, false
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveTask)
+		return 0, false
+	}
+
+	return taskId, true
+}
+
+func toRemindersBody(reminders []domain.Reminder) api.RemindersBody {
+	body := api.RemindersBody{Offsets: make([]string, len(reminders))}
+	for i, r := range reminders {
+		body.Offsets[i] = r.RemindBefore.String()
+	}
+
+	return body
+}
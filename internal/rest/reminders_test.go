@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetRemindersHandler(t *testing.T) {
+	offsets := []time.Duration{24 * time.Hour, time.Hour}
+
+	r, tasksService, remindersService := setupRemindersTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId}, nil)
+	remindersService.On("SetForTask", mock.Anything, taskId, offsets).Return([]domain.Reminder{
+		{TaskId: taskId, RemindBefore: offsets[0]},
+		{TaskId: taskId, RemindBefore: offsets[1]},
+	}, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.RemindersBody{Offsets: []string{"24h", "1h"}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/tasks/%v/reminders", taskId), &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(api.RemindersBody{Offsets: []string{"24h0m0s", "1h0m0s"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestSetRemindersHandler_InvalidOffset(t *testing.T) {
+	r, tasksService, _ := setupRemindersTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId}, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.RemindersBody{Offsets: []string{"one day"}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/tasks/%v/reminders", taskId), &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrInvalidReminderOffset)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestGetRemindersHandler_ForeignTask(t *testing.T) {
+	r, tasksService, _ := setupRemindersTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId + 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/tasks/%v/reminders", taskId), nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func setupRemindersTest(t *testing.T) (*gin.Engine, *mocks.TasksService, *mocks.RemindersService) {
+	gin.SetMode(gin.TestMode)
+
+	tasksService := mocks.NewTasksService(t)
+	remindersService := mocks.NewRemindersService(t)
+	h := &RemindersHandler{tasksService: tasksService, remindersService: remindersService}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/tasks/:taskId/reminders", h.handleGetReminders)
+	r.PUT("/tasks/:taskId/reminders", h.handleSetReminders)
+
+	return r, tasksService, remindersService
+}
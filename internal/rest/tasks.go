@@ -8,10 +8,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
 	"github.com/krau5/hyper-todo/domain"
 	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
-	"github.com/krau5/hyper-todo/internal/rest/middleware"
-	"gorm.io/gorm"
+	"github.com/krau5/hyper-todo/task"
 )
 
 //go:generate mockery --name TasksService
@@ -28,84 +28,79 @@ type TasksHandler struct {
 	tasksService TasksService
 }
 
-// CreateTaskBody defines the request body for the /tasks endpoint.
-type CreateTaskBody struct {
-	Name        string `json:"name" example:"Eat"`                      // Name of the task
-	Description string `json:"description" example:"Eat the pizza"`     // Description of the task
-	Deadline    string `json:"deadline" example:"2023-12-31T23:59:59Z"` // Deadline for the task (RFC3339 format)
-}
-
 var (
-	ErrInvalidDeadline       = appErrors.NewResponseError(http.StatusBadRequest, "failed to parse deadline")
-	ErrFailedToCreateTask    = appErrors.NewResponseError(http.StatusBadRequest, "failed to create task")
-	ErrInvalidTaskId         = appErrors.NewResponseError(http.StatusBadRequest, "task id is missing or invalid")
-	ErrTaskNotFound          = appErrors.NewResponseError(http.StatusNotFound, "task was not found")
-	ErrFailedToDeleteTask    = appErrors.NewResponseError(http.StatusInternalServerError, "failed to delete task")
-	ErrFailedToRetrieveTasks = appErrors.NewResponseError(http.StatusInternalServerError, "failed to retrieve tasks")
-	ErrFailedToUpdateTask    = appErrors.NewResponseError(http.StatusInternalServerError, "failed to update task")
+	ErrInvalidDeadline       = appErrors.NewResponseError(http.StatusBadRequest, "invalid_deadline", "failed to parse deadline")
+	ErrFailedToCreateTask    = appErrors.NewResponseError(http.StatusBadRequest, "failed_to_create_task", "failed to create task")
+	ErrInvalidTaskId         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_task_id", "task id is missing or invalid")
+	ErrInvalidBlockedFilter  = appErrors.NewResponseError(http.StatusBadRequest, "invalid_blocked_filter", "blocked must be either true or false")
+	ErrTaskBlocked           = appErrors.NewResponseError(http.StatusConflict, "task_blocked", task.ErrTaskBlocked.Error())
+	ErrTaskNotFound          = appErrors.NewResponseError(http.StatusNotFound, "task_not_found", "task was not found")
+	ErrFailedToDeleteTask    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_delete_task", "failed to delete task")
+	ErrFailedToRetrieveTasks = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_tasks", "failed to retrieve tasks")
+	ErrFailedToUpdateTask    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_task", "failed to update task")
 )
 
 // NewTasksHandler registers the task handler with the Gin engine.
-func NewTasksHandler(r *gin.Engine, tasksService TasksService) {
+func NewTasksHandler(r gin.IRouter, auth gin.HandlerFunc, tasksService TasksService) {
 	h := &TasksHandler{
 		tasksService: tasksService,
 	}
 
-	r.GET("/tasks", middleware.AuthMiddleware, h.handleGetTasks)
-	r.POST("/tasks", middleware.AuthMiddleware, h.handleCreateTask)
-	r.PATCH("/tasks/:taskId", middleware.AuthMiddleware, h.handleUpdateTask)
-	r.DELETE("/tasks/:taskId", middleware.AuthMiddleware, h.handleDeleteTask)
+	r.GET("/tasks", auth, h.handleGetTasks)
+	r.POST("/tasks", auth, h.handleCreateTask)
+	r.PATCH("/tasks/:taskId", auth, h.handleUpdateTask)
+	r.DELETE("/tasks/:taskId", auth, h.handleDeleteTask)
 }
 
 // handleGetTasks retrieves all tasks for the authenticated user.
-// @Summary Get all tasks for the current user
-// @Description Retrieve a list of tasks for the currently authenticated user
-// @Tags tasks
-// @Security ApiKeyAuth
-// @Produce json
-// @Success 200 {array} domain.Task "List of tasks"
-// @Failure 404 {object} appErrors.ResponseError "User not found"
-// @Failure 500 {object} appErrors.ResponseError "Failed to retrieve tasks"
-// @Router /tasks [get]
 func (h *TasksHandler) handleGetTasks(c *gin.Context) {
+	var blocked *bool
+	if rawBlocked, ok := c.GetQuery("blocked"); ok {
+		value, err := strconv.ParseBool(rawBlocked)
+		if err != nil {
+			c.Error(ErrInvalidBlockedFilter)
+			return
+		}
+		blocked = &value
+	}
+
 	tasks, err := h.tasksService.GetByUser(c.Request.Context(), c.GetInt64("user-id"))
 
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(ErrUserNotFound.Status, ErrUserNotFound)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
 		return
 	}
 
 	if err != nil {
-		c.JSON(ErrFailedToRetrieveTasks.Status, ErrFailedToRetrieveTasks)
+		c.Error(ErrFailedToRetrieveTasks)
 		return
 	}
 
+	if blocked != nil {
+		filtered := []domain.Task{}
+		for _, t := range tasks {
+			if t.Blocked == *blocked {
+				filtered = append(filtered, t)
+			}
+		}
+		tasks = filtered
+	}
+
 	c.JSON(http.StatusOK, tasks)
 }
 
 // handleCreateTask creates a new task.
-// @Summary Create a new task
-// @Description Create a new task for the authenticated user
-// @Tags tasks
-// @Security ApiKeyAuth
-// @Accept json
-// @Produce json
-// @Param body body CreateTaskBody true "Task details"
-// @Success 201 {object} domain.Task "Created task"
-// @Failure 400 {object} appErrors.ResponseError "Invalid request body or deadline"
-// @Failure 500 {object} appErrors.ResponseError "Failed to create task"
-// @Router /tasks [post]
 func (h *TasksHandler) handleCreateTask(c *gin.Context) {
-	var data CreateTaskBody
+	var data api.CreateTaskBody
 
-	if err := c.BindJSON(&data); err != nil {
-		c.JSON(appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody)
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
 		return
 	}
 
 	deadline, err := time.Parse(time.RFC3339, data.Deadline)
 	if err != nil {
-		c.JSON(ErrInvalidDeadline.Status, ErrInvalidDeadline)
+		c.Error(ErrInvalidDeadline)
 		return
 	}
 
@@ -117,8 +112,13 @@ func (h *TasksHandler) handleCreateTask(c *gin.Context) {
 		deadline,
 		userId,
 	)
+	if errors.Is(err, domain.ErrQuotaExceeded) {
+		c.Error(err)
+		return
+	}
+
 	if err != nil {
-		c.JSON(ErrFailedToCreateTask.Status, ErrFailedToCreateTask)
+		c.Error(ErrFailedToCreateTask)
 		return
 	}
 
@@ -126,49 +126,40 @@ func (h *TasksHandler) handleCreateTask(c *gin.Context) {
 }
 
 // handleUpdateTask updates a task by ID.
-// @Summary Update a task
-// @Description Update a task by ID for the authenticated user
-// @Tags tasks
-// @Security ApiKeyAuth
-// @Accept json
-// @Produce json
-// @Param taskId path int true "Task ID"
-// @Param body body domain.UpdateTaskData true "Task update data"
-// @Success 200 {object} domain.Task "Updated task"
-// @Failure 400 {object} appErrors.ResponseError "Invalid task ID or request body"
-// @Failure 404 {object} appErrors.ResponseError "Task not found"
-// @Failure 403 "Forbidden if the task does not belong to the user"
-// @Failure 500 {object} appErrors.ResponseError "Failed to update task"
-// @Router /tasks/{taskId} [patch]
 func (h *TasksHandler) handleUpdateTask(c *gin.Context) {
 	var data domain.UpdateTaskData
 
 	rawTaskId := c.Param("taskId")
 	taskId, err := strconv.ParseInt(rawTaskId, 10, 64)
 	if err != nil {
-		c.JSON(ErrInvalidTaskId.Status, ErrInvalidTaskId)
+		c.Error(ErrInvalidTaskId)
 		return
 	}
 
 	if err := c.ShouldBindJSON(&data); err != nil {
-		c.JSON(appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody)
+		c.Error(err)
 		return
 	}
 
 	task, err := h.tasksService.GetById(c.Request.Context(), taskId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(ErrTaskNotFound.Status, ErrTaskNotFound)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrTaskNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveTask)
 		return
 	}
 
 	if task.UserId != c.GetInt64("user-id") {
-		c.Status(http.StatusForbidden)
+		c.Error(ErrTaskForbidden)
 		return
 	}
 
 	task, err = h.tasksService.UpdateById(c.Request.Context(), taskId, data)
 	if err != nil {
-		c.JSON(ErrFailedToUpdateTask.Status, ErrFailedToUpdateTask)
+		c.Error(updateTaskError(err))
 		return
 	}
 
@@ -176,41 +167,43 @@ func (h *TasksHandler) handleUpdateTask(c *gin.Context) {
 }
 
 // handleDeleteTask deletes a task by ID.
-// @Summary Delete a task
-// @Description Delete a task by ID for the authenticated user
-// @Tags tasks
-// @Security ApiKeyAuth
-// @Param taskId path int true "Task ID"
-// @Success 200 "Task deleted successfully"
-// @Failure 400 {object} appErrors.ResponseError "Invalid task ID"
-// @Failure 404 {object} appErrors.ResponseError "Task not found"
-// @Failure 403 "Forbidden if the task does not belong to the user"
-// @Failure 500 {object} appErrors.ResponseError "Failed to delete task"
-// @Router /tasks/{taskId} [delete]
 func (h *TasksHandler) handleDeleteTask(c *gin.Context) {
 	rawTaskId := c.Param("taskId")
 	taskId, err := strconv.ParseInt(rawTaskId, 10, 64)
 	if err != nil {
-		c.JSON(ErrInvalidTaskId.Status, ErrInvalidTaskId)
+		c.Error(ErrInvalidTaskId)
 		return
 	}
 
 	task, err := h.tasksService.GetById(c.Request.Context(), taskId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(ErrTaskNotFound.Status, ErrTaskNotFound)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrTaskNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveTask)
 		return
 	}
 
 	if task.UserId != c.GetInt64("user-id") {
-		c.Status(http.StatusForbidden)
+		c.Error(ErrTaskForbidden)
 		return
 	}
 
 	err = h.tasksService.DeleteById(c.Request.Context(), taskId)
 	if err != nil {
-		c.JSON(ErrFailedToDeleteTask.Status, ErrFailedToDeleteTask)
+		c.Error(ErrFailedToDeleteTask)
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
+
+func updateTaskError(err error) *appErrors.ResponseError {
+	if errors.Is(err, task.ErrTaskBlocked) {
+		return ErrTaskBlocked
+	}
+
+	return ErrFailedToUpdateTask
+}
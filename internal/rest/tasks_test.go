@@ -10,11 +10,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
 	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
 	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/krau5/hyper-todo/task"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gorm.io/gorm"
 )
 
 const taskId int64 = 1
@@ -42,7 +45,7 @@ func TestCreateTaskHandler(t *testing.T) {
 	r, tasksService := setupTasksTest(t)
 	tasksService.On("Create", mock.Anything, name, description, deadline, userId).Return(mockTask, nil)
 
-	body := CreateTaskBody{
+	body := api.CreateTaskBody{
 		Name:        name,
 		Description: description,
 		Deadline:    rawDeadline,
@@ -61,6 +64,31 @@ func TestCreateTaskHandler(t *testing.T) {
 	assert.Equal(t, string(expectedBody), w.Body.String())
 }
 
+func TestCreateTaskHandler_QuotaExceeded(t *testing.T) {
+	rawDeadline := "2025-01-01T22:22:22.220Z"
+	deadline, _ := time.Parse(time.RFC3339, rawDeadline)
+	var userId int64 = 1
+
+	r, tasksService := setupTasksTest(t)
+	tasksService.On("Create", mock.Anything, "eat", "eat the pizza", deadline, userId).
+		Return(domain.Task{}, &domain.QuotaError{Resource: domain.QuotaTasks, Limit: 10})
+
+	body := api.CreateTaskBody{Name: "eat", Description: "eat the pizza", Deadline: rawDeadline}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		t.Error(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", &buf)
+	r.ServeHTTP(w, req)
+
+	var problem appErrors.ResponseError
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "quota_exceeded", problem.Code)
+}
+
 func TestGetTasksHandler(t *testing.T) {
 	var userId int64 = 1
 	mockTasks := []domain.Task{
@@ -94,7 +122,7 @@ func TestGetTasksHandler_UserNotFound(t *testing.T) {
 	var userId int64 = 1
 
 	r, tasksService := setupTasksTest(t)
-	tasksService.On("GetByUser", mock.Anything, userId).Return([]domain.Task{}, gorm.ErrRecordNotFound)
+	tasksService.On("GetByUser", mock.Anything, userId).Return([]domain.Task{}, domain.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/tasks", nil)
@@ -119,6 +147,57 @@ func TestGetTasksHandler_FailedToRetrieveTasks(t *testing.T) {
 	assert.Equal(t, string(expectedBody), w.Body.String())
 }
 
+func TestGetTasksHandler_BlockedFilter(t *testing.T) {
+	mockTasks := []domain.Task{
+		{ID: 1, Name: "eat", Description: "eat the pizza", Blocked: true},
+		{ID: 2, Name: "drink", Description: "drink the coke"},
+	}
+
+	r, tasksService := setupTasksTest(t)
+	tasksService.On("GetByUser", mock.Anything, userId).Return(mockTasks, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks?blocked=false", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(mockTasks[1:])
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestGetTasksHandler_InvalidBlockedFilter(t *testing.T) {
+	r, _ := setupTasksTest(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks?blocked=maybe", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrInvalidBlockedFilter)
+	assert.Equal(t, ErrInvalidBlockedFilter.Status, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestUpdateTaskHandler_TaskBlocked(t *testing.T) {
+	completed := true
+	body := domain.UpdateTaskData{Completed: &completed}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		t.Error(err)
+	}
+
+	r, tasksService := setupTasksTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId, Blocked: true}, nil)
+	tasksService.On("UpdateById", mock.Anything, taskId, body).Return(domain.Task{}, task.ErrTaskBlocked)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("/tasks/%v", taskId), &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrTaskBlocked)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
 func TestUpdateTaskHandler_TaskNotFound(t *testing.T) {
 	name := "drink"
 	body := domain.UpdateTaskData{Name: &name}
@@ -128,7 +207,7 @@ func TestUpdateTaskHandler_TaskNotFound(t *testing.T) {
 	}
 
 	r, tasksService := setupTasksTest(t)
-	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{}, gorm.ErrRecordNotFound)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{}, domain.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", fmt.Sprintf("/tasks/%v", taskId), &buf)
@@ -139,6 +218,19 @@ func TestUpdateTaskHandler_TaskNotFound(t *testing.T) {
 	assert.Equal(t, string(expectedBody), w.Body.String())
 }
 
+func TestDeleteTaskHandler_Forbidden(t *testing.T) {
+	r, tasksService := setupTasksTest(t)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId + 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("/tasks/%v", taskId), nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrTaskForbidden)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
 func setupTasksTest(t *testing.T) (*gin.Engine, *mocks.TasksService) {
 	gin.SetMode(gin.TestMode)
 
@@ -147,6 +239,7 @@ func setupTasksTest(t *testing.T) (*gin.Engine, *mocks.TasksService) {
 		tasksService: tasksService,
 	}
 	r := gin.New()
+	r.Use(middleware.ErrorHandler())
 
 	r.Use(func(c *gin.Context) {
 		c.Set("user-id", userId)
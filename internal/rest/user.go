@@ -1,45 +1,72 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/krau5/hyper-todo/internal/rest/errors"
-	"github.com/krau5/hyper-todo/internal/rest/middleware"
-	"gorm.io/gorm"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
 )
 
+//go:generate mockery --name UsageService
+type UsageService interface {
+	GetUsage(ctx context.Context, userId int64, now time.Time) (domain.Usage, error)
+}
+
 // UsersHandler handles user-related requests.
 type UsersHandler struct {
 	usersService UsersService
+	usageService UsageService
 }
 
+// UsageRoute is the route reporting the quota usage of the user.
+const UsageRoute = "/me/usage"
+
+var ErrFailedToRetrieveUsage = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_usage", "failed to retrieve usage")
+
 // NewUsersHandler registers the user handler with the Gin engine.
-func NewUsersHandler(r *gin.Engine, usersService UsersService) {
-	h := &UsersHandler{usersService: usersService}
+func NewUsersHandler(r gin.IRouter, auth gin.HandlerFunc, usersService UsersService, usageService UsageService) {
+	h := &UsersHandler{usersService: usersService, usageService: usageService}
 
-	r.GET("/me", middleware.AuthMiddleware, h.handleMe)
+	r.GET("/me", auth, h.handleMe)
+	r.GET(UsageRoute, auth, h.handleUsage)
 }
 
 // handleMe retrieves details of the currently authenticated user.
-// @Summary Get current user details
-// @Description Retrieve details of the currently authenticated user
-// @Tags users
-// @Security ApiKeyAuth
-// @Produce json
-// @Success 200 {object} domain.User "User details"
-// @Failure 404 {object} errors.ResponseError "User not found"
-// @Failure 401 {object} errors.ResponseError "Unauthorized"
-// @Router /me [get]
 func (h *UsersHandler) handleMe(c *gin.Context) {
 	userId := c.GetInt64("user-id")
 	user, err := h.usersService.GetById(c.Request.Context(), userId)
 
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(ErrUserNotFound.Status, ErrUserNotFound)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveUser)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
+
+// handleUsage reports the consumption of the quotas of the current user.
+func (h *UsersHandler) handleUsage(c *gin.Context) {
+	userId := c.GetInt64("user-id")
+	usage, err := h.usageService.GetUsage(c.Request.Context(), userId, time.Now())
+
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Error(ErrUserNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveUsage)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
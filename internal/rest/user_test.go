@@ -2,16 +2,18 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
 	"github.com/krau5/hyper-todo/internal/rest/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gorm.io/gorm"
 )
 
 var userId int64 = 1
@@ -19,7 +21,7 @@ var userId int64 = 1
 func TestMeHandler(t *testing.T) {
 	mockUser := domain.User{Name: "user", Email: "user@example.com"}
 
-	r, usersService := setupUsersTest(t)
+	r, usersService, _ := setupUsersTest(t)
 	usersService.On("GetById", mock.Anything, userId).Return(mockUser, nil)
 
 	w := httptest.NewRecorder()
@@ -32,8 +34,8 @@ func TestMeHandler(t *testing.T) {
 }
 
 func TestMeHandler_UserNotFound(t *testing.T) {
-	r, usersService := setupUsersTest(t)
-	usersService.On("GetById", mock.Anything, userId).Return(domain.User{}, gorm.ErrRecordNotFound)
+	r, usersService, _ := setupUsersTest(t)
+	usersService.On("GetById", mock.Anything, userId).Return(domain.User{}, domain.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/me", nil)
@@ -44,17 +46,55 @@ func TestMeHandler_UserNotFound(t *testing.T) {
 	assert.Equal(t, string(expectedBody), w.Body.String())
 }
 
-func setupUsersTest(t *testing.T) (*gin.Engine, *mocks.UsersService) {
+func TestUsageHandler(t *testing.T) {
+	now := time.Now()
+	limit := int64(100)
+	usage := domain.Usage{
+		Plan:     "free",
+		Tasks:    domain.QuotaUsage{Used: 3, Limit: &limit},
+		APICalls: domain.QuotaUsage{Used: 42, ResetsAt: &now},
+	}
+
+	r, _, usageService := setupUsersTest(t)
+	usageService.On("GetUsage", mock.Anything, userId, mock.Anything).Return(usage, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me/usage", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(usage)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Contains(t, w.Body.String(), `"apiCalls":{"used":42,"limit":null,`)
+}
+
+func TestUsageHandler_Failed(t *testing.T) {
+	r, _, usageService := setupUsersTest(t)
+	usageService.On("GetUsage", mock.Anything, userId, mock.Anything).Return(domain.Usage{}, errors.New("connection refused"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me/usage", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrFailedToRetrieveUsage)
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func setupUsersTest(t *testing.T) (*gin.Engine, *mocks.UsersService, *mocks.UsageService) {
 	gin.SetMode(gin.TestMode)
 
 	usersService := mocks.NewUsersService(t)
-	h := &UsersHandler{usersService: usersService}
+	usageService := mocks.NewUsageService(t)
+	h := &UsersHandler{usersService: usersService, usageService: usageService}
 	r := gin.New()
+	r.Use(middleware.ErrorHandler())
 	r.Use(func(c *gin.Context) {
 		c.Set("user-id", userId)
 		c.Next()
 	})
 	r.GET("/me", h.handleMe)
+	r.GET("/me/usage", h.handleUsage)
 
-	return r, usersService
+	return r, usersService, usageService
 }
@@ -0,0 +1,231 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/webhook"
+)
+
+//go:generate mockery --name WebhooksService
+type WebhooksService interface {
+	Create(ctx context.Context, url string, eventTypes []domain.EventType, userId int64) (domain.Webhook, error)
+	GetById(context.Context, int64) (domain.Webhook, error)
+	GetByUser(context.Context, int64) ([]domain.Webhook, error)
+	UpdateById(context.Context, int64, domain.UpdateWebhookData) (domain.Webhook, error)
+	DeleteById(context.Context, int64) error
+	GetDeliveries(context.Context, int64) ([]domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookId, deliveryId int64) (domain.WebhookDelivery, error)
+}
+
+// WebhooksHandler handles webhook subscription requests.
+type WebhooksHandler struct {
+	webhooksService WebhooksService
+}
+
+var (
+	ErrInvalidWebhookId         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_webhook_id", "webhook id is missing or invalid")
+	ErrInvalidDeliveryId        = appErrors.NewResponseError(http.StatusBadRequest, "invalid_delivery_id", "delivery id is missing or invalid")
+	ErrInvalidWebhookURL        = appErrors.NewResponseError(http.StatusBadRequest, "invalid_webhook_url", webhook.ErrInvalidURL.Error())
+	ErrNonPublicWebhookURL      = appErrors.NewResponseError(http.StatusBadRequest, "non_public_webhook_url", webhook.ErrNonPublicURL.Error())
+	ErrInvalidEventType         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_event_type", webhook.ErrInvalidEventType.Error())
+	ErrWebhookNotFound          = appErrors.NewResponseError(http.StatusNotFound, "webhook_not_found", "webhook was not found")
+	ErrDeliveryNotFound         = appErrors.NewResponseError(http.StatusNotFound, "delivery_not_found", "delivery was not found")
+	ErrFailedToCreateWebhook    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_create_webhook", "failed to create webhook")
+	ErrFailedToRetrieveWebhooks = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_webhooks", "failed to retrieve webhooks")
+	ErrFailedToUpdateWebhook    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_update_webhook", "failed to update webhook")
+	ErrFailedToDeleteWebhook    = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_delete_webhook", "failed to delete webhook")
+	ErrFailedToRetrieveDelivery = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_delivery", "failed to retrieve deliveries")
+	ErrFailedToRedeliver        = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_redeliver", "failed to redeliver")
+)
+
+// NewWebhooksHandler registers the webhooks handler with the Gin engine.
+func NewWebhooksHandler(r gin.IRouter, auth gin.HandlerFunc, webhooksService WebhooksService) {
+	h := &WebhooksHandler{webhooksService: webhooksService}
+
+	r.GET("/webhooks", auth, h.handleGetWebhooks)
+	r.POST("/webhooks", auth, h.handleCreateWebhook)
+	r.GET("/webhooks/:webhookId", auth, h.handleGetWebhook)
+	r.PATCH("/webhooks/:webhookId", auth, h.handleUpdateWebhook)
+	r.DELETE("/webhooks/:webhookId", auth, h.handleDeleteWebhook)
+	r.GET("/webhooks/:webhookId/deliveries", auth, h.handleGetDeliveries)
+	r.POST("/webhooks/:webhookId/deliveries/:deliveryId/redeliver", auth, h.handleRedeliver)
+}
+
+// handleGetWebhooks retrieves all webhooks of the authenticated user.
+func (h *WebhooksHandler) handleGetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhooksService.GetByUser(c.Request.Context(), c.GetInt64("user-id"))
+	if err != nil {
+		c.Error(ErrFailedToRetrieveWebhooks)
+		return
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// handleCreateWebhook creates a new webhook subscription.
+func (h *WebhooksHandler) handleCreateWebhook(c *gin.Context) {
+	var data api.CreateWebhookBody
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	w, err := h.webhooksService.Create(c.Request.Context(), data.URL, data.EventTypes, c.GetInt64("user-id"))
+	if respErr := webhookValidationError(err); respErr != nil {
+		c.Error(respErr)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToCreateWebhook)
+		return
+	}
+
+	c.JSON(http.StatusCreated, w)
+}
+
+// handleGetWebhook retrieves a webhook by ID.
+func (h *WebhooksHandler) handleGetWebhook(c *gin.Context) {
+	w, ok := h.getOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	w.Secret = ""
+	c.JSON(http.StatusOK, w)
+}
+
+// handleUpdateWebhook updates a webhook by ID.
+func (h *WebhooksHandler) handleUpdateWebhook(c *gin.Context) {
+	var data domain.UpdateWebhookData
+
+	w, ok := h.getOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	w, err := h.webhooksService.UpdateById(c.Request.Context(), w.ID, data)
+	if respErr := webhookValidationError(err); respErr != nil {
+		c.Error(respErr)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToUpdateWebhook)
+		return
+	}
+
+	w.Secret = ""
+	c.JSON(http.StatusOK, w)
+}
+
+// handleDeleteWebhook deletes a webhook by ID.
+func (h *WebhooksHandler) handleDeleteWebhook(c *gin.Context) {
+	w, ok := h.getOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooksService.DeleteById(c.Request.Context(), w.ID); err != nil {
+		c.Error(ErrFailedToDeleteWebhook)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// handleGetDeliveries retrieves the delivery log of a webhook.
+func (h *WebhooksHandler) handleGetDeliveries(c *gin.Context) {
+	w, ok := h.getOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhooksService.GetDeliveries(c.Request.Context(), w.ID)
+	if err != nil {
+		c.Error(ErrFailedToRetrieveDelivery)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// handleRedeliver queues a delivery again.
+func (h *WebhooksHandler) handleRedeliver(c *gin.Context) {
+	w, ok := h.getOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	deliveryId, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidDeliveryId)
+		return
+	}
+
+	delivery, err := h.webhooksService.Redeliver(c.Request.Context(), w.ID, deliveryId)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, webhook.ErrDeliveryMismatch) {
+		c.Error(ErrDeliveryNotFound)
+		return
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRedeliver)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// getOwnWebhook loads the webhook from the path and writes an error response
+// unless it belongs to the authenticated user.
+func (h *WebhooksHandler) getOwnWebhook(c *gin.Context) (domain.Webhook, bool) {
+	webhookId, err := strconv.ParseInt(c.Param("webhookId"), 10, 64)
+	if err != nil {
+		c.Error(ErrInvalidWebhookId)
+		return domain.Webhook{}, false
+	}
+
+	w, err := h.webhooksService.GetById(c.Request.Context(), webhookId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && w.UserId != c.GetInt64("user-id")) {
+		c.Error(ErrWebhookNotFound)
+		return domain.Webhook{}, false
+	}
+
+	if err != nil {
+		c.Error(ErrFailedToRetrieveWebhooks)
+		return domain.Webhook{}, false
+	}
+
+	return w, true
+}
+
+func webhookValidationError(err error) *appErrors.ResponseError {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidURL):
+		return ErrInvalidWebhookURL
+	case errors.Is(err, webhook.ErrNonPublicURL):
+		return ErrNonPublicWebhookURL
+	case errors.Is(err, webhook.ErrInvalidEventType):
+		return ErrInvalidEventType
+	}
+
+	return nil
+}
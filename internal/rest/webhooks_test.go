@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/krau5/hyper-todo/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWebhookHandler(t *testing.T) {
+	eventTypes := []domain.EventType{domain.EventTaskCreated}
+	mockWebhook := domain.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "secret", EventTypes: eventTypes, Active: true, UserId: userId}
+
+	r, webhooksService := setupWebhooksTest(t)
+	webhooksService.On("Create", mock.Anything, mockWebhook.URL, eventTypes, userId).Return(mockWebhook, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.CreateWebhookBody{URL: mockWebhook.URL, EventTypes: eventTypes})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks", &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(mockWebhook)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestCreateWebhookHandler_InvalidURL(t *testing.T) {
+	r, webhooksService := setupWebhooksTest(t)
+	webhooksService.On("Create", mock.Anything, "not a url", []domain.EventType(nil), userId).Return(domain.Webhook{}, webhook.ErrInvalidURL)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(api.CreateWebhookBody{URL: "not a url"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks", &buf)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrInvalidWebhookURL)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestGetWebhooksHandler_HidesSecrets(t *testing.T) {
+	r, webhooksService := setupWebhooksTest(t)
+	webhooksService.On("GetByUser", mock.Anything, userId).Return([]domain.Webhook{{ID: 1, URL: "https://example.com/hook", Secret: "secret"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/webhooks", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+}
+
+func TestGetWebhookHandler_ForeignWebhook(t *testing.T) {
+	r, webhooksService := setupWebhooksTest(t)
+	webhooksService.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, UserId: userId + 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/webhooks/1", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrWebhookNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestRedeliverHandler(t *testing.T) {
+	r, webhooksService := setupWebhooksTest(t)
+	webhooksService.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, UserId: userId}, nil)
+	webhooksService.On("Redeliver", mock.Anything, int64(1), int64(9)).Return(domain.WebhookDelivery{}, domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks/1/deliveries/9/redeliver", nil)
+	r.ServeHTTP(w, req)
+
+	expectedBody, _ := json.Marshal(ErrDeliveryNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func setupWebhooksTest(t *testing.T) (*gin.Engine, *mocks.WebhooksService) {
+	gin.SetMode(gin.TestMode)
+
+	webhooksService := mocks.NewWebhooksService(t)
+	h := &WebhooksHandler{webhooksService: webhooksService}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/webhooks", h.handleGetWebhooks)
+	r.POST("/webhooks", h.handleCreateWebhook)
+	r.GET("/webhooks/:webhookId", h.handleGetWebhook)
+	r.POST("/webhooks/:webhookId/deliveries/:deliveryId/redeliver", h.handleRedeliver)
+
+	return r, webhooksService
+}
@@ -0,0 +1,447 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"golang.org/x/net/websocket"
+)
+
+// EventsSubscriber provides a stream of domain events.
+type EventsSubscriber interface {
+	Subscribe(events.Handler) func()
+}
+
+// WSConfig defines heartbeat and backpressure settings for WebSocket clients.
+type WSConfig struct {
+	PingInterval time.Duration // How often the server sends a ping message
+	PongWait     time.Duration // How long the server waits for any client message before disconnecting
+	WriteWait    time.Duration // Time allowed to write a single message
+	SendBuffer   int           // Number of outgoing messages queued before a client is considered too slow
+
+	// AllowedOrigins are the other origins browsers may connect from, or any
+	// origin if they include "*". Same-origin connections are always allowed.
+	AllowedOrigins []string
+}
+
+// DefaultWSConfig returns the settings used by the API server.
+func DefaultWSConfig() WSConfig {
+	return WSConfig{
+		PingInterval: 30 * time.Second,
+		PongWait:     60 * time.Second,
+		WriteWait:    10 * time.Second,
+		SendBuffer:   64,
+	}
+}
+
+// WSHandler handles WebSocket connections.
+type WSHandler struct {
+	tasksService TasksService
+	subscriber   EventsSubscriber
+	sessions     middleware.SessionChecker
+	config       WSConfig
+}
+
+var (
+	ErrTaskForbidden        = appErrors.NewResponseError(http.StatusForbidden, "task_forbidden", "task belongs to another user")
+	ErrInvalidMessage       = appErrors.NewResponseError(http.StatusBadRequest, "invalid_message", "invalid message")
+	ErrUnknownMessageType   = appErrors.NewResponseError(http.StatusBadRequest, "unknown_message_type", "unknown message type")
+	ErrInvalidTopic         = appErrors.NewResponseError(http.StatusBadRequest, "invalid_topic", "topic is missing or invalid")
+	ErrFailedToRetrieveTask = appErrors.NewResponseError(http.StatusInternalServerError, "failed_to_retrieve_task", "failed to retrieve task")
+)
+
+// NewWSHandler registers the WebSocket handler with the Gin engine. The
+// session of a connection is checked again with every heartbeat and before
+// every task change, and the connection is closed once the account is locked
+// or deleted or its sessions are revoked.
+func NewWSHandler(r gin.IRouter, auth gin.HandlerFunc, tasksService TasksService, subscriber EventsSubscriber, sessions middleware.SessionChecker, config WSConfig) {
+	h := &WSHandler{
+		tasksService: tasksService,
+		subscriber:   subscriber,
+		sessions:     sessions,
+		config:       config,
+	}
+
+	r.GET("/ws", auth, h.handleWS)
+}
+
+// handleWS upgrades the connection to a WebSocket.
+func (h *WSHandler) handleWS(c *gin.Context) {
+	userId := c.GetInt64("user-id")
+	issuedAt := c.GetTime("token-issued-at")
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(conn *websocket.Conn) {
+			h.serve(conn, userId, issuedAt)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin rejects browser connections initiated by origins that are
+// neither the API's own nor allowed, since the token cookie would otherwise
+// be sent along with them.
+func (h *WSHandler) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(u.Host, req.Host) && !slices.Contains(h.config.AllowedOrigins, origin) && !slices.Contains(h.config.AllowedOrigins, "*") {
+		return fmt.Errorf("origin %q is not allowed", origin)
+	}
+
+	config.Origin = u
+	return nil
+}
+
+func (h *WSHandler) serve(conn *websocket.Conn, userId int64, issuedAt time.Time) {
+	client := newWSClient(conn, userId, h.config.SendBuffer)
+	client.issuedAt = issuedAt
+	defer client.close()
+
+	unsubscribe := h.subscriber.Subscribe(client.handleEvent)
+	defer unsubscribe()
+
+	// Hijacked connections are not drained by http.Server.Shutdown, so they
+	// are closed once the server's base context is canceled.
+	go func() {
+		select {
+		case <-conn.Request().Context().Done():
+			client.close()
+		case <-client.done:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.writeLoop(conn, client)
+	}()
+
+	h.readLoop(conn, client)
+	client.close()
+	wg.Wait()
+}
+
+func (h *WSHandler) readLoop(conn *websocket.Conn, client *wsClient) {
+	ctx := conn.Request().Context()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(h.config.PongWait))
+
+		var req api.WSRequest
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				client.enqueue(api.WSMessage{Type: api.WSTypeError, Error: ErrInvalidMessage})
+				continue
+			}
+			return
+		}
+
+		if req.Type == api.WSTypePong {
+			continue
+		}
+
+		switch req.Type {
+		case api.WSTypeTaskCreate, api.WSTypeTaskUpdate, api.WSTypeTaskDelete:
+			if err := h.checkSession(ctx, client); err != nil {
+				h.endSession(conn, client, req.ID, err)
+				return
+			}
+		}
+
+		client.enqueue(h.handleRequest(ctx, client, req))
+	}
+}
+
+// checkSession fails if the account of the client was locked or deleted, or
+// its sessions were revoked, since the connection was opened. Other failures
+// are ignored so that clients aren't all disconnected while the database is
+// unavailable.
+func (h *WSHandler) checkSession(ctx context.Context, client *wsClient) *appErrors.ResponseError {
+	if h.sessions == nil {
+		return nil
+	}
+
+	err := h.sessions.CheckSession(ctx, client.userId, client.issuedAt)
+	switch {
+	case errors.Is(err, domain.ErrAccountLocked), errors.Is(err, domain.ErrSessionRevoked):
+		return appErrors.FromError(err)
+	case errors.Is(err, domain.ErrNotFound):
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// endSession tells the client why its session ended and closes the
+// connection.
+func (h *WSHandler) endSession(conn *websocket.Conn, client *wsClient, id string, err *appErrors.ResponseError) {
+	conn.SetWriteDeadline(time.Now().Add(h.config.WriteWait))
+	websocket.JSON.Send(conn, api.WSMessage{ID: id, Type: api.WSTypeError, Error: err})
+	client.close()
+}
+
+func (h *WSHandler) writeLoop(conn *websocket.Conn, client *wsClient) {
+	ctx := conn.Request().Context()
+
+	ticker := time.NewTicker(h.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		var msg api.WSMessage
+
+		select {
+		case <-client.done:
+			return
+		case msg = <-client.send:
+		case <-ticker.C:
+			if err := h.checkSession(ctx, client); err != nil {
+				h.endSession(conn, client, "", err)
+				return
+			}
+			msg = api.WSMessage{Type: api.WSTypePing}
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(h.config.WriteWait))
+		if err := websocket.JSON.Send(conn, msg); err != nil {
+			client.close()
+			return
+		}
+	}
+}
+
+func (h *WSHandler) handleRequest(ctx context.Context, client *wsClient, req api.WSRequest) api.WSMessage {
+	var (
+		data any
+		err  *appErrors.ResponseError
+	)
+
+	switch req.Type {
+	case api.WSTypeSubscribe:
+		err = h.subscribe(ctx, client, req.Topic)
+	case api.WSTypeUnsubscribe:
+		client.unsubscribe(req.Topic)
+	case api.WSTypeTaskCreate:
+		data, err = h.createTask(ctx, client.userId, req.Data)
+	case api.WSTypeTaskUpdate:
+		data, err = h.updateTask(ctx, client.userId, req.TaskId, req.Data)
+	case api.WSTypeTaskDelete:
+		err = h.deleteTask(ctx, client.userId, req.TaskId)
+	default:
+		err = ErrUnknownMessageType
+	}
+
+	if err != nil {
+		return api.WSMessage{ID: req.ID, Type: api.WSTypeError, Error: err}
+	}
+
+	return api.WSMessage{ID: req.ID, Type: api.WSTypeResult, Data: data}
+}
+
+func (h *WSHandler) subscribe(ctx context.Context, client *wsClient, topic string) *appErrors.ResponseError {
+	if topic == api.WSTopicTasks {
+		client.subscribe(topic)
+		return nil
+	}
+
+	rawTaskId, ok := strings.CutPrefix(topic, "task:")
+	if !ok {
+		return ErrInvalidTopic
+	}
+
+	taskId, err := strconv.ParseInt(rawTaskId, 10, 64)
+	if err != nil {
+		return ErrInvalidTopic
+	}
+
+	if _, err := h.getOwnTask(ctx, client.userId, taskId); err != nil {
+		return err
+	}
+
+	client.subscribe(topic)
+	return nil
+}
+
+func (h *WSHandler) createTask(ctx context.Context, userId int64, raw json.RawMessage) (any, *appErrors.ResponseError) {
+	var data api.CreateTaskBody
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, appErrors.ErrInvalidBody
+	}
+
+	deadline, err := time.Parse(time.RFC3339, data.Deadline)
+	if err != nil {
+		return nil, ErrInvalidDeadline
+	}
+
+	task, err := h.tasksService.Create(ctx, data.Name, data.Description, deadline, userId)
+	var quotaErr *domain.QuotaError
+	if errors.As(err, &quotaErr) {
+		return nil, appErrors.FromError(quotaErr)
+	}
+
+	if err != nil {
+		return nil, ErrFailedToCreateTask
+	}
+
+	return task, nil
+}
+
+func (h *WSHandler) updateTask(ctx context.Context, userId, taskId int64, raw json.RawMessage) (any, *appErrors.ResponseError) {
+	var data domain.UpdateTaskData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, appErrors.ErrInvalidBody
+	}
+
+	if _, err := h.getOwnTask(ctx, userId, taskId); err != nil {
+		return nil, err
+	}
+
+	task, err := h.tasksService.UpdateById(ctx, taskId, data)
+	if err != nil {
+		return nil, updateTaskError(err)
+	}
+
+	return task, nil
+}
+
+func (h *WSHandler) deleteTask(ctx context.Context, userId, taskId int64) *appErrors.ResponseError {
+	if _, err := h.getOwnTask(ctx, userId, taskId); err != nil {
+		return err
+	}
+
+	if err := h.tasksService.DeleteById(ctx, taskId); err != nil {
+		return ErrFailedToDeleteTask
+	}
+
+	return nil
+}
+
+func (h *WSHandler) getOwnTask(ctx context.Context, userId, taskId int64) (domain.Task, *appErrors.ResponseError) {
+	if taskId == 0 {
+		return domain.Task{}, ErrInvalidTaskId
+	}
+
+	task, err := h.tasksService.GetById(ctx, taskId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Task{}, ErrTaskNotFound
+	}
+
+	if err != nil {
+		return domain.Task{}, ErrFailedToRetrieveTask
+	}
+
+	if task.UserId != userId {
+		return domain.Task{}, ErrTaskForbidden
+	}
+
+	return task, nil
+}
+
+// wsClient tracks the subscriptions and the outgoing queue of a connection.
+type wsClient struct {
+	conn     io.Closer
+	userId   int64
+	issuedAt time.Time // When the token the connection was opened with was issued
+	send     chan api.WSMessage
+	done     chan struct{}
+
+	mu     sync.RWMutex
+	topics map[string]struct{}
+
+	closeOnce sync.Once
+}
+
+func newWSClient(conn io.Closer, userId int64, sendBuffer int) *wsClient {
+	return &wsClient{
+		conn:   conn,
+		userId: userId,
+		send:   make(chan api.WSMessage, sendBuffer),
+		done:   make(chan struct{}),
+		topics: make(map[string]struct{}),
+	}
+}
+
+// enqueue queues a message without blocking. A client whose queue is full
+// is too slow to keep up and gets disconnected.
+func (c *wsClient) enqueue(msg api.WSMessage) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.close()
+		return false
+	}
+}
+
+func (c *wsClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func (c *wsClient) subscribe(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.topics[topic] = struct{}{}
+}
+
+func (c *wsClient) unsubscribe(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.topics, topic)
+}
+
+func (c *wsClient) isSubscribed(event domain.Event) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.topics[api.WSTopicTasks]; ok {
+		return true
+	}
+
+	_, ok := c.topics[fmt.Sprintf("task:%d", event.Task.ID)]
+	return ok
+}
+
+func (c *wsClient) handleEvent(_ context.Context, event domain.Event) {
+	if event.UserId != c.userId || !c.isSubscribed(event) {
+		return
+	}
+
+	c.enqueue(api.WSMessage{Type: api.WSTypeEvent, Event: &event})
+}
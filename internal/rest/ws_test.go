@@ -0,0 +1,293 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/internal/rest/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestWSHandler_ReceivesSubscribedEvents(t *testing.T) {
+	conn, _, bus := setupWSTest(t, testWSConfig(), nil)
+
+	send(t, conn, api.WSRequest{ID: "1", Type: api.WSTypeSubscribe, Topic: api.WSTopicTasks})
+	assert.Equal(t, api.WSMessage{ID: "1", Type: api.WSTypeResult}, receive(t, conn))
+
+	otherUserTask := domain.Task{ID: 2, Name: "other", UserId: userId + 1}
+	ownTask := domain.Task{ID: 1, Name: "own", UserId: userId}
+	bus.Publish(context.TODO(), domain.NewTaskEvent(domain.EventTaskCreated, otherUserTask))
+	bus.Publish(context.TODO(), domain.NewTaskEvent(domain.EventTaskCreated, ownTask))
+
+	msg := receive(t, conn)
+	assert.Equal(t, api.WSTypeEvent, msg.Type)
+	assert.Equal(t, domain.EventTaskCreated, msg.Event.Type)
+	assert.Equal(t, ownTask.ID, msg.Event.Task.ID)
+}
+
+func TestWSHandler_SubscribeToForeignTask(t *testing.T) {
+	conn, tasksService, _ := setupWSTest(t, testWSConfig(), nil)
+	tasksService.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, UserId: userId + 1}, nil)
+
+	send(t, conn, api.WSRequest{ID: "1", Type: api.WSTypeSubscribe, Topic: "task:1"})
+
+	msg := receive(t, conn)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, api.WSTypeError, msg.Type)
+	assert.Equal(t, ErrTaskForbidden.Status, msg.Error.Status)
+}
+
+func TestWSHandler_CreateTask(t *testing.T) {
+	rawDeadline := "2025-01-01T22:22:22Z"
+	deadline, _ := time.Parse(time.RFC3339, rawDeadline)
+	mockTask := domain.Task{ID: 1, Name: "eat", Description: "eat the pizza", Deadline: deadline, UserId: userId}
+
+	conn, tasksService, _ := setupWSTest(t, testWSConfig(), nil)
+	tasksService.On("Create", mock.Anything, mockTask.Name, mockTask.Description, deadline, userId).Return(mockTask, nil)
+
+	data, _ := json.Marshal(api.CreateTaskBody{Name: mockTask.Name, Description: mockTask.Description, Deadline: rawDeadline})
+	send(t, conn, api.WSRequest{ID: "create-1", Type: api.WSTypeTaskCreate, Data: data})
+
+	msg := receive(t, conn)
+	assert.Equal(t, "create-1", msg.ID)
+	assert.Equal(t, api.WSTypeResult, msg.Type)
+
+	expectedData, _ := json.Marshal(mockTask)
+	actualData, _ := json.Marshal(msg.Data)
+	assert.JSONEq(t, string(expectedData), string(actualData))
+}
+
+func TestWSHandler_CreateTaskQuotaExceeded(t *testing.T) {
+	rawDeadline := "2025-01-01T22:22:22Z"
+	deadline, _ := time.Parse(time.RFC3339, rawDeadline)
+
+	conn, tasksService, _ := setupWSTest(t, testWSConfig(), nil)
+	tasksService.On("Create", mock.Anything, "eat", "", deadline, userId).
+		Return(domain.Task{}, &domain.QuotaError{Resource: domain.QuotaTasks, Limit: 10})
+
+	data, _ := json.Marshal(api.CreateTaskBody{Name: "eat", Deadline: rawDeadline})
+	send(t, conn, api.WSRequest{ID: "create-1", Type: api.WSTypeTaskCreate, Data: data})
+
+	msg := receive(t, conn)
+	assert.Equal(t, "create-1", msg.ID)
+	assert.Equal(t, api.WSTypeError, msg.Type)
+	assert.Equal(t, http.StatusForbidden, msg.Error.Status)
+	assert.Equal(t, "quota_exceeded", msg.Error.Code)
+}
+
+func TestWSHandler_UnknownMessageType(t *testing.T) {
+	conn, _, _ := setupWSTest(t, testWSConfig(), nil)
+
+	send(t, conn, api.WSRequest{ID: "1", Type: "task.explode"})
+
+	msg := receive(t, conn)
+	assert.Equal(t, api.WSTypeError, msg.Type)
+	assert.Equal(t, ErrUnknownMessageType.Code, msg.Error.Code)
+}
+
+func TestWSHandler_SendsHeartbeats(t *testing.T) {
+	config := testWSConfig()
+	config.PingInterval = 10 * time.Millisecond
+	conn, _, _ := setupWSTest(t, config, nil)
+
+	msg := receive(t, conn)
+	assert.Equal(t, api.WSTypePing, msg.Type)
+}
+
+func TestWSHandler_DisconnectsSilentClients(t *testing.T) {
+	config := testWSConfig()
+	config.PongWait = 50 * time.Millisecond
+	conn, _, _ := setupWSTest(t, config, nil)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	var msg api.WSMessage
+	err := websocket.JSON.Receive(conn, &msg)
+	assert.Error(t, err)
+}
+
+func TestWSHandler_ClosesOnServerShutdown(t *testing.T) {
+	r, _, _ := setupWSRouter(t, testWSConfig(), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewUnstartedServer(r)
+	server.Config.BaseContext = func(net.Listener) context.Context { return ctx }
+	server.Start()
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, err := websocket.Dial(wsURL, "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cancel()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var msg api.WSMessage
+	err = websocket.JSON.Receive(conn, &msg)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWSHandler_RejectsForeignOrigin(t *testing.T) {
+	config := testWSConfig()
+	config.AllowedOrigins = []string{"https://app.example.com"}
+	r, _, _ := setupWSRouter(t, config, nil)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	_, err := websocket.Dial(wsURL, "", "http://evil.example.com")
+	assert.Error(t, err)
+}
+
+func TestWSHandler_AcceptsAllowedOrigin(t *testing.T) {
+	config := testWSConfig()
+	config.AllowedOrigins = []string{"https://app.example.com"}
+	r, _, _ := setupWSRouter(t, config, nil)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, err := websocket.Dial(wsURL, "", "https://app.example.com")
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestWSHandler_ClosesEndedSessions(t *testing.T) {
+	t.Run("on heartbeats", func(t *testing.T) {
+		config := testWSConfig()
+		config.PingInterval = 10 * time.Millisecond
+		conn, _, _ := setupWSTest(t, config, fakeSessions{domain.ErrSessionRevoked})
+
+		msg := receive(t, conn)
+		assert.Equal(t, api.WSTypeError, msg.Type)
+		assert.Equal(t, appErrors.ErrSessionRevoked.Code, msg.Error.Code)
+
+		err := websocket.JSON.Receive(conn, &msg)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("before task changes", func(t *testing.T) {
+		conn, _, _ := setupWSTest(t, testWSConfig(), fakeSessions{domain.ErrAccountLocked})
+
+		send(t, conn, api.WSRequest{ID: "1", Type: api.WSTypeTaskDelete, TaskId: 1})
+
+		msg := receive(t, conn)
+		assert.Equal(t, "1", msg.ID)
+		assert.Equal(t, api.WSTypeError, msg.Type)
+		assert.Equal(t, appErrors.ErrAccountLocked.Code, msg.Error.Code)
+
+		err := websocket.JSON.Receive(conn, &msg)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("keeps valid sessions", func(t *testing.T) {
+		config := testWSConfig()
+		config.PingInterval = 10 * time.Millisecond
+		conn, _, _ := setupWSTest(t, config, fakeSessions{})
+
+		assert.Equal(t, api.WSTypePing, receive(t, conn).Type)
+		assert.Equal(t, api.WSTypePing, receive(t, conn).Type)
+	})
+}
+
+func TestWSClient_DisconnectsSlowConsumers(t *testing.T) {
+	closer := &fakeCloser{}
+	client := newWSClient(closer, userId, 1)
+
+	assert.True(t, client.enqueue(api.WSMessage{Type: api.WSTypePing}))
+	assert.False(t, client.enqueue(api.WSMessage{Type: api.WSTypePing}))
+	assert.True(t, closer.closed)
+
+	select {
+	case <-client.done:
+	default:
+		t.Error("expected the client to be closed")
+	}
+}
+
+type fakeCloser struct {
+	closed bool
+}
+
+func (c *fakeCloser) Close() error {
+	c.closed = true
+	return nil
+}
+
+type fakeSessions struct {
+	err error
+}
+
+func (s fakeSessions) CheckSession(context.Context, int64, time.Time) error {
+	return s.err
+}
+
+func testWSConfig() WSConfig {
+	return WSConfig{
+		PingInterval: time.Hour,
+		PongWait:     time.Hour,
+		WriteWait:    time.Second,
+		SendBuffer:   16,
+	}
+}
+
+func send(t *testing.T, conn *websocket.Conn, req api.WSRequest) {
+	require.NoError(t, websocket.JSON.Send(conn, req))
+}
+
+func receive(t *testing.T, conn *websocket.Conn) api.WSMessage {
+	var msg api.WSMessage
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, websocket.JSON.Receive(conn, &msg))
+
+	return msg
+}
+
+func setupWSRouter(t *testing.T, config WSConfig, sessions middleware.SessionChecker) (*gin.Engine, *mocks.TasksService, *events.Bus) {
+	gin.SetMode(gin.TestMode)
+
+	tasksService := mocks.NewTasksService(t)
+	bus := events.NewBus()
+	h := &WSHandler{tasksService: tasksService, subscriber: bus, sessions: sessions, config: config}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.Use(func(c *gin.Context) {
+		c.Set("user-id", userId)
+		c.Next()
+	})
+	r.GET("/ws", h.handleWS)
+
+	return r, tasksService, bus
+}
+
+func setupWSTest(t *testing.T, config WSConfig, sessions middleware.SessionChecker) (*websocket.Conn, *mocks.TasksService, *events.Bus) {
+	r, tasksService, bus := setupWSRouter(t, config, sessions)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, err := websocket.Dial(wsURL, "", server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, tasksService, bus
+}
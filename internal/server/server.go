@@ -0,0 +1,317 @@
+// Package server builds the router serving the API from the configuration,
+// wiring the repositories, services and handlers.
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ginzap "github.com/gin-contrib/zap"
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/audit"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/digest"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	"github.com/krau5/hyper-todo/internal/health"
+	"github.com/krau5/hyper-todo/internal/lifecycle"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/internal/migrations"
+	"github.com/krau5/hyper-todo/internal/openapi"
+	"github.com/krau5/hyper-todo/internal/ratelimit"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/krau5/hyper-todo/internal/repository/memory"
+	"github.com/krau5/hyper-todo/internal/rest"
+	appErrors "github.com/krau5/hyper-todo/internal/rest/errors"
+	"github.com/krau5/hyper-todo/internal/rest/middleware"
+	"github.com/krau5/hyper-todo/mail"
+	"github.com/krau5/hyper-todo/notification"
+	"github.com/krau5/hyper-todo/preference"
+	"github.com/krau5/hyper-todo/quota"
+	"github.com/krau5/hyper-todo/reminder"
+	"github.com/krau5/hyper-todo/task"
+	"github.com/krau5/hyper-todo/user"
+	"github.com/krau5/hyper-todo/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// overdueCountTimeout bounds the query counting overdue tasks on scrapes.
+const overdueCountTimeout = 5 * time.Second
+
+// legacyRoutesDeprecatedAt is when the unversioned routes were deprecated in
+// favor of /api/v1.
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// NewRouter returns the router serving the API, with its services wired and
+// their background workers started on the lifecycle. Without a database, i.e.
+// with the memory storage driver, only users and tasks are available. Metrics
+// are registered with the default Prometheus registry, so a process builds a
+// single router.
+func NewRouter(cfg config.Config, db *gorm.DB, logger *zap.Logger, app *lifecycle.Lifecycle) (*gin.Engine, error) {
+	r := gin.Default()
+	if err := r.SetTrustedProxies(trustedProxies(cfg.HTTP.TrustedProxies)); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Tracing())
+	r.Use(ginzap.GinzapWithConfig(logger, &ginzap.Config{
+		TimeFormat: time.RFC3339,
+		UTC:        true,
+		Context:    middleware.AccessLogFields,
+	}))
+	r.Use(ginzap.RecoveryWithZap(logger, true))
+	r.Use(middleware.Logger(logger))
+	r.Use(middleware.ErrorHandler())
+	if origins := cfg.CORS.Origins(); len(origins) != 0 {
+		r.Use(middleware.CORS(origins, cfg.CORS.AllowCredentials, cfg.CORS.MaxAge))
+	}
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) { c.Error(appErrors.ErrRouteNotFound) })
+	r.NoMethod(func(c *gin.Context) { c.Error(appErrors.ErrMethodNotAllowed) })
+	if err := registerHandlers(r, cfg, db, logger, app); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// registerHandlers wires the services and registers the routes.
+func registerHandlers(r *gin.Engine, cfg config.Config, db *gorm.DB, logger *zap.Logger, app *lifecycle.Lifecycle) error {
+	var (
+		usersRepo        user.UsersRepository
+		tasksRepo        task.TasksRepository
+		dependenciesRepo task.DependenciesRepository
+		usageRepo        quota.UsageRepository
+	)
+	if db == nil {
+		store := memory.NewStore()
+		usersRepo = memory.NewUsersRepository(store)
+		tasksRepo = memory.NewTasksRepository(store)
+		dependenciesRepo = memory.NewDependenciesRepository(store)
+		usageRepo = memory.NewUsageRepository(store)
+	} else {
+		usersRepo = repository.NewUserRepository(db)
+		tasksRepo = repository.NewTasksRepository(db)
+		dependenciesRepo = repository.NewDependenciesRepository(db)
+		usageRepo = repository.NewUsageRepository(db)
+	}
+
+	plans, err := cfg.Quota.PlanLimits()
+	if err != nil {
+		return fmt.Errorf("invalid quota plans: %w", err)
+	}
+	quotaService := quota.NewService(quota.Plans{Limits: plans, Default: cfg.Quota.DefaultPlan}, usersRepo, tasksRepo, usageRepo)
+
+	usersService := user.NewService(usersRepo)
+
+	bus := events.NewBus()
+	tasksService := task.NewService(tasksRepo, usersRepo, dependenciesRepo, bus, quotaService)
+
+	prometheus.MustRegister(metrics.NewOverdueCollector(tasksRepo, overdueCountTimeout))
+
+	r.Use(middleware.PrometheusMiddleware())
+	r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+		// Exemplars linking samples to traces require OpenMetrics.
+		EnableOpenMetrics: true,
+	})))
+
+	readiness := health.NewRegistry(cfg.HTTP.HealthTimeout)
+	readiness.Register("shutdown", health.Draining(app.Draining()))
+
+	auth := middleware.AuthMiddleware(cfg.Auth.JwtSecretKey.Value(), usersService)
+
+	spec, err := openapi.Load(api.Spec)
+	if err != nil {
+		return fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	rest.NewPingHandler(r)
+	rest.NewHealthHandler(r, readiness)
+	rest.NewDocsHandler(r, api.Spec)
+
+	// Routes registered above, probes, metrics and docs, are not rate limited
+	// nor counted against quotas, and don't change state so need no CSRF
+	// check.
+	if cfg.RateLimit.Enabled {
+		policies, err := cfg.RateLimit.Policies()
+		if err != nil {
+			return fmt.Errorf("invalid rate limits: %w", err)
+		}
+
+		var store ratelimit.Store = ratelimit.NewMemoryStore()
+		if cfg.RateLimit.Store == config.RateLimitStoreDatabase {
+			store = repository.NewRateLimitStore(db)
+		}
+		r.Use(middleware.RateLimit(store, policies, cfg.Auth.JwtSecretKey.Value()))
+	}
+	if cfg.Auth.CSRFProtection {
+		r.Use(middleware.CSRF(cfg.Auth.JwtSecretKey.Value()))
+	}
+	r.Use(middleware.APIQuota(quotaService, cfg.Auth.JwtSecretKey.Value(), rest.UsageRoute))
+	if cfg.API.ValidateRequests {
+		r.Use(middleware.ValidateRequests(spec))
+	}
+
+	// Version 1 of the API is served under /api/v1 and, while legacy routes
+	// are enabled, at the root. A later version gets its own group sharing
+	// the services.
+	v1, err := apiV1Routers(r, cfg.API)
+	if err != nil {
+		return fmt.Errorf("invalid API settings: %w", err)
+	}
+	wsConfig := rest.DefaultWSConfig()
+	wsConfig.AllowedOrigins = cfg.CORS.Origins()
+	for _, api := range v1 {
+		rest.NewAuthHandler(api, auth, usersService, cfg.Auth)
+		rest.NewTasksHandler(api, auth, tasksService)
+		rest.NewUsersHandler(api, auth, usersService, quotaService)
+		rest.NewDependenciesHandler(api, auth, tasksService, tasksService)
+		rest.NewWSHandler(api, auth, tasksService, bus, usersService, wsConfig)
+	}
+
+	if db == nil {
+		logger.Warn("Webhooks, notifications, reminders, preferences, digests and the admin API are disabled with the memory storage driver")
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("getting the database handle: %w", err)
+	}
+	prometheus.MustRegister(collectors.NewDBStatsCollector(sqlDB, cfg.Storage.Driver))
+
+	readiness.Register("database", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	if cfg.Storage.Driver == config.DriverPostgres {
+		migrator, err := migrations.NewMigrator(db)
+		if err != nil {
+			return fmt.Errorf("loading migrations: %w", err)
+		}
+		readiness.Register("migrations", migrator.Check)
+	}
+
+	webhooksRepo := repository.NewWebhooksRepository(db)
+	deliveriesRepo := repository.NewDeliveriesRepository(db)
+	webhooksService := webhook.NewService(webhooksRepo, deliveriesRepo)
+	// Deliveries are written as events are published, so none is lost once
+	// the request succeeded. The Dispatcher sends them in the background.
+	bus.Subscribe(func(ctx context.Context, event domain.Event) {
+		if err := webhooksService.HandleEvent(ctx, event); err != nil {
+			logger.Error("failed to queue webhook deliveries", zap.String("event", string(event.Type)), zap.Error(err))
+		}
+	})
+
+	dispatcher := webhook.NewDispatcher(webhooksRepo, deliveriesRepo, webhook.DefaultDispatcherConfig())
+	app.Go(func(ctx context.Context) {
+		dispatcher.Run(ctx, func(err error) {
+			logger.Error("failed to dispatch webhook deliveries", zap.Error(err))
+		})
+	})
+
+	preferencesRepo := repository.NewPreferencesRepository(db)
+	preferencesService := preference.NewService(preferencesRepo)
+
+	notificationsRepo := repository.NewNotificationsRepository(db)
+	notificationsService := notification.NewService(notificationsRepo)
+	channels := []notification.Channel{
+		notification.NewInAppChannel(notificationsRepo),
+		notification.NewWebhookChannel(bus, tasksRepo, webhooksRepo),
+	}
+	emailSender := initEmailSender(cfg.SMTP)
+	if emailSender != nil {
+		channels = append(channels, notification.NewEmailChannel(emailSender))
+	}
+	notifier := notification.NewNotifier(channels...)
+
+	remindersRepo := repository.NewRemindersRepository(db)
+	remindersService := reminder.NewService(remindersRepo)
+	scheduler := reminder.NewScheduler(remindersRepo, usersRepo, preferencesService, notifier, reminder.DefaultSchedulerConfig())
+	app.Go(func(ctx context.Context) {
+		scheduler.Run(ctx, func(err error) {
+			logger.Error("failed to send reminders", zap.Error(err))
+		})
+	})
+
+	digestService := digest.NewService(tasksRepo, usersRepo, preferencesService)
+	if emailSender != nil {
+		digestJob := digest.NewJob(digestService, preferencesRepo, emailSender, digest.DefaultJobConfig())
+		app.Go(func(ctx context.Context) {
+			digestJob.Run(ctx, func(err error) {
+				logger.Error("failed to send digests", zap.Error(err))
+			})
+		})
+	}
+
+	auditService := audit.NewService(repository.NewAuditLogsRepository(db))
+
+	for _, api := range v1 {
+		rest.NewWebhooksHandler(api, auth, webhooksService)
+		rest.NewNotificationsHandler(api, auth, notificationsService)
+		rest.NewRemindersHandler(api, auth, tasksService, remindersService)
+		rest.NewPreferencesHandler(api, auth, preferencesService)
+		rest.NewDigestHandler(api, auth, digestService)
+	}
+
+	// Routes added after versioning are only served under /api/v1, the first
+	// of the routers.
+	rest.NewAdminHandler(v1[0], auth, usersService, tasksService, auditService)
+
+	return nil
+}
+
+// apiV1Routers returns the routers serving version 1 of the API: the
+// /api/v1 group and, if enabled, the root one marking legacy routes as
+// deprecated.
+func apiV1Routers(r *gin.Engine, cfg config.APIConfig) ([]gin.IRouter, error) {
+	routers := []gin.IRouter{r.Group("/api/v1")}
+	if !cfg.LegacyRoutes {
+		return routers, nil
+	}
+
+	sunset, err := cfg.Sunset()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(routers, r.Group("/", middleware.Deprecated(legacyRoutesDeprecatedAt, sunset, "/api/v1"))), nil
+}
+
+// trustedProxies splits the comma-separated proxies, nil for none.
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); len(proxy) != 0 {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
+// initEmailSender returns nil if no SMTP server is configured.
+func initEmailSender(cfg config.SMTPConfig) mail.Sender {
+	if len(cfg.Host) == 0 {
+		return nil
+	}
+
+	return mail.NewSMTPSender(mail.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password.Value(),
+		From:     cfg.From,
+	})
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krau5/hyper-todo/api"
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/lifecycle"
+	"github.com/krau5/hyper-todo/internal/openapi"
+	"github.com/krau5/hyper-todo/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// undocumentedRoutes are served outside of the API, so are not in the
+// OpenAPI document.
+var undocumentedRoutes = map[string]bool{
+	"GET /metrics":      true,
+	"GET /openapi.yaml": true,
+	"GET /swagger":      true,
+}
+
+var (
+	testRouter *gin.Engine
+	testSpec   *openapi.Spec
+	testDB     *gorm.DB
+)
+
+// TestMain builds a single router for the tests since services register
+// their metrics globally.
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.Storage.SQLitePath = ":memory:"
+	cfg.Auth.JwtSecretKey = "0123456789abcdef0123456789abcdef"
+	cfg.RateLimit.Enabled = false
+
+	var err error
+	if testSpec, err = openapi.Load(api.Spec); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if testDB, err = repository.OpenSQLite(cfg.Storage.SQLitePath, &gorm.Config{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	app := lifecycle.New()
+	if testRouter, err = NewRouter(cfg, testDB, zap.NewNop(), app); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	app.Shutdown(ctx)
+
+	os.Exit(code)
+}
+
+// TestRoutesDocumented checks that the OpenAPI document and the router
+// agree on the operations of the API.
+func TestRoutesDocumented(t *testing.T) {
+	registered := map[string]bool{}
+	for _, route := range testRouter.Routes() {
+		key := route.Method + " " + route.Path
+		if undocumentedRoutes[key] {
+			continue
+		}
+
+		path := strings.TrimPrefix(route.Path, "/api/v1")
+		if len(path) == 0 {
+			path = "/"
+		}
+		path = openapi.RoutePath(path)
+		registered[route.Method+" "+path] = true
+
+		assert.NotNil(t, testSpec.Operation(route.Method, path), "%s is not in the OpenAPI document", key)
+	}
+
+	for _, op := range testSpec.Operations() {
+		assert.True(t, registered[op.Method+" "+op.Path], "%s %s of the OpenAPI document is not routed", op.Method, op.Path)
+	}
+}
+
+// TestAdminRoutesVersioned checks that routes added after versioning have no
+// legacy alias at the root.
+func TestAdminRoutesVersioned(t *testing.T) {
+	paths := map[string]bool{}
+	for _, route := range testRouter.Routes() {
+		paths[route.Method+" "+route.Path] = true
+	}
+
+	assert.True(t, paths["GET /tasks"], "legacy routes are enabled")
+	assert.True(t, paths["GET /api/v1/admin/users"])
+	assert.False(t, paths["GET /admin/users"])
+}
+
+// client calls the API as a logged in user, checking every response against
+// the OpenAPI document.
+type client struct {
+	t       *testing.T
+	cookies []*http.Cookie
+	csrf    string
+}
+
+func (c *client) do(method, path string, body any) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(c.t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, _ := http.NewRequest(method, "/api/v1"+path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+	if len(c.csrf) != 0 {
+		req.Header.Set(api.CSRFHeader, c.csrf)
+	}
+
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	op := operation(method, req.URL.Path)
+	require.NotNil(c.t, op, "no operation for %s %s", method, path)
+	assert.NoError(c.t, op.ValidateResponse(w.Code, w.Header(), w.Body.Bytes()), "%s %s: %s", method, path, w.Body.String())
+
+	return w
+}
+
+// decode does the request and decodes the response of the expected status.
+func (c *client) decode(method, path string, body any, status int, v any) {
+	c.t.Helper()
+
+	w := c.do(method, path, body)
+	require.Equal(c.t, status, w.Code, "%s %s: %s", method, path, w.Body.String())
+	if v != nil {
+		require.NoError(c.t, json.Unmarshal(w.Body.Bytes(), v))
+	}
+}
+
+// operation returns the operation of the document matching a request path.
+func operation(method, path string) *openapi.Operation {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/v1"), "/")
+	for _, op := range testSpec.Operations() {
+		opSegments := strings.Split(op.Path, "/")
+		if op.Method != method || len(opSegments) != len(segments) {
+			continue
+		}
+
+		matches := true
+		for i, segment := range opSegments {
+			if !strings.HasPrefix(segment, "{") && segment != segments[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return op
+		}
+	}
+
+	return nil
+}
+
+// TestResponsesDocumented goes through the API checking that responses
+// match the OpenAPI document.
+func TestResponsesDocumented(t *testing.T) {
+	c := &client{t: t}
+
+	c.decode("POST", "/register", map[string]string{"name": "John Doe", "email": "john@example.com", "password": "Password_123"}, http.StatusCreated, nil)
+	w := c.do("POST", "/login", map[string]string{"email": "john@example.com", "password": "Password_123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	c.cookies = w.Result().Cookies()
+	for _, cookie := range c.cookies {
+		if cookie.Name == api.CSRFCookie {
+			c.csrf = cookie.Value
+		}
+	}
+
+	c.decode("POST", "/refresh", nil, http.StatusOK, nil)
+	c.decode("GET", "/me", nil, http.StatusOK, nil)
+	c.decode("GET", "/me/usage", nil, http.StatusOK, nil)
+	c.decode("GET", "/me/preferences", nil, http.StatusOK, nil)
+	c.decode("PATCH", "/me/preferences", map[string]any{"timezone": "Europe/Berlin", "digestEnabled": true}, http.StatusOK, nil)
+	c.decode("GET", "/me/digest/preview", nil, http.StatusOK, nil)
+
+	deadline := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	var blocker, blocked struct{ Id int64 }
+	c.decode("POST", "/tasks", map[string]string{"name": "Buy", "description": "Buy the pizza", "deadline": deadline}, http.StatusCreated, &blocker)
+	c.decode("POST", "/tasks", map[string]string{"name": "Eat", "description": "Eat the pizza", "deadline": deadline}, http.StatusCreated, &blocked)
+	c.decode("PATCH", fmt.Sprintf("/tasks/%d", blocker.Id), map[string]string{"description": "Buy a large pizza"}, http.StatusOK, nil)
+
+	c.decode("PUT", fmt.Sprintf("/tasks/%d/blockers/%d", blocked.Id, blocker.Id), nil, http.StatusOK, nil)
+	c.decode("GET", fmt.Sprintf("/tasks/%d/dependencies", blocked.Id), nil, http.StatusOK, nil)
+	c.decode("GET", "/tasks", nil, http.StatusOK, nil)
+	c.decode("GET", "/tasks?blocked=false", nil, http.StatusOK, nil)
+	c.decode("PATCH", fmt.Sprintf("/tasks/%d", blocked.Id), map[string]bool{"completed": true}, http.StatusConflict, nil)
+	c.decode("DELETE", fmt.Sprintf("/tasks/%d/blockers/%d", blocked.Id, blocker.Id), nil, http.StatusOK, nil)
+
+	c.decode("GET", fmt.Sprintf("/tasks/%d/reminders", blocked.Id), nil, http.StatusOK, nil)
+	c.decode("PUT", fmt.Sprintf("/tasks/%d/reminders", blocked.Id), map[string][]string{"offsets": {"24h", "1h"}}, http.StatusOK, nil)
+
+	c.decode("GET", "/notifications", nil, http.StatusOK, nil)
+	c.decode("POST", "/notifications/read-all", nil, http.StatusOK, nil)
+
+	var webhook struct{ Id int64 }
+	c.decode("POST", "/webhooks", map[string]any{"url": "https://203.0.113.10/hooks/tasks", "eventTypes": []string{"task.created"}}, http.StatusCreated, &webhook)
+	c.decode("GET", "/webhooks", nil, http.StatusOK, nil)
+	c.decode("GET", fmt.Sprintf("/webhooks/%d", webhook.Id), nil, http.StatusOK, nil)
+	c.decode("PATCH", fmt.Sprintf("/webhooks/%d", webhook.Id), map[string]bool{"active": false}, http.StatusOK, nil)
+	c.decode("GET", fmt.Sprintf("/webhooks/%d/deliveries", webhook.Id), nil, http.StatusOK, nil)
+	c.decode("DELETE", fmt.Sprintf("/webhooks/%d", webhook.Id), nil, http.StatusOK, nil)
+
+	c.decode("DELETE", fmt.Sprintf("/tasks/%d", blocked.Id), nil, http.StatusOK, nil)
+	c.decode("PATCH", fmt.Sprintf("/tasks/%d", blocked.Id), map[string]string{"name": "Eat"}, http.StatusNotFound, nil)
+	c.decode("POST", "/tasks", map[string]string{"name": "Eat"}, http.StatusBadRequest, nil)
+
+	c.decode("GET", "/admin/users", nil, http.StatusForbidden, nil)
+	c.decode("POST", "/register", map[string]string{"name": "Jane Roe", "email": "jane@example.com", "password": "Password_123"}, http.StatusCreated, nil)
+	users := repository.NewUserRepository(testDB)
+	john, err := users.GetByEmail(context.TODO(), "john@example.com")
+	require.NoError(t, err)
+	require.NoError(t, users.SetRole(context.TODO(), john.ID, domain.RoleAdmin))
+
+	var found []struct{ Id int64 }
+	c.decode("GET", "/admin/users?q=jane&limit=10", nil, http.StatusOK, &found)
+	require.Len(t, found, 1)
+	jane := found[0].Id
+	c.decode("GET", fmt.Sprintf("/admin/users/%d/tasks", jane), nil, http.StatusOK, nil)
+	c.decode("POST", fmt.Sprintf("/admin/users/%d/logout", jane), nil, http.StatusOK, nil)
+	c.decode("POST", fmt.Sprintf("/admin/users/%d/disable", jane), nil, http.StatusOK, nil)
+	c.decode("POST", fmt.Sprintf("/admin/users/%d/enable", jane), nil, http.StatusOK, nil)
+	c.decode("POST", fmt.Sprintf("/admin/users/%d/disable", john.ID), nil, http.StatusConflict, nil)
+	c.decode("GET", fmt.Sprintf("/admin/audit-logs?targetUserId=%d", jane), nil, http.StatusOK, nil)
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krau5/hyper-todo/config"
+	"github.com/krau5/hyper-todo/internal/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("does nothing without exporter", func(t *testing.T) {
+		shutdown, err := Setup(ctx, config.TracingConfig{Exporter: config.ExporterNone})
+		assert.Nil(t, err)
+		assert.Nil(t, shutdown(ctx))
+	})
+
+	t.Run("rejects unknown exporters", func(t *testing.T) {
+		_, err := Setup(ctx, config.TracingConfig{Exporter: "jaeger"})
+		assert.ErrorIs(t, err, ErrUnknownExporter)
+	})
+}
+
+func TestEnd(t *testing.T) {
+	spans := tracingtest.New(t)
+	failure := errors.New("failure")
+
+	work := func(ctx context.Context, fail bool) (err error) {
+		_, span := Start(ctx, "work", attribute.Bool("fail", fail))
+		defer End(span, &err)
+
+		if fail {
+			return failure
+		}
+		return nil
+	}
+
+	assert.Nil(t, work(context.Background(), false))
+	assert.ErrorIs(t, work(context.Background(), true), failure)
+
+	ended := spans.GetSpans()
+	require.Len(t, ended, 2)
+	assert.Equal(t, codes.Unset, ended[0].Status.Code)
+	assert.Empty(t, ended[0].Events)
+	assert.Equal(t, codes.Error, ended[1].Status.Code)
+	assert.Equal(t, "failure", ended[1].Status.Description)
+	assert.Len(t, ended[1].Events, 1)
+}
+
+type record struct {
+	ID    uint
+	Email string
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	require.Nil(t, err)
+	require.Nil(t, db.AutoMigrate(&record{}))
+	require.Nil(t, db.Use(GormPlugin{}))
+
+	spans := tracingtest.New(t)
+	ctx, parent := Start(context.Background(), "parent")
+
+	assert.Nil(t, db.WithContext(ctx).Create(&record{Email: "secret@example.com"}).Error)
+	var found record
+	assert.ErrorIs(t, db.WithContext(ctx).First(&found, 42).Error, gorm.ErrRecordNotFound)
+	assert.NotNil(t, db.WithContext(ctx).Exec("SELECT * FROM missing").Error)
+	parent.End()
+
+	ended := spans.GetSpans()
+	assert.Equal(t, []string{"db.create", "db.query", "db.raw", "parent"}, tracingtest.Names(ended))
+
+	create := ended[0]
+	assert.Equal(t, parent.SpanContext().SpanID(), create.Parent.SpanID())
+	assert.Contains(t, create.Attributes, semconv.DBSystemSqlite)
+	assert.Contains(t, create.Attributes, semconv.DBCollectionName("records"))
+	assert.Contains(t, create.Attributes, attribute.Int64("db.rows_affected", 1))
+	for _, a := range create.Attributes {
+		assert.NotContains(t, a.Value.Emit(), "secret@example.com")
+	}
+
+	assert.Equal(t, codes.Unset, ended[1].Status.Code, "missing records are not errors")
+	assert.Equal(t, codes.Error, ended[2].Status.Code)
+}
@@ -1,28 +1,17 @@
 package utils
 
 import (
-	"errors"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/krau5/hyper-todo/config"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func IsErrDuplicatedKey(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	var perr *pgconn.PgError
-	errors.As(err, &perr)
-
-	return perr.Code == "23505"
-}
-
 // HashPassword generates a bcrypt hash for the given password
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 12)
@@ -36,16 +25,17 @@ func VerifyPassword(password, hash string) bool {
 	return err == nil
 }
 
-func CreateJwt(userId int64) (string, error) {
+// CreateJwt signs a token authenticating the user which expires after ttl.
+func CreateJwt(userId int64, secret string, ttl time.Duration) (string, error) {
 	sub := strconv.FormatInt(userId, 10)
 	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": sub,
 		"iss": "hyper-todo",
-		"exp": time.Now().Add(time.Hour).Unix(),
+		"exp": time.Now().Add(ttl).Unix(),
 		"iat": time.Now().Unix(),
 	})
 
-	tokenString, err := claims.SignedString([]byte(config.Envs.JwtSecretKey))
+	tokenString, err := claims.SignedString([]byte(secret))
 	if err != nil {
 		return "", err
 	}
@@ -53,10 +43,10 @@ func CreateJwt(userId int64) (string, error) {
 	return tokenString, nil
 }
 
-func VerifyJwt(tokenString string) (*jwt.Token, error) {
+func VerifyJwt(tokenString, secret string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		return []byte(config.Envs.JwtSecretKey), nil
-	})
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -68,3 +58,12 @@ func VerifyJwt(tokenString string) (*jwt.Token, error) {
 
 	return token, nil
 }
+
+// CSRFToken derives the CSRF token of an authentication token. Being bound to
+// the session, it can be checked without storing it.
+func CSRFToken(token, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("csrf:" + token))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
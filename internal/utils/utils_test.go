@@ -16,3 +16,13 @@ func TestHashPassword(t *testing.T) {
 	assert.True(t, VerifyPassword(password, hash))
 	assert.False(t, VerifyPassword("Password_321", hash))
 }
+
+func TestCSRFToken(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+
+	token := CSRFToken("a.b.c", secret)
+	assert.Len(t, token, 64)
+	assert.Equal(t, token, CSRFToken("a.b.c", secret))
+	assert.NotEqual(t, token, CSRFToken("a.b.d", secret))
+	assert.NotEqual(t, token, CSRFToken("a.b.c", secret+"0"))
+}
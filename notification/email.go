@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/mail"
+)
+
+// EmailChannel sends notifications to the user's email address.
+type EmailChannel struct {
+	sender mail.Sender
+}
+
+func NewEmailChannel(sender mail.Sender) *EmailChannel {
+	return &EmailChannel{sender: sender}
+}
+
+func (c *EmailChannel) Name() domain.NotificationChannel {
+	return domain.ChannelEmail
+}
+
+func (c *EmailChannel) Send(ctx context.Context, user domain.User, notification domain.Notification) error {
+	return c.sender.Send(ctx, mail.Email{
+		To:      user.Email,
+		Subject: notification.Title,
+		Text:    notification.Body,
+	})
+}
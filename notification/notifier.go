@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+// Channel delivers notifications to users through one medium.
+type Channel interface {
+	Name() domain.NotificationChannel
+	Send(context.Context, domain.User, domain.Notification) error
+}
+
+// Notifier fans notifications out to the channels chosen by the user.
+type Notifier struct {
+	channels map[domain.NotificationChannel]Channel
+}
+
+var (
+	ErrNoChannel = errors.New("none of the user's notification channels is available")
+	// ErrNoSubscriber is returned by channels which have nowhere to deliver
+	// the notification of a user to.
+	ErrNoSubscriber = errors.New("no subscriber for the notification")
+)
+
+func NewNotifier(channels ...Channel) *Notifier {
+	n := &Notifier{channels: make(map[domain.NotificationChannel]Channel)}
+	for _, c := range channels {
+		n.channels[c.Name()] = c
+	}
+
+	return n
+}
+
+// Notify sends the notification through every channel in the user's
+// preferences which is registered with the notifier. It returns the number
+// of channels the notification was sent through and the errors of the
+// failed ones. Channels without a subscriber are skipped like unregistered
+// ones.
+func (n *Notifier) Notify(ctx context.Context, user domain.User, preferences domain.Preferences, notification domain.Notification) (int, error) {
+	var (
+		sent int
+		errs []error
+	)
+
+	for _, name := range preferences.NotificationChannels {
+		channel, ok := n.channels[name]
+		if !ok {
+			continue
+		}
+
+		err := channel.Send(ctx, user, notification)
+		if errors.Is(err, ErrNoSubscriber) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		sent++
+	}
+
+	if sent == 0 && len(errs) == 0 {
+		return 0, ErrNoChannel
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// InAppChannel stores notifications in the user's inbox.
+type InAppChannel struct {
+	notificationsRepo NotificationsRepository
+}
+
+func NewInAppChannel(notificationsRepo NotificationsRepository) *InAppChannel {
+	return &InAppChannel{notificationsRepo: notificationsRepo}
+}
+
+func (c *InAppChannel) Name() domain.NotificationChannel {
+	return domain.ChannelInApp
+}
+
+func (c *InAppChannel) Send(ctx context.Context, user domain.User, notification domain.Notification) error {
+	notification.UserId = user.ID
+	_, err := c.notificationsRepo.Create(ctx, notification)
+	return err
+}
+
+// EventPublisher broadcasts domain events, e.g. to webhooks.
+type EventPublisher interface {
+	Publish(context.Context, domain.Event)
+}
+
+// TaskLoader loads the task a notification refers to.
+type TaskLoader interface {
+	GetById(context.Context, int64) (domain.Task, error)
+}
+
+// WebhookLoader loads the webhooks of a user.
+type WebhookLoader interface {
+	GetByUser(context.Context, int64) ([]domain.Webhook, error)
+}
+
+// WebhookChannel publishes task reminders as events, which are delivered to
+// the user's webhooks subscribed to the task.reminder event type. It fails
+// with ErrNoSubscriber if the user has no such active webhook.
+type WebhookChannel struct {
+	publisher EventPublisher
+	tasks     TaskLoader
+	webhooks  WebhookLoader
+}
+
+func NewWebhookChannel(publisher EventPublisher, tasks TaskLoader, webhooks WebhookLoader) *WebhookChannel {
+	return &WebhookChannel{publisher: publisher, tasks: tasks, webhooks: webhooks}
+}
+
+func (c *WebhookChannel) Name() domain.NotificationChannel {
+	return domain.ChannelWebhook
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, user domain.User, notification domain.Notification) error {
+	if notification.TaskId == 0 {
+		return nil
+	}
+
+	webhooks, err := c.webhooks.GetByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(webhooks, func(w domain.Webhook) bool {
+		return w.Active && w.Subscribes(domain.EventTaskReminder)
+	}) {
+		return ErrNoSubscriber
+	}
+
+	task, err := c.tasks.GetById(ctx, notification.TaskId)
+	if err != nil {
+		return err
+	}
+
+	c.publisher.Publish(ctx, domain.NewTaskEvent(domain.EventTaskReminder, task))
+	return nil
+}
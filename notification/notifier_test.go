@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	"github.com/krau5/hyper-todo/mail"
+	mailMocks "github.com/krau5/hyper-todo/mail/mocks"
+	"github.com/krau5/hyper-todo/notification/mocks"
+	taskMocks "github.com/krau5/hyper-todo/task/mocks"
+	webhookMocks "github.com/krau5/hyper-todo/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotify(t *testing.T) {
+	ctx := context.TODO()
+	user := domain.User{ID: 1, Email: "user@example.com"}
+	notification := domain.Notification{TaskId: 1, Title: "title", Body: "body"}
+
+	t.Run("sends through the channels chosen by the user", func(t *testing.T) {
+		notificationsRepo := mocks.NewNotificationsRepository(t)
+		emailSender := mailMocks.NewSender(t)
+		notifier := NewNotifier(NewInAppChannel(notificationsRepo), NewEmailChannel(emailSender))
+
+		preferences := domain.DefaultPreferences(user.ID)
+		preferences.NotificationChannels = []domain.NotificationChannel{domain.ChannelEmail}
+		emailSender.On("Send", mock.Anything, mail.Email{To: user.Email, Subject: "title", Text: "body"}).Return(nil)
+
+		sent, err := notifier.Notify(ctx, user, preferences, notification)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("reports failed channels", func(t *testing.T) {
+		notificationsRepo := mocks.NewNotificationsRepository(t)
+		emailSender := mailMocks.NewSender(t)
+		notifier := NewNotifier(NewInAppChannel(notificationsRepo), NewEmailChannel(emailSender))
+
+		preferences := domain.DefaultPreferences(user.ID)
+		preferences.NotificationChannels = []domain.NotificationChannel{domain.ChannelInApp, domain.ChannelEmail}
+		notificationsRepo.On("Create", mock.Anything, mock.MatchedBy(func(n domain.Notification) bool {
+			return n.UserId == user.ID
+		})).Return(domain.Notification{}, nil)
+		emailSender.On("Send", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		sent, err := notifier.Notify(ctx, user, preferences, notification)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("skips channels without a subscriber", func(t *testing.T) {
+		webhooksRepo := webhookMocks.NewWebhooksRepository(t)
+		notifier := NewNotifier(NewWebhookChannel(events.NewBus(), taskMocks.NewTasksRepository(t), webhooksRepo))
+
+		preferences := domain.DefaultPreferences(user.ID)
+		preferences.NotificationChannels = []domain.NotificationChannel{domain.ChannelWebhook}
+		webhooksRepo.On("GetByUser", mock.Anything, user.ID).Return([]domain.Webhook{}, nil)
+
+		sent, err := notifier.Notify(ctx, user, preferences, notification)
+		assert.ErrorIs(t, err, ErrNoChannel)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("throws an error if no chosen channel is available", func(t *testing.T) {
+		notifier := NewNotifier()
+
+		_, err := notifier.Notify(ctx, user, domain.DefaultPreferences(user.ID), notification)
+		assert.EqualError(t, err, ErrNoChannel.Error())
+	})
+}
+
+func TestWebhookChannel(t *testing.T) {
+	ctx := context.TODO()
+	user := domain.User{ID: 1, Email: "user@example.com"}
+	notification := domain.Notification{TaskId: 1, Title: "title", Body: "body"}
+	task := domain.Task{ID: 1, Name: "task", UserId: user.ID}
+
+	setup := func(t *testing.T, webhooks []domain.Webhook) (*WebhookChannel, *taskMocks.TasksRepository, *[]domain.Event) {
+		bus := events.NewBus()
+		tasksRepo := taskMocks.NewTasksRepository(t)
+		webhooksRepo := webhookMocks.NewWebhooksRepository(t)
+		webhooksRepo.On("GetByUser", mock.Anything, user.ID).Return(webhooks, nil)
+
+		var published []domain.Event
+		bus.Subscribe(func(_ context.Context, event domain.Event) { published = append(published, event) })
+
+		return NewWebhookChannel(bus, tasksRepo, webhooksRepo), tasksRepo, &published
+	}
+
+	t.Run("publishes reminders for subscribed webhooks", func(t *testing.T) {
+		channel, tasksRepo, published := setup(t, []domain.Webhook{
+			{ID: 1, Active: true, EventTypes: []domain.EventType{domain.EventTaskReminder}},
+		})
+		tasksRepo.On("GetById", mock.Anything, task.ID).Return(task, nil)
+
+		assert.Nil(t, channel.Send(ctx, user, notification))
+		assert.Len(t, *published, 1)
+		assert.Equal(t, domain.EventTaskReminder, (*published)[0].Type)
+	})
+
+	t.Run("fails without an active subscribed webhook", func(t *testing.T) {
+		channel, _, published := setup(t, []domain.Webhook{
+			{ID: 1, Active: false},
+			{ID: 2, Active: true, EventTypes: []domain.EventType{domain.EventTaskCreated}},
+		})
+
+		assert.ErrorIs(t, channel.Send(ctx, user, notification), ErrNoSubscriber)
+		assert.Empty(t, *published)
+	})
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+//go:generate mockery --name NotificationsRepository
+type NotificationsRepository interface {
+	Create(context.Context, domain.Notification) (domain.Notification, error)
+	GetById(context.Context, int64) (domain.Notification, error)
+	GetByUser(ctx context.Context, userId int64, unreadOnly bool) ([]domain.Notification, error)
+	SetRead(ctx context.Context, id int64, read bool) (domain.Notification, error)
+	SetAllRead(ctx context.Context, userId int64) error
+}
+
+// Service manages the in-app notification inbox.
+type Service struct {
+	notificationsRepo NotificationsRepository
+}
+
+var (
+	ErrInvalidId     = errors.New("id is missing or empty")
+	ErrInvalidUserId = errors.New("userId is missing or empty")
+)
+
+func NewService(notificationsRepo NotificationsRepository) *Service {
+	return &Service{notificationsRepo: notificationsRepo}
+}
+
+func (s *Service) GetById(ctx context.Context, id int64) (domain.Notification, error) {
+	if id == 0 {
+		return domain.Notification{}, ErrInvalidId
+	}
+
+	return s.notificationsRepo.GetById(ctx, id)
+}
+
+func (s *Service) GetByUser(ctx context.Context, userId int64, unreadOnly bool) ([]domain.Notification, error) {
+	if userId == 0 {
+		return []domain.Notification{}, ErrInvalidUserId
+	}
+
+	return s.notificationsRepo.GetByUser(ctx, userId, unreadOnly)
+}
+
+func (s *Service) SetRead(ctx context.Context, id int64, read bool) (domain.Notification, error) {
+	if id == 0 {
+		return domain.Notification{}, ErrInvalidId
+	}
+
+	return s.notificationsRepo.SetRead(ctx, id, read)
+}
+
+func (s *Service) SetAllRead(ctx context.Context, userId int64) error {
+	if userId == 0 {
+		return ErrInvalidUserId
+	}
+
+	return s.notificationsRepo.SetAllRead(ctx, userId)
+}
@@ -0,0 +1,126 @@
+package preference
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+//go:generate mockery --name PreferencesRepository
+type PreferencesRepository interface {
+	GetByUser(context.Context, int64) (domain.Preferences, error)
+	Save(context.Context, domain.Preferences) (domain.Preferences, error)
+}
+
+type Service struct {
+	preferencesRepo PreferencesRepository
+}
+
+var (
+	ErrInvalidUserId     = errors.New("userId is missing or empty")
+	ErrInvalidTimezone   = errors.New("timezone is not a valid IANA time zone")
+	ErrInvalidQuietHours = errors.New("quiet hours must both be set in the HH:MM format or both be empty")
+	ErrInvalidChannel    = errors.New("unknown notification channel")
+	ErrInvalidDigestTime = errors.New("digest time must be in the HH:MM format")
+)
+
+// Channels lists the notification channels users can choose from.
+var Channels = []domain.NotificationChannel{
+	domain.ChannelInApp,
+	domain.ChannelEmail,
+	domain.ChannelWebhook,
+}
+
+func NewService(preferencesRepo PreferencesRepository) *Service {
+	return &Service{preferencesRepo: preferencesRepo}
+}
+
+// GetByUser returns the preferences of the user, or the defaults if the user
+// has never changed them.
+func (s *Service) GetByUser(ctx context.Context, userId int64) (domain.Preferences, error) {
+	if userId == 0 {
+		return domain.Preferences{}, ErrInvalidUserId
+	}
+
+	preferences, err := s.preferencesRepo.GetByUser(ctx, userId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultPreferences(userId), nil
+	}
+
+	if err != nil {
+		return domain.Preferences{}, err
+	}
+
+	return preferences, nil
+}
+
+func (s *Service) Update(ctx context.Context, userId int64, data domain.UpdatePreferencesData) (domain.Preferences, error) {
+	preferences, err := s.GetByUser(ctx, userId)
+	if err != nil {
+		return domain.Preferences{}, err
+	}
+
+	if data.Timezone != nil {
+		if _, err := time.LoadLocation(*data.Timezone); err != nil || len(*data.Timezone) == 0 {
+			return domain.Preferences{}, ErrInvalidTimezone
+		}
+		preferences.Timezone = *data.Timezone
+	}
+	if data.QuietHoursStart != nil {
+		preferences.QuietHoursStart = *data.QuietHoursStart
+	}
+	if data.QuietHoursEnd != nil {
+		preferences.QuietHoursEnd = *data.QuietHoursEnd
+	}
+	if data.NotificationChannels != nil {
+		for _, channel := range *data.NotificationChannels {
+			if !isKnownChannel(channel) {
+				return domain.Preferences{}, ErrInvalidChannel
+			}
+		}
+		preferences.NotificationChannels = *data.NotificationChannels
+	}
+	if data.DigestEnabled != nil {
+		preferences.DigestEnabled = *data.DigestEnabled
+	}
+	if data.DigestTime != nil {
+		if _, err := domain.ParseClock(*data.DigestTime); err != nil {
+			return domain.Preferences{}, ErrInvalidDigestTime
+		}
+		preferences.DigestTime = *data.DigestTime
+	}
+
+	if err := validateQuietHours(preferences.QuietHoursStart, preferences.QuietHoursEnd); err != nil {
+		return domain.Preferences{}, err
+	}
+
+	return s.preferencesRepo.Save(ctx, preferences)
+}
+
+func validateQuietHours(start, end string) error {
+	if len(start) == 0 && len(end) == 0 {
+		return nil
+	}
+
+	if _, err := domain.ParseClock(start); err != nil {
+		return ErrInvalidQuietHours
+	}
+
+	if _, err := domain.ParseClock(end); err != nil {
+		return ErrInvalidQuietHours
+	}
+
+	return nil
+}
+
+func isKnownChannel(channel domain.NotificationChannel) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
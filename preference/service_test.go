@@ -0,0 +1,82 @@
+package preference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/preference/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetByUser(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("returns defaults if the user has no preferences", func(t *testing.T) {
+		service, preferencesRepo := setupTest(t)
+		preferencesRepo.On("GetByUser", mock.Anything, userId).Return(domain.Preferences{}, domain.ErrNotFound)
+
+		preferences, err := service.GetByUser(ctx, userId)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DefaultPreferences(userId), preferences)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("throws an error if timezone is invalid", func(t *testing.T) {
+		service, preferencesRepo := setupTest(t)
+		preferencesRepo.On("GetByUser", mock.Anything, userId).Return(domain.DefaultPreferences(userId), nil)
+
+		timezone := "Mars/Olympus_Mons"
+		_, err := service.Update(ctx, userId, domain.UpdatePreferencesData{Timezone: &timezone})
+		assert.EqualError(t, err, ErrInvalidTimezone.Error())
+	})
+
+	t.Run("throws an error if only one quiet hours bound is set", func(t *testing.T) {
+		service, preferencesRepo := setupTest(t)
+		preferencesRepo.On("GetByUser", mock.Anything, userId).Return(domain.DefaultPreferences(userId), nil)
+
+		start := "22:00"
+		_, err := service.Update(ctx, userId, domain.UpdatePreferencesData{QuietHoursStart: &start})
+		assert.EqualError(t, err, ErrInvalidQuietHours.Error())
+	})
+
+	t.Run("throws an error if channel is unknown", func(t *testing.T) {
+		service, preferencesRepo := setupTest(t)
+		preferencesRepo.On("GetByUser", mock.Anything, userId).Return(domain.DefaultPreferences(userId), nil)
+
+		channels := []domain.NotificationChannel{"pigeon"}
+		_, err := service.Update(ctx, userId, domain.UpdatePreferencesData{NotificationChannels: &channels})
+		assert.EqualError(t, err, ErrInvalidChannel.Error())
+	})
+
+	t.Run("saves the merged preferences", func(t *testing.T) {
+		service, preferencesRepo := setupTest(t)
+		preferencesRepo.On("GetByUser", mock.Anything, userId).Return(domain.DefaultPreferences(userId), nil)
+
+		timezone, start, end := "Europe/Berlin", "22:00", "07:00"
+		expected := domain.DefaultPreferences(userId)
+		expected.Timezone, expected.QuietHoursStart, expected.QuietHoursEnd = timezone, start, end
+		preferencesRepo.On("Save", mock.Anything, expected).Return(expected, nil)
+
+		preferences, err := service.Update(ctx, userId, domain.UpdatePreferencesData{
+			Timezone:        &timezone,
+			QuietHoursStart: &start,
+			QuietHoursEnd:   &end,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, preferences)
+	})
+}
+
+func setupTest(t *testing.T) (*Service, *mocks.PreferencesRepository) {
+	preferencesRepo := mocks.NewPreferencesRepository(t)
+	service := NewService(preferencesRepo)
+
+	return service, preferencesRepo
+}
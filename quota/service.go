@@ -0,0 +1,150 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/tracing"
+	"github.com/krau5/hyper-todo/user"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+//go:generate mockery --name TasksRepository
+type TasksRepository interface {
+	CountByUser(ctx context.Context, userId int64) (int64, error)
+}
+
+//go:generate mockery --name UsageRepository
+type UsageRepository interface {
+	// IncrementAPICalls counts an API call of the user on the day, in the
+	// "2006-01-02" format, and returns the calls of the day.
+	IncrementAPICalls(ctx context.Context, userId int64, day string) (int64, error)
+	GetAPICalls(ctx context.Context, userId int64, day string) (int64, error)
+}
+
+// Plans are the limits of every plan, by name. Users without a plan, or
+// with one which is not configured, get the Default plan.
+type Plans struct {
+	Limits  map[string]domain.Limits
+	Default string
+}
+
+type Service struct {
+	plans     Plans
+	usersRepo user.UsersRepository
+	tasksRepo TasksRepository
+	usageRepo UsageRepository
+}
+
+var ErrInvalidUserId = errors.New("userId is missing or empty")
+
+func NewService(plans Plans, usersRepo user.UsersRepository, tasksRepo TasksRepository, usageRepo UsageRepository) *Service {
+	return &Service{
+		plans:     plans,
+		usersRepo: usersRepo,
+		tasksRepo: tasksRepo,
+		usageRepo: usageRepo,
+	}
+}
+
+// plan returns the name and limits of the plan of the user.
+func (s *Service) plan(ctx context.Context, userId int64) (string, domain.Limits, error) {
+	if userId == 0 {
+		return "", domain.Limits{}, ErrInvalidUserId
+	}
+
+	u, err := s.usersRepo.GetById(ctx, userId)
+	if err != nil {
+		return "", domain.Limits{}, err
+	}
+
+	if limits, ok := s.plans.Limits[u.Plan]; ok {
+		return u.Plan, limits, nil
+	}
+
+	return s.plans.Default, s.plans.Limits[s.plans.Default], nil
+}
+
+// TasksLimit returns how many tasks the user may have, or 0 if their plan
+// doesn't limit them. The limit is enforced when tasks are created, under
+// the same lock as the count of the user's tasks, so concurrent creations
+// can't exceed it.
+func (s *Service) TasksLimit(ctx context.Context, userId int64) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "quota.Service.TasksLimit", attribute.Int64("user.id", userId))
+	defer tracing.End(span, &err)
+
+	_, limits, err := s.plan(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return limits.Tasks, nil
+}
+
+// UseAPICall counts an API call of the user made at now and returns a
+// QuotaError if it exceeds the calls of the day, in UTC. Refused calls are
+// counted too.
+func (s *Service) UseAPICall(ctx context.Context, userId int64, now time.Time) error {
+	_, limits, err := s.plan(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	day, resetsAt := today(now)
+	calls, err := s.usageRepo.IncrementAPICalls(ctx, userId, day)
+	if err != nil {
+		return err
+	}
+
+	if limits.APICallsPerDay != 0 && calls > limits.APICallsPerDay {
+		return &domain.QuotaError{Resource: domain.QuotaAPICalls, Limit: limits.APICallsPerDay, ResetsAt: resetsAt}
+	}
+
+	return nil
+}
+
+// GetUsage returns the consumption of the quotas of the user at now.
+func (s *Service) GetUsage(ctx context.Context, userId int64, now time.Time) (_ domain.Usage, err error) {
+	ctx, span := tracing.Start(ctx, "quota.Service.GetUsage", attribute.Int64("user.id", userId))
+	defer tracing.End(span, &err)
+
+	plan, limits, err := s.plan(ctx, userId)
+	if err != nil {
+		return domain.Usage{}, err
+	}
+
+	tasks, err := s.tasksRepo.CountByUser(ctx, userId)
+	if err != nil {
+		return domain.Usage{}, err
+	}
+
+	day, resetsAt := today(now)
+	calls, err := s.usageRepo.GetAPICalls(ctx, userId, day)
+	if err != nil {
+		return domain.Usage{}, err
+	}
+
+	return domain.Usage{
+		Plan:     plan,
+		Tasks:    domain.QuotaUsage{Used: tasks, Limit: limit(limits.Tasks)},
+		APICalls: domain.QuotaUsage{Used: calls, Limit: limit(limits.APICallsPerDay), ResetsAt: &resetsAt},
+	}, nil
+}
+
+// today returns the UTC day of now and when it ends.
+func today(now time.Time) (string, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return start.Format(time.DateOnly), start.AddDate(0, 0, 1)
+}
+
+func limit(value int64) *int64 {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,133 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/quota/mocks"
+	userMocks "github.com/krau5/hyper-todo/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var plans = Plans{
+	Limits: map[string]domain.Limits{
+		"free": {Tasks: 2, APICallsPerDay: 3},
+		"pro":  {},
+	},
+	Default: "free",
+}
+
+func setupTest(t *testing.T) (*Service, *userMocks.UsersRepository, *mocks.TasksRepository, *mocks.UsageRepository) {
+	usersRepo := userMocks.NewUsersRepository(t)
+	tasksRepo := mocks.NewTasksRepository(t)
+	usageRepo := mocks.NewUsageRepository(t)
+
+	return NewService(plans, usersRepo, tasksRepo, usageRepo), usersRepo, tasksRepo, usageRepo
+}
+
+func TestTasksLimit(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("returns the limit of the plan of the user", func(t *testing.T) {
+		service, usersRepo, _, _ := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, Plan: "free"}, nil)
+
+		limit, err := service.TasksLimit(ctx, userId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), limit)
+	})
+
+	t.Run("returns the limit of the default plan", func(t *testing.T) {
+		service, usersRepo, _, _ := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, Plan: "unknown"}, nil)
+
+		limit, err := service.TasksLimit(ctx, userId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), limit)
+	})
+
+	t.Run("returns 0 without a limit", func(t *testing.T) {
+		service, usersRepo, _, _ := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, Plan: "pro"}, nil)
+
+		limit, err := service.TasksLimit(ctx, userId)
+		assert.Nil(t, err)
+		assert.Zero(t, limit)
+	})
+}
+
+func TestUseAPICall(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	t.Run("counts calls on the UTC day", func(t *testing.T) {
+		service, usersRepo, _, usageRepo := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId}, nil)
+		usageRepo.On("IncrementAPICalls", mock.Anything, userId, "2025-03-10").Return(int64(3), nil)
+
+		assert.Nil(t, service.UseAPICall(ctx, userId, now))
+	})
+
+	t.Run("refuses calls over the quota until the next day", func(t *testing.T) {
+		service, usersRepo, _, usageRepo := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId}, nil)
+		usageRepo.On("IncrementAPICalls", mock.Anything, userId, "2025-03-10").Return(int64(4), nil)
+
+		err := service.UseAPICall(ctx, userId, now)
+
+		var quotaErr *domain.QuotaError
+		assert.True(t, errors.As(err, &quotaErr))
+		assert.Equal(t, domain.QuotaAPICalls, quotaErr.Resource)
+		assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), quotaErr.ResetsAt)
+	})
+}
+
+func TestGetUsage(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	t.Run("reports usage against the limits of the plan", func(t *testing.T) {
+		service, usersRepo, tasksRepo, usageRepo := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId}, nil)
+		tasksRepo.On("CountByUser", mock.Anything, userId).Return(int64(1), nil)
+		usageRepo.On("GetAPICalls", mock.Anything, userId, "2025-03-10").Return(int64(2), nil)
+
+		usage, err := service.GetUsage(ctx, userId, now)
+		assert.Nil(t, err)
+
+		tasksLimit, callsLimit := int64(2), int64(3)
+		resetsAt := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, domain.Usage{
+			Plan:     "free",
+			Tasks:    domain.QuotaUsage{Used: 1, Limit: &tasksLimit},
+			APICalls: domain.QuotaUsage{Used: 2, Limit: &callsLimit, ResetsAt: &resetsAt},
+		}, usage)
+	})
+
+	t.Run("reports no limits of unlimited plans", func(t *testing.T) {
+		service, usersRepo, tasksRepo, usageRepo := setupTest(t)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, Plan: "pro"}, nil)
+		tasksRepo.On("CountByUser", mock.Anything, userId).Return(int64(10), nil)
+		usageRepo.On("GetAPICalls", mock.Anything, userId, "2025-03-10").Return(int64(20), nil)
+
+		usage, err := service.GetUsage(ctx, userId, now)
+		assert.Nil(t, err)
+		assert.Equal(t, "pro", usage.Plan)
+		assert.Nil(t, usage.Tasks.Limit)
+		assert.Nil(t, usage.APICalls.Limit)
+	})
+
+	t.Run("throws an error if userId is invalid", func(t *testing.T) {
+		service, _, _, _ := setupTest(t)
+
+		_, err := service.GetUsage(ctx, 0, now)
+		assert.ErrorIs(t, err, ErrInvalidUserId)
+	})
+}
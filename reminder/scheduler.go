@@ -0,0 +1,181 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/user"
+)
+
+// PreferencesProvider returns the notification preferences of a user.
+type PreferencesProvider interface {
+	GetByUser(context.Context, int64) (domain.Preferences, error)
+}
+
+// Notifier sends a notification through the channels chosen by the user.
+type Notifier interface {
+	Notify(context.Context, domain.User, domain.Preferences, domain.Notification) (int, error)
+}
+
+// SchedulerConfig defines polling settings of the Scheduler.
+type SchedulerConfig struct {
+	PollInterval time.Duration // How often due reminders are looked up
+	BatchSize    int           // Maximum number of reminders processed per poll
+	Lease        time.Duration // How long a claimed reminder is hidden from other schedulers
+	RetryDelay   time.Duration // Delay before a reminder which failed to be sent is retried
+}
+
+// DefaultSchedulerConfig returns the settings used by the API server.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		PollInterval: time.Minute,
+		BatchSize:    100,
+		Lease:        15 * time.Minute,
+		RetryDelay:   10 * time.Minute,
+	}
+}
+
+// Scheduler sends reminders for tasks approaching their deadline. Due
+// reminders are claimed for a lease while being sent, so several schedulers
+// can run side by side.
+type Scheduler struct {
+	remindersRepo RemindersRepository
+	usersRepo     user.UsersRepository
+	preferences   PreferencesProvider
+	notifier      Notifier
+	config        SchedulerConfig
+	now           func() time.Time
+	onError       func(error)
+}
+
+func NewScheduler(
+	remindersRepo RemindersRepository,
+	usersRepo user.UsersRepository,
+	preferences PreferencesProvider,
+	notifier Notifier,
+	config SchedulerConfig,
+) *Scheduler {
+	return &Scheduler{
+		remindersRepo: remindersRepo,
+		usersRepo:     usersRepo,
+		preferences:   preferences,
+		notifier:      notifier,
+		config:        config,
+		now:           time.Now,
+		onError:       func(error) {},
+	}
+}
+
+// Run processes due reminders until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context, onError func(error)) {
+	s.onError = onError
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		_, err := s.RunOnce(ctx)
+		metrics.ObserveJob("reminders", start, err)
+		if err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends one batch of due reminders and returns how many were sent.
+// Reminders which can't be sent now are postponed, so they don't hold up the
+// others.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	dues, err := s.remindersRepo.ClaimDue(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, due := range dues {
+		next, ok := s.remind(ctx, due)
+		if !ok {
+			if err := s.remindersRepo.Postpone(ctx, due.Reminder.ID, next); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if err := s.remindersRepo.MarkSent(ctx, due.Reminder.ID, due.Task.Deadline); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// remind notifies the task owner and reports whether the reminder is done
+// with, or else when to try again. Reminders falling into the owner's quiet
+// hours are postponed until the quiet hours are over, and those which failed
+// to be sent are retried after the retry delay. Reminders of users who are
+// gone or notified through no channel are done with.
+func (s *Scheduler) remind(ctx context.Context, due domain.DueReminder) (time.Time, bool) {
+	now := s.now()
+	retryAt := now.Add(s.config.RetryDelay)
+
+	preferences, err := s.preferences.GetByUser(ctx, due.Task.UserId)
+	if err != nil {
+		s.onError(err)
+		return retryAt, false
+	}
+
+	if preferences.InQuietHours(now) {
+		return preferences.EndOfQuietHours(now), false
+	}
+
+	owner, err := s.usersRepo.GetById(ctx, due.Task.UserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return time.Time{}, true
+	}
+	if err != nil {
+		s.onError(err)
+		return retryAt, false
+	}
+
+	sent, err := s.notifier.Notify(ctx, owner, preferences, newNotification(due, now, preferences.Location()))
+	if err != nil {
+		s.onError(err)
+	}
+	if err != nil && sent == 0 {
+		return retryAt, false
+	}
+
+	return time.Time{}, true
+}
+
+func newNotification(due domain.DueReminder, now time.Time, loc *time.Location) domain.Notification {
+	left := due.Task.Deadline.Sub(now).Round(time.Minute)
+	title := fmt.Sprintf("%q is due in %s", due.Task.Name, left)
+	if left <= 0 {
+		// Postponed reminders may be sent past the deadline.
+		title = fmt.Sprintf("%q was due %s ago", due.Task.Name, -left)
+	}
+
+	return domain.Notification{
+		TaskId: due.Task.ID,
+		Title:  title,
+		Body: fmt.Sprintf(
+			"%s\n\nDeadline: %s",
+			due.Task.Description,
+			due.Task.Deadline.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"),
+		),
+		UserId: due.Task.UserId,
+	}
+}
@@ -0,0 +1,145 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/reminder/mocks"
+	userMocks "github.com/krau5/hyper-todo/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	owner := domain.User{ID: 1, Name: "user", Email: "user@example.com"}
+	due := domain.DueReminder{
+		Reminder: domain.Reminder{ID: 1, TaskId: 7, RemindBefore: time.Hour},
+		Task:     domain.Task{ID: 7, Name: "eat", Description: "eat the pizza", Deadline: now.Add(time.Hour), UserId: owner.ID},
+	}
+
+	t.Run("notifies the task owner", func(t *testing.T) {
+		scheduler, remindersRepo, usersRepo, notifier := setupSchedulerTest(t, now, domain.DefaultPreferences(owner.ID))
+
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{due}, nil)
+		remindersRepo.On("MarkSent", mock.Anything, due.Reminder.ID, due.Task.Deadline).Return(nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+
+		sent, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, notifier.notifications, 1)
+		assert.Equal(t, `"eat" is due in 1h0m0s`, notifier.notifications[0].Title)
+		assert.Equal(t, due.Task.ID, notifier.notifications[0].TaskId)
+	})
+
+	t.Run("postpones reminders until quiet hours are over", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.QuietHoursStart, preferences.QuietHoursEnd = "11:00", "13:00"
+		scheduler, remindersRepo, _, notifier := setupSchedulerTest(t, now, preferences)
+
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{due}, nil)
+		remindersRepo.On("Postpone", mock.Anything, due.Reminder.ID, time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)).Return(nil)
+
+		sent, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, notifier.notifications)
+	})
+
+	t.Run("honors the time zone of quiet hours", func(t *testing.T) {
+		preferences := domain.DefaultPreferences(owner.ID)
+		preferences.Timezone = "America/New_York"
+		preferences.QuietHoursStart, preferences.QuietHoursEnd = "22:00", "08:00"
+		scheduler, remindersRepo, _, _ := setupSchedulerTest(t, now, preferences)
+
+		newYork, _ := time.LoadLocation("America/New_York")
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{due}, nil)
+		remindersRepo.On("Postpone", mock.Anything, due.Reminder.ID, mock.MatchedBy(func(until time.Time) bool {
+			return until.Equal(time.Date(2025, 1, 1, 8, 0, 0, 0, newYork))
+		})).Return(nil)
+
+		_, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+	})
+
+	t.Run("retries reminders which failed to be sent later", func(t *testing.T) {
+		scheduler, remindersRepo, usersRepo, notifier := setupSchedulerTest(t, now, domain.DefaultPreferences(owner.ID))
+		notifier.err = errors.New("smtp is down")
+		var errs []error
+		scheduler.onError = func(err error) { errs = append(errs, err) }
+
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{due}, nil)
+		remindersRepo.On("Postpone", mock.Anything, due.Reminder.ID, now.Add(10*time.Minute)).Return(nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+
+		sent, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Zero(t, sent)
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("drops reminders of users who are gone", func(t *testing.T) {
+		scheduler, remindersRepo, usersRepo, notifier := setupSchedulerTest(t, now, domain.DefaultPreferences(owner.ID))
+
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{due}, nil)
+		remindersRepo.On("MarkSent", mock.Anything, due.Reminder.ID, due.Task.Deadline).Return(nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(domain.User{}, domain.ErrNotFound)
+
+		_, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, notifier.notifications)
+	})
+
+	t.Run("tells late reminders the task is past due", func(t *testing.T) {
+		scheduler, remindersRepo, usersRepo, notifier := setupSchedulerTest(t, now, domain.DefaultPreferences(owner.ID))
+		late := due
+		late.Task.Deadline = now.Add(-2 * time.Hour)
+
+		remindersRepo.On("ClaimDue", mock.Anything, 100, 15*time.Minute).Return([]domain.DueReminder{late}, nil)
+		remindersRepo.On("MarkSent", mock.Anything, due.Reminder.ID, late.Task.Deadline).Return(nil)
+		usersRepo.On("GetById", mock.Anything, owner.ID).Return(owner, nil)
+
+		_, err := scheduler.RunOnce(ctx)
+		assert.Nil(t, err)
+		require.Len(t, notifier.notifications, 1)
+		assert.Equal(t, `"eat" was due 2h0m0s ago`, notifier.notifications[0].Title)
+	})
+}
+
+type fakeNotifier struct {
+	notifications []domain.Notification
+	err           error
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, _ domain.User, _ domain.Preferences, notification domain.Notification) (int, error) {
+	if n.err != nil {
+		return 0, n.err
+	}
+
+	n.notifications = append(n.notifications, notification)
+	return 1, nil
+}
+
+type fakePreferences struct {
+	preferences domain.Preferences
+}
+
+func (p fakePreferences) GetByUser(context.Context, int64) (domain.Preferences, error) {
+	return p.preferences, nil
+}
+
+func setupSchedulerTest(t *testing.T, now time.Time, preferences domain.Preferences) (*Scheduler, *mocks.RemindersRepository, *userMocks.UsersRepository, *fakeNotifier) {
+	remindersRepo := mocks.NewRemindersRepository(t)
+	usersRepo := userMocks.NewUsersRepository(t)
+	notifier := &fakeNotifier{}
+	scheduler := NewScheduler(remindersRepo, usersRepo, fakePreferences{preferences}, notifier, DefaultSchedulerConfig())
+	scheduler.now = func() time.Time { return now }
+
+	return scheduler, remindersRepo, usersRepo, notifier
+}
@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+//go:generate mockery --name RemindersRepository
+type RemindersRepository interface {
+	GetByTask(context.Context, int64) ([]domain.Reminder, error)
+	ReplaceForTask(ctx context.Context, taskId int64, offsets []time.Duration) ([]domain.Reminder, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.DueReminder, error)
+	MarkSent(ctx context.Context, id int64, deadline time.Time) error
+	Postpone(ctx context.Context, id int64, until time.Time) error
+}
+
+type Service struct {
+	remindersRepo RemindersRepository
+}
+
+// MaxReminders is the maximum number of reminders per task.
+const MaxReminders = 5
+
+// MaxOffset is the longest time before the deadline a reminder can be sent.
+const MaxOffset = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidTaskId    = errors.New("taskId is missing or empty")
+	ErrInvalidOffset    = errors.New("reminder offsets must be positive and at most 30 days")
+	ErrTooManyReminders = errors.New("too many reminders for one task")
+)
+
+func NewService(remindersRepo RemindersRepository) *Service {
+	return &Service{remindersRepo: remindersRepo}
+}
+
+func (s *Service) GetByTask(ctx context.Context, taskId int64) ([]domain.Reminder, error) {
+	if taskId == 0 {
+		return []domain.Reminder{}, ErrInvalidTaskId
+	}
+
+	return s.remindersRepo.GetByTask(ctx, taskId)
+}
+
+// SetForTask replaces the reminders of a task. Duplicate offsets are merged
+// and the reminders are ordered from the earliest to the latest.
+func (s *Service) SetForTask(ctx context.Context, taskId int64, offsets []time.Duration) ([]domain.Reminder, error) {
+	if taskId == 0 {
+		return []domain.Reminder{}, ErrInvalidTaskId
+	}
+
+	unique := make(map[time.Duration]struct{}, len(offsets))
+	for _, offset := range offsets {
+		if offset <= 0 || offset > MaxOffset {
+			return []domain.Reminder{}, ErrInvalidOffset
+		}
+		unique[offset] = struct{}{}
+	}
+
+	if len(unique) > MaxReminders {
+		return []domain.Reminder{}, ErrTooManyReminders
+	}
+
+	sorted := make([]time.Duration, 0, len(unique))
+	for offset := range unique {
+		sorted = append(sorted, offset)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return s.remindersRepo.ReplaceForTask(ctx, taskId, sorted)
+}
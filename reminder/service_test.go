@@ -0,0 +1,58 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/reminder/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetForTask(t *testing.T) {
+	ctx := context.TODO()
+	var taskId int64 = 1
+
+	t.Run("throws an error if taskId is invalid", func(t *testing.T) {
+		service, _ := setupTest(t)
+
+		_, err := service.SetForTask(ctx, 0, []time.Duration{time.Hour})
+		assert.EqualError(t, err, ErrInvalidTaskId.Error())
+	})
+
+	t.Run("throws an error if an offset is out of range", func(t *testing.T) {
+		service, _ := setupTest(t)
+
+		for _, offset := range []time.Duration{0, -time.Hour, MaxOffset + time.Second} {
+			_, err := service.SetForTask(ctx, taskId, []time.Duration{offset})
+			assert.EqualError(t, err, ErrInvalidOffset.Error())
+		}
+	})
+
+	t.Run("throws an error if there are too many reminders", func(t *testing.T) {
+		service, _ := setupTest(t)
+
+		offsets := []time.Duration{time.Minute, time.Hour, 2 * time.Hour, 3 * time.Hour, 4 * time.Hour, 5 * time.Hour}
+		_, err := service.SetForTask(ctx, taskId, offsets)
+		assert.EqualError(t, err, ErrTooManyReminders.Error())
+	})
+
+	t.Run("merges duplicates and orders offsets", func(t *testing.T) {
+		service, remindersRepo := setupTest(t)
+
+		expected := []time.Duration{24 * time.Hour, time.Hour}
+		remindersRepo.On("ReplaceForTask", mock.Anything, taskId, expected).Return([]domain.Reminder{}, nil)
+
+		_, err := service.SetForTask(ctx, taskId, []time.Duration{time.Hour, 24 * time.Hour, time.Hour})
+		assert.Nil(t, err)
+	})
+}
+
+func setupTest(t *testing.T) (*Service, *mocks.RemindersRepository) {
+	remindersRepo := mocks.NewRemindersRepository(t)
+	service := NewService(remindersRepo)
+
+	return service, remindersRepo
+}
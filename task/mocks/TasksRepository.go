@@ -16,6 +16,62 @@ type TasksRepository struct {
 	mock.Mock
 }
 
+// CountByUser provides a mock function with given fields: ctx, userId
+func (_m *TasksRepository) CountByUser(ctx context.Context, userId int64) (int64, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountOverdue provides a mock function with given fields: ctx, now
+func (_m *TasksRepository) CountOverdue(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOverdue")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, name, description, deadline, userId
 func (_m *TasksRepository) Create(ctx context.Context, name string, description string, deadline time.Time, userId int64) (domain.Task, error) {
 	ret := _m.Called(ctx, name, description, deadline, userId)
@@ -44,6 +100,34 @@ func (_m *TasksRepository) Create(ctx context.Context, name string, description
 	return r0, r1
 }
 
+// CreateChecked provides a mock function with given fields: ctx, name, description, deadline, userId, check
+func (_m *TasksRepository) CreateChecked(ctx context.Context, name string, description string, deadline time.Time, userId int64, check func(count int64) error) (domain.Task, error) {
+	ret := _m.Called(ctx, name, description, deadline, userId, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateChecked")
+	}
+
+	var r0 domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int64, func(count int64) error) (domain.Task, error)); ok {
+		return rf(ctx, name, description, deadline, userId, check)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, int64, func(count int64) error) domain.Task); ok {
+		r0 = rf(ctx, name, description, deadline, userId, check)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, int64, func(count int64) error) error); ok {
+		r1 = rf(ctx, name, description, deadline, userId, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteById provides a mock function with given fields: _a0, _a1
 func (_m *TasksRepository) DeleteById(_a0 context.Context, _a1 int64) error {
 	ret := _m.Called(_a0, _a1)
@@ -120,6 +204,34 @@ func (_m *TasksRepository) GetByUser(_a0 context.Context, _a1 int64) ([]domain.T
 	return r0, r1
 }
 
+// Reassign provides a mock function with given fields: ctx, fromUserId, toUserId
+func (_m *TasksRepository) Reassign(ctx context.Context, fromUserId int64, toUserId int64) (int64, error) {
+	ret := _m.Called(ctx, fromUserId, toUserId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reassign")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, fromUserId, toUserId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, fromUserId, toUserId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, fromUserId, toUserId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateById provides a mock function with given fields: _a0, _a1, _a2
 func (_m *TasksRepository) UpdateById(_a0 context.Context, _a1 int64, _a2 domain.UpdateTaskData) (domain.Task, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -6,22 +6,60 @@ import (
 	"time"
 
 	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/internal/tracing"
 	"github.com/krau5/hyper-todo/user"
-	"gorm.io/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 )
 
 //go:generate mockery --name TasksRepository
 type TasksRepository interface {
 	Create(ctx context.Context, name, description string, deadline time.Time, userId int64) (domain.Task, error)
+	// CreateChecked creates a task of the user unless check fails on the
+	// number of tasks the user has, atomically.
+	CreateChecked(ctx context.Context, name, description string, deadline time.Time, userId int64, check func(count int64) error) (domain.Task, error)
 	GetById(context.Context, int64) (domain.Task, error)
 	GetByUser(context.Context, int64) ([]domain.Task, error)
 	UpdateById(context.Context, int64, domain.UpdateTaskData) (domain.Task, error)
 	DeleteById(context.Context, int64) error
+	CountOverdue(ctx context.Context, now time.Time) (int64, error)
+	CountByUser(ctx context.Context, userId int64) (int64, error)
+	Reassign(ctx context.Context, fromUserId, toUserId int64) (int64, error)
+}
+
+//go:generate mockery --name DependenciesRepository
+type DependenciesRepository interface {
+	Create(ctx context.Context, taskId, blockedById int64) error
+	// CreateChecked creates a dependency between tasks of the user unless
+	// check fails on the dependencies the user has, atomically.
+	CreateChecked(ctx context.Context, userId, taskId, blockedById int64, check func([]domain.TaskDependency) error) error
+	Delete(ctx context.Context, taskId, blockedById int64) error
+	GetByUser(context.Context, int64) ([]domain.TaskDependency, error)
+	GetBlockers(context.Context, int64) ([]domain.Task, error)
+	GetBlocked(context.Context, int64) ([]domain.Task, error)
+}
+
+// QuotaChecker returns how many tasks a user may have, or 0 if they are not
+// limited.
+//
+//go:generate mockery --name QuotaChecker
+type QuotaChecker interface {
+	TasksLimit(ctx context.Context, userId int64) (int64, error)
+}
+
+// EventPublisher broadcasts task changes to interested subscribers.
+type EventPublisher interface {
+	Publish(context.Context, domain.Event)
 }
 
 type Service struct {
-	usersRepo user.UsersRepository
-	tasksRepo TasksRepository
+	usersRepo        user.UsersRepository
+	tasksRepo        TasksRepository
+	dependenciesRepo DependenciesRepository
+	publisher        EventPublisher
+	quotas           QuotaChecker
 }
 
 var (
@@ -29,16 +67,27 @@ var (
 	ErrInvalidDescription = errors.New("description is missing or empty")
 	ErrInvalidId          = errors.New("id is missing or empty")
 	ErrInvalidUserId      = errors.New("userId is missing or empty")
+	ErrSelfDependency     = errors.New("task can't be blocked by itself")
+	ErrForeignDependency  = errors.New("tasks must belong to the same user")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrTaskBlocked        = errors.New("task is blocked by tasks which are not completed")
+	ErrSameUser           = errors.New("tasks can't be reassigned to their owner")
 )
 
-func NewService(tasksRepo TasksRepository, usersRepo user.UsersRepository) *Service {
+func NewService(tasksRepo TasksRepository, usersRepo user.UsersRepository, dependenciesRepo DependenciesRepository, publisher EventPublisher, quotas QuotaChecker) *Service {
 	return &Service{
-		tasksRepo: tasksRepo,
-		usersRepo: usersRepo,
+		tasksRepo:        tasksRepo,
+		usersRepo:        usersRepo,
+		dependenciesRepo: dependenciesRepo,
+		publisher:        publisher,
+		quotas:           quotas,
 	}
 }
 
-func (s *Service) Create(ctx context.Context, name, description string, deadline time.Time, userId int64) (domain.Task, error) {
+func (s *Service) Create(ctx context.Context, name, description string, deadline time.Time, userId int64) (_ domain.Task, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.Create", attribute.Int64("user.id", userId))
+	defer tracing.End(span, &err)
+
 	if len(name) == 0 {
 		return domain.Task{}, ErrInvalidName
 	}
@@ -47,20 +96,37 @@ func (s *Service) Create(ctx context.Context, name, description string, deadline
 		return domain.Task{}, ErrInvalidDescription
 	}
 
-	_, err := s.usersRepo.GetById(ctx, userId)
+	_, err = s.usersRepo.GetById(ctx, userId)
 	if err != nil {
 		return domain.Task{}, err
 	}
 
-	task, err := s.tasksRepo.Create(ctx, name, description, deadline, userId)
+	limit, err := s.quotas.TasksLimit(ctx, userId)
 	if err != nil {
 		return domain.Task{}, err
 	}
 
+	task, err := s.tasksRepo.CreateChecked(ctx, name, description, deadline, userId, func(count int64) error {
+		if limit != 0 && count >= limit {
+			return &domain.QuotaError{Resource: domain.QuotaTasks, Limit: limit}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	metrics.TasksCreated.Inc()
+	logging.FromContext(ctx).Info("Task created", zap.Int64("task_id", task.ID))
+	s.publisher.Publish(ctx, domain.NewTaskEvent(domain.EventTaskCreated, task))
+
 	return task, nil
 }
 
-func (s *Service) GetById(ctx context.Context, id int64) (domain.Task, error) {
+func (s *Service) GetById(ctx context.Context, id int64) (_ domain.Task, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.GetById", attribute.Int64("task.id", id))
+	defer tracing.End(span, &err)
+
 	if id == 0 {
 		return domain.Task{}, ErrInvalidId
 	}
@@ -73,14 +139,17 @@ func (s *Service) GetById(ctx context.Context, id int64) (domain.Task, error) {
 	return task, nil
 }
 
-func (s *Service) GetByUser(ctx context.Context, userId int64) ([]domain.Task, error) {
+func (s *Service) GetByUser(ctx context.Context, userId int64) (_ []domain.Task, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.GetByUser", attribute.Int64("user.id", userId))
+	defer tracing.End(span, &err)
+
 	if userId == 0 {
 		return []domain.Task{}, ErrInvalidUserId
 	}
 
-	_, err := s.usersRepo.GetById(ctx, userId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return []domain.Task{}, gorm.ErrRecordNotFound
+	_, err = s.usersRepo.GetById(ctx, userId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return []domain.Task{}, domain.ErrNotFound
 	}
 
 	tasks, err := s.tasksRepo.GetByUser(ctx, userId)
@@ -91,23 +160,228 @@ func (s *Service) GetByUser(ctx context.Context, userId int64) ([]domain.Task, e
 	return tasks, nil
 }
 
-func (s *Service) UpdateById(ctx context.Context, id int64, data domain.UpdateTaskData) (domain.Task, error) {
+// UpdateById updates a task. Completing a task with blockers which are not
+// completed yet fails with ErrTaskBlocked unless data.Force is set.
+func (s *Service) UpdateById(ctx context.Context, id int64, data domain.UpdateTaskData) (_ domain.Task, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.UpdateById", attribute.Int64("task.id", id))
+	defer tracing.End(span, &err)
+
 	if id == 0 {
 		return domain.Task{}, ErrInvalidId
 	}
 
+	completing := data.Completed != nil && *data.Completed
+	wasCompleted := false
+	if completing {
+		// Only transitions to completed are counted.
+		current, err := s.tasksRepo.GetById(ctx, id)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		wasCompleted = current.Completed
+	}
+
+	if completing && !data.Force {
+		blockers, err := s.dependenciesRepo.GetBlockers(ctx, id)
+		if err != nil {
+			return domain.Task{}, err
+		}
+
+		for _, blocker := range blockers {
+			if !blocker.Completed {
+				logging.FromContext(ctx).Info("Task is blocked", zap.Int64("task_id", id), zap.Int64("blocker_id", blocker.ID))
+				return domain.Task{}, ErrTaskBlocked
+			}
+		}
+	}
+
 	task, err := s.tasksRepo.UpdateById(ctx, id, data)
 	if err != nil {
 		return domain.Task{}, err
 	}
 
+	if completing && !wasCompleted {
+		metrics.TasksCompleted.Inc()
+	}
+
+	logging.FromContext(ctx).Info("Task updated", zap.Int64("task_id", id), zap.Bool("forced", data.Force))
+	s.publisher.Publish(ctx, domain.NewTaskEvent(domain.EventTaskUpdated, task))
+
 	return task, nil
 }
 
-func (s *Service) DeleteById(ctx context.Context, id int64) error {
+func (s *Service) DeleteById(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.DeleteById", attribute.Int64("task.id", id))
+	defer tracing.End(span, &err)
+
 	if id == 0 {
 		return ErrInvalidId
 	}
 
-	return s.tasksRepo.DeleteById(ctx, id)
+	task, err := s.tasksRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tasksRepo.DeleteById(ctx, id); err != nil {
+		return err
+	}
+
+	metrics.TasksDeleted.Inc()
+	logging.FromContext(ctx).Info("Task deleted", zap.Int64("task_id", id))
+	s.publisher.Publish(ctx, domain.NewTaskEvent(domain.EventTaskDeleted, task))
+
+	return nil
+}
+
+func (s *Service) CountByUser(ctx context.Context, userId int64) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.CountByUser", attribute.Int64("user.id", userId))
+	defer tracing.End(span, &err)
+
+	if userId == 0 {
+		return 0, ErrInvalidUserId
+	}
+
+	return s.tasksRepo.CountByUser(ctx, userId)
+}
+
+// Reassign moves every task of a user to another and returns how many were
+// moved. Dependencies move along since they only link tasks of the same user.
+// Quotas of the new owner are not enforced.
+func (s *Service) Reassign(ctx context.Context, fromUserId, toUserId int64) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.Reassign", attribute.Int64("user.id", fromUserId), attribute.Int64("to_user.id", toUserId))
+	defer tracing.End(span, &err)
+
+	if fromUserId == 0 || toUserId == 0 {
+		return 0, ErrInvalidUserId
+	}
+
+	if fromUserId == toUserId {
+		return 0, ErrSameUser
+	}
+
+	for _, userId := range []int64{fromUserId, toUserId} {
+		if _, err := s.usersRepo.GetById(ctx, userId); err != nil {
+			return 0, err
+		}
+	}
+
+	count, err := s.tasksRepo.Reassign(ctx, fromUserId, toUserId)
+	if err != nil {
+		return 0, err
+	}
+
+	logging.FromContext(ctx).Info("Tasks reassigned", zap.Int64("from_user_id", fromUserId), zap.Int64("to_user_id", toUserId), zap.Int64("count", count))
+	return count, nil
+}
+
+func (s *Service) GetDependencies(ctx context.Context, id int64) (_ domain.TaskDependencies, err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.GetDependencies", attribute.Int64("task.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return domain.TaskDependencies{}, ErrInvalidId
+	}
+
+	blockedBy, err := s.dependenciesRepo.GetBlockers(ctx, id)
+	if err != nil {
+		return domain.TaskDependencies{}, err
+	}
+
+	blocks, err := s.dependenciesRepo.GetBlocked(ctx, id)
+	if err != nil {
+		return domain.TaskDependencies{}, err
+	}
+
+	return domain.TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// AddBlocker marks the task as blocked by another task of the same user.
+// Adding an existing dependency is a no-op.
+func (s *Service) AddBlocker(ctx context.Context, id, blockerId int64) (err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.AddBlocker", attribute.Int64("task.id", id), attribute.Int64("blocker.id", blockerId))
+	defer tracing.End(span, &err)
+
+	if id == 0 || blockerId == 0 {
+		return ErrInvalidId
+	}
+
+	if id == blockerId {
+		return ErrSelfDependency
+	}
+
+	task, err := s.tasksRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	blocker, err := s.tasksRepo.GetById(ctx, blockerId)
+	if err != nil {
+		return err
+	}
+
+	if task.UserId != blocker.UserId {
+		return ErrForeignDependency
+	}
+
+	// The cycle check and the creation are atomic, or concurrent requests
+	// could each add half of a cycle.
+	err = s.dependenciesRepo.CreateChecked(ctx, task.UserId, id, blockerId, func(dependencies []domain.TaskDependency) error {
+		if isBlockedBy(dependencies, blockerId, id) {
+			logging.FromContext(ctx).Info("Dependency would create a cycle", zap.Int64("task_id", id), zap.Int64("blocker_id", blockerId))
+			return ErrDependencyCycle
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Blocker added", zap.Int64("task_id", id), zap.Int64("blocker_id", blockerId))
+	return nil
+}
+
+func (s *Service) RemoveBlocker(ctx context.Context, id, blockerId int64) (err error) {
+	ctx, span := tracing.Start(ctx, "task.Service.RemoveBlocker", attribute.Int64("task.id", id), attribute.Int64("blocker.id", blockerId))
+	defer tracing.End(span, &err)
+
+	if id == 0 || blockerId == 0 {
+		return ErrInvalidId
+	}
+
+	if err := s.dependenciesRepo.Delete(ctx, id, blockerId); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Blocker removed", zap.Int64("task_id", id), zap.Int64("blocker_id", blockerId))
+	return nil
+}
+
+// isBlockedBy reports whether the task is blocked by the blocker, directly
+// or through other tasks.
+func isBlockedBy(dependencies []domain.TaskDependency, taskId, blockerId int64) bool {
+	blockers := make(map[int64][]int64)
+	for _, d := range dependencies {
+		blockers[d.TaskId] = append(blockers[d.TaskId], d.BlockedById)
+	}
+
+	visited := map[int64]bool{taskId: true}
+	queue := []int64{taskId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range blockers[current] {
+			if next == blockerId {
+				return true
+			}
+
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
 }
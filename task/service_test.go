@@ -6,11 +6,16 @@ import (
 	"time"
 
 	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/internal/tracing/tracingtest"
 	"github.com/krau5/hyper-todo/task/mocks"
 	userMocks "github.com/krau5/hyper-todo/user/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gorm.io/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func TestCreate(t *testing.T) {
@@ -21,7 +26,7 @@ func TestCreate(t *testing.T) {
 	var userId int64 = 1
 
 	t.Run("throws an error if name is invalid", func(t *testing.T) {
-		service, _, _ := setupTest(t)
+		service, _, _, _ := setupTest(t)
 
 		_, err := service.Create(ctx, "", description, deadline, userId)
 		assert.Error(t, err)
@@ -29,7 +34,7 @@ func TestCreate(t *testing.T) {
 	})
 
 	t.Run("throws an error if description is invalid", func(t *testing.T) {
-		service, _, _ := setupTest(t)
+		service, _, _, _ := setupTest(t)
 
 		_, err := service.Create(ctx, name, "", deadline, userId)
 		assert.Error(t, err)
@@ -37,14 +42,68 @@ func TestCreate(t *testing.T) {
 	})
 
 	t.Run("throws an error if the user was not found", func(t *testing.T) {
-		service, _, usersRepo := setupTest(t)
+		service, _, usersRepo, _ := setupTest(t)
 
-		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, gorm.ErrRecordNotFound)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, domain.ErrNotFound)
 
 		_, err := service.Create(ctx, name, description, deadline, userId)
 
 		assert.Error(t, err)
-		assert.EqualError(t, err, gorm.ErrRecordNotFound.Error())
+		assert.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	t.Run("publishes an event after the task was created", func(t *testing.T) {
+		tasksRepo := mocks.NewTasksRepository(t)
+		usersRepo := userMocks.NewUsersRepository(t)
+		bus := events.NewBus()
+		quotas := mocks.NewQuotaChecker(t)
+		service := NewService(tasksRepo, usersRepo, mocks.NewDependenciesRepository(t), bus, quotas)
+
+		mockTask := domain.Task{ID: 1, Name: name, Description: description, Deadline: deadline, UserId: userId}
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, nil)
+		quotas.On("TasksLimit", mock.Anything, userId).Return(int64(0), nil)
+		tasksRepo.On("CreateChecked", mock.Anything, name, description, deadline, userId, mock.Anything).Return(mockTask, nil)
+
+		var received []domain.Event
+		bus.Subscribe(func(_ context.Context, e domain.Event) { received = append(received, e) })
+
+		_, err := service.Create(ctx, name, description, deadline, userId)
+		assert.Nil(t, err)
+		assert.Len(t, received, 1)
+		assert.Equal(t, domain.EventTaskCreated, received[0].Type)
+		assert.Equal(t, mockTask, received[0].Task)
+		assert.Equal(t, userId, received[0].UserId)
+	})
+
+	t.Run("refuses tasks beyond the quota of the user", func(t *testing.T) {
+		tasksRepo := mocks.NewTasksRepository(t)
+		usersRepo := userMocks.NewUsersRepository(t)
+		quotas := mocks.NewQuotaChecker(t)
+		service := NewService(tasksRepo, usersRepo, mocks.NewDependenciesRepository(t), events.NewBus(), quotas)
+
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, nil)
+		quotas.On("TasksLimit", mock.Anything, userId).Return(int64(10), nil)
+		tasksRepo.On("CreateChecked", mock.Anything, name, description, deadline, userId, mock.Anything).Return(withTasks(10))
+
+		_, err := service.Create(ctx, name, description, deadline, userId)
+
+		var quotaErr *domain.QuotaError
+		assert.ErrorAs(t, err, &quotaErr)
+		assert.Equal(t, &domain.QuotaError{Resource: domain.QuotaTasks, Limit: 10}, quotaErr)
+	})
+
+	t.Run("creates tasks under the quota of the user", func(t *testing.T) {
+		tasksRepo := mocks.NewTasksRepository(t)
+		usersRepo := userMocks.NewUsersRepository(t)
+		quotas := mocks.NewQuotaChecker(t)
+		service := NewService(tasksRepo, usersRepo, mocks.NewDependenciesRepository(t), events.NewBus(), quotas)
+
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, nil)
+		quotas.On("TasksLimit", mock.Anything, userId).Return(int64(10), nil)
+		tasksRepo.On("CreateChecked", mock.Anything, name, description, deadline, userId, mock.Anything).Return(withTasks(9))
+
+		_, err := service.Create(ctx, name, description, deadline, userId)
+		assert.Nil(t, err)
 	})
 }
 
@@ -52,7 +111,7 @@ func TestGetById(t *testing.T) {
 	ctx := context.TODO()
 
 	t.Run("throws an error if id is invalid", func(t *testing.T) {
-		service, _, _ := setupTest(t)
+		service, _, _, _ := setupTest(t)
 
 		_, err := service.GetById(ctx, 0)
 		assert.Error(t, err)
@@ -60,7 +119,7 @@ func TestGetById(t *testing.T) {
 	})
 
 	t.Run("returns a task if it was found", func(t *testing.T) {
-		service, tasksRepo, _ := setupTest(t)
+		service, tasksRepo, _, _ := setupTest(t)
 
 		mockTask := domain.Task{
 			Name:        "eat",
@@ -83,7 +142,7 @@ func TestGetByUser(t *testing.T) {
 	var userId int64 = 1
 
 	t.Run("throws an error if userId is invalid", func(t *testing.T) {
-		service, _, _ := setupTest(t)
+		service, _, _, _ := setupTest(t)
 
 		_, err := service.GetByUser(ctx, 0)
 		assert.Error(t, err)
@@ -91,17 +150,17 @@ func TestGetByUser(t *testing.T) {
 	})
 
 	t.Run("throws an error if user was not found", func(t *testing.T) {
-		service, _, usersRepo := setupTest(t)
+		service, _, usersRepo, _ := setupTest(t)
 
-		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, gorm.ErrRecordNotFound)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{}, domain.ErrNotFound)
 
 		_, err := service.GetByUser(ctx, userId)
 		assert.Error(t, err)
-		assert.EqualError(t, err, gorm.ErrRecordNotFound.Error())
+		assert.EqualError(t, err, domain.ErrNotFound.Error())
 	})
 
 	t.Run("retrieves and returns tasks if userId is correct", func(t *testing.T) {
-		service, tasksRepo, usersRepo := setupTest(t)
+		service, tasksRepo, usersRepo, _ := setupTest(t)
 
 		mockTasks := []domain.Task{
 			{Name: "task 1", Description: "description 1", Deadline: time.Now()},
@@ -121,7 +180,7 @@ func TestUpdateById_InvalidId(t *testing.T) {
 	ctx := context.TODO()
 	name := "drink"
 
-	service, _, _ := setupTest(t)
+	service, _, _, _ := setupTest(t)
 
 	mockData := domain.UpdateTaskData{Name: &name}
 	task, err := service.UpdateById(ctx, 0, mockData)
@@ -132,16 +191,210 @@ func TestUpdateById_InvalidId(t *testing.T) {
 
 func TestDeleteById_InvalidId(t *testing.T) {
 	ctx := context.TODO()
-	service, _, _ := setupTest(t)
+	service, _, _, _ := setupTest(t)
 
 	err := service.DeleteById(ctx, 0)
 	assert.EqualError(t, err, ErrInvalidId.Error())
 }
 
-func setupTest(t *testing.T) (*Service, *mocks.TasksRepository, *userMocks.UsersRepository) {
+func TestUpdateById_Blocked(t *testing.T) {
+	ctx := context.TODO()
+	completed := true
+	var taskId int64 = 1
+
+	t.Run("refuses to complete a task with open blockers", func(t *testing.T) {
+		service, tasksRepo, _, dependenciesRepo := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId}, nil)
+		dependenciesRepo.On("GetBlockers", mock.Anything, taskId).Return([]domain.Task{{ID: 2, Completed: true}, {ID: 3}}, nil)
+
+		_, err := service.UpdateById(ctx, taskId, domain.UpdateTaskData{Completed: &completed})
+		assert.ErrorIs(t, err, ErrTaskBlocked)
+	})
+
+	t.Run("records the refusal on the span of the call", func(t *testing.T) {
+		spans := tracingtest.New(t)
+		service, tasksRepo, _, dependenciesRepo := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId}, nil)
+		dependenciesRepo.On("GetBlockers", mock.Anything, taskId).Return([]domain.Task{{ID: 3}}, nil)
+
+		_, err := service.UpdateById(ctx, taskId, domain.UpdateTaskData{Completed: &completed})
+		assert.ErrorIs(t, err, ErrTaskBlocked)
+
+		ended := spans.GetSpans()
+		assert.Equal(t, []string{"task.Service.UpdateById"}, tracingtest.Names(ended))
+		assert.Equal(t, codes.Error, ended[0].Status.Code)
+		assert.Equal(t, ErrTaskBlocked.Error(), ended[0].Status.Description)
+		assert.Contains(t, ended[0].Attributes, attribute.Int64("task.id", taskId))
+	})
+
+	t.Run("completes a task once its blockers are completed", func(t *testing.T) {
+		service, tasksRepo, _, dependenciesRepo := setupTest(t)
+		data := domain.UpdateTaskData{Completed: &completed}
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId}, nil)
+		dependenciesRepo.On("GetBlockers", mock.Anything, taskId).Return([]domain.Task{{ID: 2, Completed: true}}, nil)
+		tasksRepo.On("UpdateById", mock.Anything, taskId, data).Return(domain.Task{ID: taskId, Completed: true}, nil)
+
+		task, err := service.UpdateById(ctx, taskId, data)
+		assert.Nil(t, err)
+		assert.True(t, task.Completed)
+	})
+
+	t.Run("completes a blocked task when forced", func(t *testing.T) {
+		service, tasksRepo, _, _ := setupTest(t)
+		data := domain.UpdateTaskData{Completed: &completed, Force: true}
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId}, nil)
+		tasksRepo.On("UpdateById", mock.Anything, taskId, data).Return(domain.Task{ID: taskId, Completed: true}, nil)
+
+		task, err := service.UpdateById(ctx, taskId, data)
+		assert.Nil(t, err)
+		assert.True(t, task.Completed)
+	})
+}
+
+func TestUpdateById_CompletedMetric(t *testing.T) {
+	ctx := context.TODO()
+	completed := true
+	var taskId int64 = 1
+	data := domain.UpdateTaskData{Completed: &completed, Force: true}
+
+	t.Run("counts a task being completed", func(t *testing.T) {
+		service, tasksRepo, _, _ := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId}, nil)
+		tasksRepo.On("UpdateById", mock.Anything, taskId, data).Return(domain.Task{ID: taskId, Completed: true}, nil)
+
+		before := testutil.ToFloat64(metrics.TasksCompleted)
+		_, err := service.UpdateById(ctx, taskId, data)
+		assert.Nil(t, err)
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.TasksCompleted))
+	})
+
+	t.Run("does not count a task that was already completed", func(t *testing.T) {
+		service, tasksRepo, _, _ := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, taskId).Return(domain.Task{ID: taskId, Completed: true}, nil)
+		tasksRepo.On("UpdateById", mock.Anything, taskId, data).Return(domain.Task{ID: taskId, Completed: true}, nil)
+
+		before := testutil.ToFloat64(metrics.TasksCompleted)
+		_, err := service.UpdateById(ctx, taskId, data)
+		assert.Nil(t, err)
+		assert.Equal(t, before, testutil.ToFloat64(metrics.TasksCompleted))
+	})
+}
+
+func TestAddBlocker(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("adds a dependency", func(t *testing.T) {
+		service, tasksRepo, _, dependenciesRepo := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Task{ID: 1, UserId: userId}, nil)
+		tasksRepo.On("GetById", mock.Anything, int64(2)).Return(domain.Task{ID: 2, UserId: userId}, nil)
+		dependenciesRepo.On("CreateChecked", mock.Anything, userId, int64(1), int64(2), mock.Anything).Return(checkAgainst(nil))
+
+		err := service.AddBlocker(ctx, 1, 2)
+		assert.Nil(t, err)
+	})
+
+	t.Run("rejects a task blocking itself", func(t *testing.T) {
+		service, _, _, _ := setupTest(t)
+
+		err := service.AddBlocker(ctx, 1, 1)
+		assert.ErrorIs(t, err, ErrSelfDependency)
+	})
+
+	t.Run("rejects tasks of another user", func(t *testing.T) {
+		service, tasksRepo, _, _ := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Task{ID: 1, UserId: userId}, nil)
+		tasksRepo.On("GetById", mock.Anything, int64(2)).Return(domain.Task{ID: 2, UserId: userId + 1}, nil)
+
+		err := service.AddBlocker(ctx, 1, 2)
+		assert.ErrorIs(t, err, ErrForeignDependency)
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		service, tasksRepo, _, dependenciesRepo := setupTest(t)
+		tasksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Task{ID: 1, UserId: userId}, nil)
+		tasksRepo.On("GetById", mock.Anything, int64(3)).Return(domain.Task{ID: 3, UserId: userId}, nil)
+		// 3 is blocked by 2, which is blocked by 1.
+		dependenciesRepo.On("CreateChecked", mock.Anything, userId, int64(1), int64(3), mock.Anything).Return(checkAgainst([]domain.TaskDependency{
+			{TaskId: 2, BlockedById: 1},
+			{TaskId: 3, BlockedById: 2},
+		}))
+
+		err := service.AddBlocker(ctx, 1, 3)
+		assert.ErrorIs(t, err, ErrDependencyCycle)
+	})
+}
+
+// checkAgainst returns a CreateChecked implementation running the check on
+// the dependencies.
+func checkAgainst(dependencies []domain.TaskDependency) func(context.Context, int64, int64, int64, func([]domain.TaskDependency) error) error {
+	return func(_ context.Context, _, _, _ int64, check func([]domain.TaskDependency) error) error {
+		return check(dependencies)
+	}
+}
+
+// withTasks returns a TasksRepository.CreateChecked implementation running
+// the check on the number of tasks.
+func withTasks(count int64) func(context.Context, string, string, time.Time, int64, func(int64) error) (domain.Task, error) {
+	return func(_ context.Context, _, _ string, _ time.Time, _ int64, check func(int64) error) (domain.Task, error) {
+		if err := check(count); err != nil {
+			return domain.Task{}, err
+		}
+		return domain.Task{ID: count + 1}, nil
+	}
+}
+
+func setupTest(t *testing.T) (*Service, *mocks.TasksRepository, *userMocks.UsersRepository, *mocks.DependenciesRepository) {
 	tasksRepo := mocks.NewTasksRepository(t)
 	usersRepo := userMocks.NewUsersRepository(t)
-	service := NewService(tasksRepo, usersRepo)
+	dependenciesRepo := mocks.NewDependenciesRepository(t)
+	quotas := mocks.NewQuotaChecker(t)
+	quotas.On("TasksLimit", mock.Anything, mock.Anything).Return(int64(0), nil).Maybe()
+	service := NewService(tasksRepo, usersRepo, dependenciesRepo, events.NewBus(), quotas)
+
+	return service, tasksRepo, usersRepo, dependenciesRepo
+}
+
+func TestReassign(t *testing.T) {
+	ctx := context.TODO()
+	var fromUserId, toUserId int64 = 1, 2
+
+	t.Run("throws an error if a user id is invalid", func(t *testing.T) {
+		service, _, _, _ := setupTest(t)
+
+		_, err := service.Reassign(ctx, 0, toUserId)
+		assert.EqualError(t, err, ErrInvalidUserId.Error())
+
+		_, err = service.Reassign(ctx, fromUserId, 0)
+		assert.EqualError(t, err, ErrInvalidUserId.Error())
+	})
 
-	return service, tasksRepo, usersRepo
+	t.Run("throws an error if the users are the same", func(t *testing.T) {
+		service, _, _, _ := setupTest(t)
+
+		_, err := service.Reassign(ctx, fromUserId, fromUserId)
+		assert.EqualError(t, err, ErrSameUser.Error())
+	})
+
+	t.Run("throws an error if a user was not found", func(t *testing.T) {
+		service, _, usersRepo, _ := setupTest(t)
+
+		usersRepo.On("GetById", mock.Anything, fromUserId).Return(domain.User{ID: fromUserId}, nil)
+		usersRepo.On("GetById", mock.Anything, toUserId).Return(domain.User{}, domain.ErrNotFound)
+
+		_, err := service.Reassign(ctx, fromUserId, toUserId)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("moves the tasks", func(t *testing.T) {
+		service, tasksRepo, usersRepo, _ := setupTest(t)
+
+		usersRepo.On("GetById", mock.Anything, fromUserId).Return(domain.User{ID: fromUserId}, nil)
+		usersRepo.On("GetById", mock.Anything, toUserId).Return(domain.User{ID: toUserId}, nil)
+		tasksRepo.On("Reassign", mock.Anything, fromUserId, toUserId).Return(int64(3), nil)
+
+		count, err := service.Reassign(ctx, fromUserId, toUserId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+	})
 }
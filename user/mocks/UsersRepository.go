@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/krau5/hyper-todo/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UsersRepository is an autogenerated mock type for the UsersRepository type
@@ -88,6 +90,138 @@ func (_m *UsersRepository) GetById(_a0 context.Context, _a1 int64) (domain.User,
 	return r0, r1
 }
 
+// List provides a mock function with given fields: _a0
+func (_m *UsersRepository) List(_a0 context.Context) ([]domain.User, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.User, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.User); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSessions provides a mock function with given fields: ctx, id, revokedAt
+func (_m *UsersRepository) RevokeSessions(ctx context.Context, id int64, revokedAt time.Time) error {
+	ret := _m.Called(ctx, id, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query, limit, offset
+func (_m *UsersRepository) Search(ctx context.Context, query string, limit int, offset int) ([]domain.User, error) {
+	ret := _m.Called(ctx, query, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]domain.User, error)); ok {
+		return rf(ctx, query, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []domain.User); ok {
+		r0 = rf(ctx, query, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, query, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLocked provides a mock function with given fields: ctx, id, lockedAt
+func (_m *UsersRepository) SetLocked(ctx context.Context, id int64, lockedAt *time.Time) error {
+	ret := _m.Called(ctx, id, lockedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *time.Time) error); ok {
+		r0 = rf(ctx, id, lockedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRole provides a mock function with given fields: ctx, id, role
+func (_m *UsersRepository) SetRole(ctx context.Context, id int64, role domain.Role) error {
+	ret := _m.Called(ctx, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Role) error); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, password
+func (_m *UsersRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUsersRepository creates a new instance of UsersRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUsersRepository(t interface {
@@ -3,8 +3,14 @@ package user
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/logging"
+	"github.com/krau5/hyper-todo/internal/metrics"
+	"github.com/krau5/hyper-todo/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 )
 
 //go:generate mockery --name UsersRepository
@@ -12,6 +18,12 @@ type UsersRepository interface {
 	Create(ctx context.Context, name, email, password string) error
 	GetByEmail(context.Context, string) (domain.User, error)
 	GetById(context.Context, int64) (domain.User, error)
+	List(context.Context) ([]domain.User, error)
+	UpdatePassword(ctx context.Context, id int64, password string) error
+	SetLocked(ctx context.Context, id int64, lockedAt *time.Time) error
+	SetRole(ctx context.Context, id int64, role domain.Role) error
+	RevokeSessions(ctx context.Context, id int64, revokedAt time.Time) error
+	Search(ctx context.Context, query string, limit, offset int) ([]domain.User, error)
 }
 
 type Service struct {
@@ -23,13 +35,25 @@ var (
 	ErrInvalidEmail    = errors.New("email is missing or empty")
 	ErrInvalidPassword = errors.New("password is missing or empty")
 	ErrInvalidId       = errors.New("id is missing or empty")
+	ErrInvalidRole     = errors.New("role must be one of user, support or admin")
+	ErrInvalidLimit    = errors.New("limit must be between 1 and 100")
+	ErrInvalidOffset   = errors.New("offset must not be negative")
+)
+
+// Limits of the number of users searched at once.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
 )
 
 func NewService(usersRepo UsersRepository) *Service {
 	return &Service{usersRepo: usersRepo}
 }
 
-func (s *Service) Create(ctx context.Context, name, email, password string) error {
+func (s *Service) Create(ctx context.Context, name, email, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Create")
+	defer tracing.End(span, &err)
+
 	if len(name) == 0 {
 		return ErrInvalidName
 	}
@@ -42,10 +66,22 @@ func (s *Service) Create(ctx context.Context, name, email, password string) erro
 		return ErrInvalidPassword
 	}
 
-	return s.usersRepo.Create(ctx, name, email, password)
+	if err := s.usersRepo.Create(ctx, name, email, password); err != nil {
+		if errors.Is(err, domain.ErrDuplicate) {
+			logging.FromContext(ctx).Info("Email is already registered")
+		}
+		return err
+	}
+
+	metrics.UsersRegistered.Inc()
+	logging.FromContext(ctx).Info("User created")
+	return nil
 }
 
-func (s *Service) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+func (s *Service) GetByEmail(ctx context.Context, email string) (_ domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetByEmail")
+	defer tracing.End(span, &err)
+
 	if len(email) == 0 {
 		return domain.User{}, ErrInvalidEmail
 	}
@@ -58,7 +94,10 @@ func (s *Service) GetByEmail(ctx context.Context, email string) (domain.User, er
 	return user, nil
 }
 
-func (s *Service) GetById(ctx context.Context, id int64) (domain.User, error) {
+func (s *Service) GetById(ctx context.Context, id int64) (_ domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetById", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
 	if id == 0 {
 		return domain.User{}, ErrInvalidId
 	}
@@ -70,3 +109,158 @@ func (s *Service) GetById(ctx context.Context, id int64) (domain.User, error) {
 
 	return user, nil
 }
+
+// List returns every user ordered by id.
+func (s *Service) List(ctx context.Context) (_ []domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.List")
+	defer tracing.End(span, &err)
+
+	users, err := s.usersRepo.List(ctx)
+	if err != nil {
+		return []domain.User{}, err
+	}
+
+	return users, nil
+}
+
+func (s *Service) ResetPassword(ctx context.Context, id int64, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.ResetPassword", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	if len(password) == 0 {
+		return ErrInvalidPassword
+	}
+
+	if err := s.usersRepo.UpdatePassword(ctx, id, password); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Password reset")
+	return nil
+}
+
+// Lock locks the account of a user, who can't log in nor refresh tokens until
+// it is unlocked. Locking a locked account keeps the time it was locked at.
+func (s *Service) Lock(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Lock", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	user, err := s.usersRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Locked() {
+		return nil
+	}
+
+	lockedAt := time.Now()
+	if err := s.usersRepo.SetLocked(ctx, id, &lockedAt); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("User locked")
+	return nil
+}
+
+func (s *Service) Unlock(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Unlock", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	if err := s.usersRepo.SetLocked(ctx, id, nil); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("User unlocked")
+	return nil
+}
+
+// Search returns the users whose name or email contains the query, ignoring
+// case, ordered by id. Up to DefaultSearchLimit users are returned if limit
+// is 0.
+func (s *Service) Search(ctx context.Context, query string, limit, offset int) (_ []domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Search")
+	defer tracing.End(span, &err)
+
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit < 0 || limit > MaxSearchLimit {
+		return []domain.User{}, ErrInvalidLimit
+	}
+	if offset < 0 {
+		return []domain.User{}, ErrInvalidOffset
+	}
+
+	return s.usersRepo.Search(ctx, query, limit, offset)
+}
+
+func (s *Service) SetRole(ctx context.Context, id int64, role domain.Role) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.SetRole", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+
+	if err := s.usersRepo.SetRole(ctx, id, role); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Role changed", zap.String("role", string(role)))
+	return nil
+}
+
+// RevokeSessions logs a user out, refusing the tokens issued until now.
+func (s *Service) RevokeSessions(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "user.Service.RevokeSessions", attribute.Int64("user.id", id))
+	defer tracing.End(span, &err)
+
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	if err := s.usersRepo.RevokeSessions(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Sessions revoked")
+	return nil
+}
+
+// CheckSession fails with domain.ErrAccountLocked if the account of the user
+// is locked, and with domain.ErrSessionRevoked if their sessions were revoked
+// after the token was issued. Tokens are issued at second precision, so those
+// issued within the second sessions were revoked in are refused too.
+func (s *Service) CheckSession(ctx context.Context, userId int64, issuedAt time.Time) error {
+	user, err := s.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if user.Locked() {
+		return domain.ErrAccountLocked
+	}
+
+	if user.SessionsRevokedAt != nil && !issuedAt.After(user.SessionsRevokedAt.Truncate(time.Second)) {
+		return domain.ErrSessionRevoked
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/krau5/hyper-todo/domain"
 	"github.com/krau5/hyper-todo/user/mocks"
@@ -96,3 +97,149 @@ func TestGetById(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestResetPassword(t *testing.T) {
+	usersRepo := mocks.NewUsersRepository(t)
+	service := NewService(usersRepo)
+
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("throws an error if id is invalid", func(t *testing.T) {
+		err := service.ResetPassword(ctx, 0, "password123")
+		assert.EqualError(t, err, ErrInvalidId.Error())
+	})
+
+	t.Run("throws an error if password is invalid", func(t *testing.T) {
+		err := service.ResetPassword(ctx, userId, "")
+		assert.EqualError(t, err, ErrInvalidPassword.Error())
+	})
+
+	t.Run("updates the password", func(t *testing.T) {
+		usersRepo.On("UpdatePassword", mock.Anything, userId, "password123").Return(nil)
+
+		err := service.ResetPassword(ctx, userId, "password123")
+		assert.Nil(t, err)
+	})
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("throws an error if id is invalid", func(t *testing.T) {
+		service := NewService(mocks.NewUsersRepository(t))
+
+		err := service.Lock(ctx, 0)
+		assert.EqualError(t, err, ErrInvalidId.Error())
+	})
+
+	t.Run("locks the account at the current time", func(t *testing.T) {
+		usersRepo := mocks.NewUsersRepository(t)
+		service := NewService(usersRepo)
+
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId}, nil)
+		usersRepo.On("SetLocked", mock.Anything, userId, mock.MatchedBy(func(lockedAt *time.Time) bool {
+			return lockedAt != nil && time.Since(*lockedAt) < time.Minute
+		})).Return(nil)
+
+		err := service.Lock(ctx, userId)
+		assert.Nil(t, err)
+	})
+
+	t.Run("keeps the time locked accounts were locked at", func(t *testing.T) {
+		usersRepo := mocks.NewUsersRepository(t)
+		service := NewService(usersRepo)
+
+		lockedAt := time.Now().Add(-time.Hour)
+		usersRepo.On("GetById", mock.Anything, userId).Return(domain.User{ID: userId, LockedAt: &lockedAt}, nil)
+
+		err := service.Lock(ctx, userId)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unlocks the account", func(t *testing.T) {
+		usersRepo := mocks.NewUsersRepository(t)
+		service := NewService(usersRepo)
+
+		usersRepo.On("SetLocked", mock.Anything, userId, (*time.Time)(nil)).Return(nil)
+
+		err := service.Unlock(ctx, userId)
+		assert.Nil(t, err)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("searches up to the default limit", func(t *testing.T) {
+		usersRepo := mocks.NewUsersRepository(t)
+		service := NewService(usersRepo)
+
+		users := []domain.User{{ID: 1, Name: "John"}}
+		usersRepo.On("Search", mock.Anything, "john", DefaultSearchLimit, 0).Return(users, nil)
+
+		found, err := service.Search(ctx, "john", 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, users, found)
+	})
+
+	t.Run("throws an error if limit or offset is invalid", func(t *testing.T) {
+		service := NewService(mocks.NewUsersRepository(t))
+
+		_, err := service.Search(ctx, "", MaxSearchLimit+1, 0)
+		assert.EqualError(t, err, ErrInvalidLimit.Error())
+
+		_, err = service.Search(ctx, "", 10, -1)
+		assert.EqualError(t, err, ErrInvalidOffset.Error())
+	})
+}
+
+func TestSetRole(t *testing.T) {
+	usersRepo := mocks.NewUsersRepository(t)
+	service := NewService(usersRepo)
+
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("throws an error if role is invalid", func(t *testing.T) {
+		err := service.SetRole(ctx, userId, "root")
+		assert.EqualError(t, err, ErrInvalidRole.Error())
+	})
+
+	t.Run("sets the role", func(t *testing.T) {
+		usersRepo.On("SetRole", mock.Anything, userId, domain.RoleSupport).Return(nil)
+
+		err := service.SetRole(ctx, userId, domain.RoleSupport)
+		assert.Nil(t, err)
+	})
+}
+
+func TestCheckSession(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+	revokedAt := time.Date(2026, time.October, 19, 12, 0, 0, 500_000_000, time.UTC)
+	lockedAt := revokedAt
+
+	for _, tc := range []struct {
+		name     string
+		user     domain.User
+		issuedAt time.Time
+		err      error
+	}{
+		{"accepts tokens of active users", domain.User{ID: userId}, revokedAt, nil},
+		{"refuses tokens of locked users", domain.User{ID: userId, LockedAt: &lockedAt}, revokedAt.Add(time.Hour), domain.ErrAccountLocked},
+		{"refuses tokens issued before sessions were revoked", domain.User{ID: userId, SessionsRevokedAt: &revokedAt}, revokedAt.Add(-time.Hour), domain.ErrSessionRevoked},
+		{"refuses tokens issued within the second sessions were revoked in", domain.User{ID: userId, SessionsRevokedAt: &revokedAt}, revokedAt.Truncate(time.Second), domain.ErrSessionRevoked},
+		{"accepts tokens issued after sessions were revoked", domain.User{ID: userId, SessionsRevokedAt: &revokedAt}, revokedAt.Add(time.Second).Truncate(time.Second), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			usersRepo := mocks.NewUsersRepository(t)
+			service := NewService(usersRepo)
+			usersRepo.On("GetById", mock.Anything, userId).Return(tc.user, nil)
+
+			err := service.CheckSession(ctx, userId, tc.issuedAt)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
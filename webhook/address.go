@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// nonPublicNetworks are the ranges not covered by the net.IP predicates that
+// don't reach the public internet either.
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // This network
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved, including broadcast
+	mustParseCIDR("64:ff9b::/96"),  // NAT64, which may map to private IPv4 addresses
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
+
+// isPublic reports whether the address is on the public internet, so that
+// webhooks can't be used to reach the server itself, its cloud metadata
+// endpoint or the private network it runs in.
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupIP resolves a host, which may be an IP address.
+func lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// checkHost fails with ErrNonPublicURL unless the host only resolves to
+// public addresses.
+func checkHost(ctx context.Context, lookup func(context.Context, string) ([]net.IP, error), host string) error {
+	ips, err := lookup(ctx, host)
+	if err != nil || len(ips) == 0 {
+		return ErrNonPublicURL
+	}
+
+	for _, ip := range ips {
+		if !isPublic(ip) {
+			return ErrNonPublicURL
+		}
+	}
+
+	return nil
+}
+
+// refuseNonPublic is a net.Dialer control function refusing connections to
+// non-public addresses. Checking the address being dialed rather than the
+// URL also covers hosts whose DNS records changed after registration.
+func refuseNonPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+		return fmt.Errorf("%w: refusing to connect to %s", ErrNonPublicURL, host)
+	}
+
+	return nil
+}
+
+// newHTTPClient returns the client sending deliveries. It only connects to
+// public addresses, ignores proxy settings, which would dial on its behalf,
+// and doesn't follow redirects, which could lead to internal URLs.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   refuseNonPublic,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/internal/metrics"
+)
+
+// Headers sent along with every delivery.
+const (
+	HeaderEvent     = "X-Hyper-Todo-Event"
+	HeaderDelivery  = "X-Hyper-Todo-Delivery"
+	HeaderTimestamp = "X-Hyper-Todo-Timestamp"
+	HeaderSignature = "X-Hyper-Todo-Signature"
+)
+
+// DispatcherConfig defines polling and retry settings of the Dispatcher.
+type DispatcherConfig struct {
+	PollInterval time.Duration // How often the queue is polled for due deliveries
+	BatchSize    int           // Maximum number of deliveries claimed per poll
+	Lease        time.Duration // How long a claimed delivery is hidden from other dispatchers
+	Timeout      time.Duration // HTTP timeout of a single attempt
+	MaxAttempts  int           // Attempts after which a delivery is moved to the dead state
+	BaseBackoff  time.Duration // Delay before the first retry, doubled on every further attempt
+	MaxBackoff   time.Duration // Upper bound of the retry delay
+}
+
+// DefaultDispatcherConfig returns the settings used by the API server.
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		PollInterval: 5 * time.Second,
+		BatchSize:    20,
+		Lease:        time.Minute,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+	}
+}
+
+// Dispatcher sends queued deliveries to webhook endpoints. Deliveries are
+// claimed with row locks, so several dispatchers can share one queue.
+type Dispatcher struct {
+	webhooksRepo   WebhooksRepository
+	deliveriesRepo DeliveriesRepository
+	client         *http.Client
+	config         DispatcherConfig
+	now            func() time.Time
+}
+
+func NewDispatcher(webhooksRepo WebhooksRepository, deliveriesRepo DeliveriesRepository, config DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		webhooksRepo:   webhooksRepo,
+		deliveriesRepo: deliveriesRepo,
+		client:         newHTTPClient(config.Timeout),
+		config:         config,
+		now:            time.Now,
+	}
+}
+
+// Run polls the queue until the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		err := d.DispatchDue(ctx)
+		metrics.ObserveJob("webhook_deliveries", start, err)
+		if err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims and sends one batch of due deliveries.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	deliveries, err := d.deliveriesRepo.ClaimDue(ctx, d.config.BatchSize, d.config.Lease)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		delivery = d.attempt(ctx, delivery)
+
+		if err := d.deliveriesRepo.Update(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	delivery.Attempts++
+
+	webhook, err := d.webhooksRepo.GetById(ctx, delivery.WebhookId)
+	if err != nil {
+		delivery.Status = domain.DeliveryDead
+		delivery.LastError = fmt.Sprintf("failed to load webhook: %v", err)
+		return delivery
+	}
+
+	if !webhook.Active {
+		delivery.Status = domain.DeliveryDead
+		delivery.LastError = "webhook is inactive"
+		return delivery
+	}
+
+	status, err := d.send(ctx, webhook, delivery)
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		now := d.now()
+		delivery.Status = domain.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = domain.DeliveryDead
+		return delivery
+	}
+
+	delivery.Status = domain.DeliveryRetrying
+	delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.Attempts))
+	return delivery
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hyper-todo-webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+
+	return delay
+}
+
+// Sign returns the value of the signature header for a payload. Receivers
+// verify deliveries by computing the HMAC-SHA256 of "<timestamp>.<body>"
+// with the webhook secret and comparing it with the header.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
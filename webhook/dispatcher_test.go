@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDispatchDue_SignsAndDelivers(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotSignature string
+		gotTimestamp string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotTimestamp = r.Header.Get(HeaderTimestamp)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, webhooksRepo, deliveriesRepo := setupDispatcherTest(t)
+	delivery := domain.WebhookDelivery{ID: 1, WebhookId: 1, EventType: domain.EventTaskCreated, Payload: []byte(`{"type":"task.created"}`)}
+
+	deliveriesRepo.On("ClaimDue", mock.Anything, 20, time.Minute).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhooksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, URL: server.URL, Secret: "secret", Active: true}, nil)
+	deliveriesRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliverySucceeded && d.Attempts == 1 && d.ResponseStatus == http.StatusNoContent && d.DeliveredAt != nil
+	})).Return(nil)
+
+	err := dispatcher.DispatchDue(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, string(delivery.Payload), string(gotBody))
+
+	timestamp, _ := strconv.ParseInt(gotTimestamp, 10, 64)
+	assert.Equal(t, Sign("secret", timestamp, delivery.Payload), gotSignature)
+}
+
+func TestDispatchDue_SchedulesRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dispatcher, webhooksRepo, deliveriesRepo := setupDispatcherTest(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	delivery := domain.WebhookDelivery{ID: 1, WebhookId: 1, Attempts: 1, Payload: []byte(`{}`)}
+
+	deliveriesRepo.On("ClaimDue", mock.Anything, 20, time.Minute).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhooksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, URL: server.URL, Active: true}, nil)
+	deliveriesRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliveryRetrying &&
+			d.Attempts == 2 &&
+			d.ResponseStatus == http.StatusInternalServerError &&
+			d.NextAttemptAt.Equal(now.Add(time.Minute))
+	})).Return(nil)
+
+	err := dispatcher.DispatchDue(context.TODO())
+	assert.Nil(t, err)
+}
+
+func TestDispatchDue_MovesToDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	dispatcher, webhooksRepo, deliveriesRepo := setupDispatcherTest(t)
+	delivery := domain.WebhookDelivery{ID: 1, WebhookId: 1, Attempts: 2, Payload: []byte(`{}`)}
+
+	deliveriesRepo.On("ClaimDue", mock.Anything, 20, time.Minute).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhooksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, URL: server.URL, Active: true}, nil)
+	deliveriesRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliveryDead && d.Attempts == 3 && d.LastError != ""
+	})).Return(nil)
+
+	err := dispatcher.DispatchDue(context.TODO())
+	assert.Nil(t, err)
+}
+
+func TestDispatchDue_RefusesNonPublicAddresses(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	dispatcher, webhooksRepo, deliveriesRepo := setupDispatcherTest(t)
+	dispatcher.client = newHTTPClient(time.Second)
+	delivery := domain.WebhookDelivery{ID: 1, WebhookId: 1, Payload: []byte(`{}`)}
+
+	deliveriesRepo.On("ClaimDue", mock.Anything, 20, time.Minute).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhooksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, URL: server.URL, Active: true}, nil)
+	deliveriesRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliveryRetrying && strings.Contains(d.LastError, ErrNonPublicURL.Error())
+	})).Return(nil)
+
+	err := dispatcher.DispatchDue(context.TODO())
+	assert.Nil(t, err)
+	assert.False(t, requested)
+}
+
+func TestDispatchDue_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	dispatcher, webhooksRepo, deliveriesRepo := setupDispatcherTest(t)
+	delivery := domain.WebhookDelivery{ID: 1, WebhookId: 1, Payload: []byte(`{}`)}
+
+	deliveriesRepo.On("ClaimDue", mock.Anything, 20, time.Minute).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhooksRepo.On("GetById", mock.Anything, int64(1)).Return(domain.Webhook{ID: 1, URL: server.URL, Active: true}, nil)
+	deliveriesRepo.On("Update", mock.Anything, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliveryRetrying && d.ResponseStatus == http.StatusTemporaryRedirect
+	})).Return(nil)
+
+	err := dispatcher.DispatchDue(context.TODO())
+	assert.Nil(t, err)
+	assert.False(t, redirected)
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher, _, _ := setupDispatcherTest(t)
+
+	assert.Equal(t, 30*time.Second, dispatcher.backoff(1))
+	assert.Equal(t, time.Minute, dispatcher.backoff(2))
+	assert.Equal(t, 2*time.Minute, dispatcher.backoff(3))
+	assert.Equal(t, time.Hour, dispatcher.backoff(20))
+}
+
+func setupDispatcherTest(t *testing.T) (*Dispatcher, *mocks.WebhooksRepository, *mocks.DeliveriesRepository) {
+	webhooksRepo := mocks.NewWebhooksRepository(t)
+	deliveriesRepo := mocks.NewDeliveriesRepository(t)
+	dispatcher := NewDispatcher(webhooksRepo, deliveriesRepo, DispatcherConfig{
+		PollInterval: time.Second,
+		BatchSize:    20,
+		Lease:        time.Minute,
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   time.Hour,
+	})
+	// Test servers listen on loopback, which the dispatcher refuses to dial.
+	dispatcher.client.Transport = http.DefaultTransport
+
+	return dispatcher, webhooksRepo, deliveriesRepo
+}
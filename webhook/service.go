@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+)
+
+//go:generate mockery --name WebhooksRepository
+type WebhooksRepository interface {
+	Create(ctx context.Context, url, secret string, eventTypes []domain.EventType, userId int64) (domain.Webhook, error)
+	GetById(context.Context, int64) (domain.Webhook, error)
+	GetByUser(context.Context, int64) ([]domain.Webhook, error)
+	UpdateById(context.Context, int64, domain.UpdateWebhookData) (domain.Webhook, error)
+	DeleteById(context.Context, int64) error
+}
+
+//go:generate mockery --name DeliveriesRepository
+type DeliveriesRepository interface {
+	Create(ctx context.Context, webhookId int64, eventType domain.EventType, payload []byte) (domain.WebhookDelivery, error)
+	GetById(context.Context, int64) (domain.WebhookDelivery, error)
+	GetByWebhook(ctx context.Context, webhookId int64, limit int) ([]domain.WebhookDelivery, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	Update(context.Context, domain.WebhookDelivery) error
+}
+
+type Service struct {
+	webhooksRepo   WebhooksRepository
+	deliveriesRepo DeliveriesRepository
+	lookupIP       func(ctx context.Context, host string) ([]net.IP, error)
+}
+
+// DeliveriesLimit is the number of most recent deliveries returned by GetDeliveries.
+const DeliveriesLimit = 100
+
+var (
+	ErrInvalidId         = errors.New("id is missing or empty")
+	ErrInvalidUserId     = errors.New("userId is missing or empty")
+	ErrInvalidURL        = errors.New("url must be an absolute http or https URL")
+	ErrNonPublicURL      = errors.New("url must resolve to public internet addresses only")
+	ErrInvalidEventType  = errors.New("unknown event type")
+	ErrDeliveryMismatch  = errors.New("delivery does not belong to the webhook")
+	ErrFailedToSerialize = errors.New("failed to serialize event payload")
+)
+
+// EventTypes lists the event types webhooks can subscribe to.
+var EventTypes = []domain.EventType{
+	domain.EventTaskCreated,
+	domain.EventTaskUpdated,
+	domain.EventTaskDeleted,
+	domain.EventTaskReminder,
+}
+
+// Payload is the JSON body sent to webhook endpoints.
+type Payload struct {
+	Type       domain.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Task       domain.Task      `json:"task"`
+}
+
+func NewService(webhooksRepo WebhooksRepository, deliveriesRepo DeliveriesRepository) *Service {
+	return &Service{
+		webhooksRepo:   webhooksRepo,
+		deliveriesRepo: deliveriesRepo,
+		lookupIP:       lookupIP,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, rawURL string, eventTypes []domain.EventType, userId int64) (domain.Webhook, error) {
+	if userId == 0 {
+		return domain.Webhook{}, ErrInvalidUserId
+	}
+
+	if err := s.validateURL(ctx, rawURL); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if err := validateEventTypes(eventTypes); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return s.webhooksRepo.Create(ctx, rawURL, secret, eventTypes, userId)
+}
+
+func (s *Service) GetById(ctx context.Context, id int64) (domain.Webhook, error) {
+	if id == 0 {
+		return domain.Webhook{}, ErrInvalidId
+	}
+
+	return s.webhooksRepo.GetById(ctx, id)
+}
+
+func (s *Service) GetByUser(ctx context.Context, userId int64) ([]domain.Webhook, error) {
+	if userId == 0 {
+		return []domain.Webhook{}, ErrInvalidUserId
+	}
+
+	return s.webhooksRepo.GetByUser(ctx, userId)
+}
+
+func (s *Service) UpdateById(ctx context.Context, id int64, data domain.UpdateWebhookData) (domain.Webhook, error) {
+	if id == 0 {
+		return domain.Webhook{}, ErrInvalidId
+	}
+
+	if data.URL != nil {
+		if err := s.validateURL(ctx, *data.URL); err != nil {
+			return domain.Webhook{}, err
+		}
+	}
+
+	if data.EventTypes != nil {
+		if err := validateEventTypes(*data.EventTypes); err != nil {
+			return domain.Webhook{}, err
+		}
+	}
+
+	return s.webhooksRepo.UpdateById(ctx, id, data)
+}
+
+func (s *Service) DeleteById(ctx context.Context, id int64) error {
+	if id == 0 {
+		return ErrInvalidId
+	}
+
+	return s.webhooksRepo.DeleteById(ctx, id)
+}
+
+func (s *Service) GetDeliveries(ctx context.Context, webhookId int64) ([]domain.WebhookDelivery, error) {
+	if webhookId == 0 {
+		return []domain.WebhookDelivery{}, ErrInvalidId
+	}
+
+	return s.deliveriesRepo.GetByWebhook(ctx, webhookId, DeliveriesLimit)
+}
+
+// Redeliver queues a fresh copy of a previous delivery, regardless of its status.
+func (s *Service) Redeliver(ctx context.Context, webhookId, deliveryId int64) (domain.WebhookDelivery, error) {
+	if webhookId == 0 || deliveryId == 0 {
+		return domain.WebhookDelivery{}, ErrInvalidId
+	}
+
+	delivery, err := s.deliveriesRepo.GetById(ctx, deliveryId)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	if delivery.WebhookId != webhookId {
+		return domain.WebhookDelivery{}, ErrDeliveryMismatch
+	}
+
+	return s.deliveriesRepo.Create(ctx, webhookId, delivery.EventType, delivery.Payload)
+}
+
+// HandleEvent queues a delivery for every active webhook of the event owner
+// subscribed to the event type. Deliveries are sent later by the Dispatcher.
+func (s *Service) HandleEvent(ctx context.Context, event domain.Event) error {
+	webhooks, err := s.webhooksRepo.GetByUser(ctx, event.UserId)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, w := range webhooks {
+		if !w.Active || !w.Subscribes(event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(Payload{Type: event.Type, OccurredAt: event.OccurredAt, Task: event.Task})
+			if err != nil {
+				return ErrFailedToSerialize
+			}
+		}
+
+		if _, err := s.deliveriesRepo.Create(ctx, w.ID, event.Type, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks that the URL is an http or https one whose host
+// resolves to public addresses. The Dispatcher checks the addresses again
+// when connecting, since DNS records may change.
+func (s *Service) validateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidURL
+	}
+
+	return checkHost(ctx, s.lookupIP, u.Hostname())
+}
+
+func validateEventTypes(eventTypes []domain.EventType) error {
+	for _, t := range eventTypes {
+		known := false
+		for _, k := range EventTypes {
+			if t == k {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return ErrInvalidEventType
+		}
+	}
+
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
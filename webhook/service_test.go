@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/krau5/hyper-todo/domain"
+	"github.com/krau5/hyper-todo/events"
+	"github.com/krau5/hyper-todo/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	var userId int64 = 1
+
+	t.Run("throws an error if url is invalid", func(t *testing.T) {
+		service, _, _ := setupTest(t)
+
+		for _, rawURL := range []string{"", "example.com/hook", "ftp://example.com/hook", "https://"} {
+			_, err := service.Create(ctx, rawURL, nil, userId)
+			assert.EqualError(t, err, ErrInvalidURL.Error(), rawURL)
+		}
+	})
+
+	t.Run("throws an error if url doesn't resolve to public addresses", func(t *testing.T) {
+		service, _, _ := setupTest(t)
+
+		for _, rawURL := range []string{
+			"http://localhost:8080/hook",
+			"http://127.0.0.1/hook",
+			"http://[::1]/hook",
+			"http://0.0.0.0/hook",
+			"http://169.254.169.254/latest/meta-data",
+			"http://metadata.internal/hook",
+			"https://intranet.example/hook",
+			"https://172.16.0.1/hook",
+			"https://[fd00::1]/hook",
+			"https://half-private.test/hook",
+			"https://mapped-loopback.io/hook",
+			"https://unknown.invalid/hook",
+		} {
+			_, err := service.Create(ctx, rawURL, nil, userId)
+			assert.ErrorIs(t, err, ErrNonPublicURL, rawURL)
+		}
+	})
+
+	t.Run("throws an error if event type is unknown", func(t *testing.T) {
+		service, _, _ := setupTest(t)
+
+		_, err := service.Create(ctx, "https://example.com/hook", []domain.EventType{"task.exploded"}, userId)
+		assert.EqualError(t, err, ErrInvalidEventType.Error())
+	})
+
+	t.Run("generates a secret for the webhook", func(t *testing.T) {
+		service, webhooksRepo, _ := setupTest(t)
+		eventTypes := []domain.EventType{domain.EventTaskCreated}
+
+		webhooksRepo.On("Create", mock.Anything, "https://example.com/hook", mock.MatchedBy(func(secret string) bool {
+			return len(secret) == 64
+		}), eventTypes, userId).Return(domain.Webhook{ID: 1}, nil)
+
+		webhook, err := service.Create(ctx, "https://example.com/hook", eventTypes, userId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), webhook.ID)
+	})
+}
+
+func TestHandleEvent(t *testing.T) {
+	ctx := context.TODO()
+	task := domain.Task{ID: 7, Name: "eat", UserId: 1}
+	event := domain.NewTaskEvent(domain.EventTaskUpdated, task)
+
+	t.Run("queues deliveries for active subscribed webhooks only", func(t *testing.T) {
+		service, webhooksRepo, deliveriesRepo := setupTest(t)
+
+		webhooksRepo.On("GetByUser", mock.Anything, task.UserId).Return([]domain.Webhook{
+			{ID: 1, Active: true},
+			{ID: 2, Active: true, EventTypes: []domain.EventType{domain.EventTaskUpdated}},
+			{ID: 3, Active: true, EventTypes: []domain.EventType{domain.EventTaskCreated}},
+			{ID: 4, Active: false},
+		}, nil)
+
+		var payload Payload
+		deliveriesRepo.On("Create", mock.Anything, int64(1), domain.EventTaskUpdated, mock.Anything).
+			Run(func(args mock.Arguments) {
+				json.Unmarshal(args.Get(3).([]byte), &payload)
+			}).
+			Return(domain.WebhookDelivery{}, nil)
+		deliveriesRepo.On("Create", mock.Anything, int64(2), domain.EventTaskUpdated, mock.Anything).Return(domain.WebhookDelivery{}, nil)
+
+		err := service.HandleEvent(ctx, event)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.EventTaskUpdated, payload.Type)
+		assert.Equal(t, task.ID, payload.Task.ID)
+	})
+
+	t.Run("writes a delivery for every event of a burst", func(t *testing.T) {
+		service, webhooksRepo, deliveriesRepo := setupTest(t)
+		webhooksRepo.On("GetByUser", mock.Anything, task.UserId).Return([]domain.Webhook{{ID: 1, Active: true}}, nil)
+		deliveriesRepo.On("Create", mock.Anything, int64(1), domain.EventTaskUpdated, mock.Anything).Return(domain.WebhookDelivery{}, nil)
+
+		bus := events.NewBus()
+		bus.Subscribe(func(ctx context.Context, event domain.Event) {
+			assert.Nil(t, service.HandleEvent(ctx, event))
+		})
+
+		const burst = 5000
+		for i := 0; i < burst; i++ {
+			bus.Publish(ctx, event)
+		}
+
+		deliveriesRepo.AssertNumberOfCalls(t, "Create", burst)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	ctx := context.TODO()
+	delivery := domain.WebhookDelivery{
+		ID:        5,
+		WebhookId: 1,
+		EventType: domain.EventTaskCreated,
+		Payload:   []byte(`{"type":"task.created"}`),
+		Status:    domain.DeliveryDead,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("throws an error if the delivery belongs to another webhook", func(t *testing.T) {
+		service, _, deliveriesRepo := setupTest(t)
+		deliveriesRepo.On("GetById", mock.Anything, delivery.ID).Return(delivery, nil)
+
+		_, err := service.Redeliver(ctx, 2, delivery.ID)
+		assert.EqualError(t, err, ErrDeliveryMismatch.Error())
+	})
+
+	t.Run("queues a copy of the delivery", func(t *testing.T) {
+		service, _, deliveriesRepo := setupTest(t)
+		deliveriesRepo.On("GetById", mock.Anything, delivery.ID).Return(delivery, nil)
+		deliveriesRepo.On("Create", mock.Anything, delivery.WebhookId, delivery.EventType, []byte(delivery.Payload)).
+			Return(domain.WebhookDelivery{ID: 6, Status: domain.DeliveryPending}, nil)
+
+		queued, err := service.Redeliver(ctx, delivery.WebhookId, delivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), queued.ID)
+	})
+}
+
+func setupTest(t *testing.T) (*Service, *mocks.WebhooksRepository, *mocks.DeliveriesRepository) {
+	webhooksRepo := mocks.NewWebhooksRepository(t)
+	deliveriesRepo := mocks.NewDeliveriesRepository(t)
+	service := NewService(webhooksRepo, deliveriesRepo)
+	service.lookupIP = fakeLookupIP
+
+	return service, webhooksRepo, deliveriesRepo
+}
+
+// fakeLookupIP resolves the hosts of the tests without DNS.
+func fakeLookupIP(_ context.Context, host string) ([]net.IP, error) {
+	hosts := map[string][]string{
+		"example.com":        {"93.184.215.14", "2606:2800:21f:cb07:6820:80da:af6b:8b2c"},
+		"localhost":          {"127.0.0.1", "::1"},
+		"metadata.internal":  {"169.254.169.254"},
+		"intranet.example":   {"10.0.0.7"},
+		"half-private.test":  {"93.184.215.14", "192.168.1.1"},
+		"mapped-loopback.io": {"::ffff:127.0.0.1"},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	addresses, ok := hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	ips := make([]net.IP, len(addresses))
+	for i, address := range addresses {
+		ips[i] = net.ParseIP(address)
+	}
+
+	return ips, nil
+}